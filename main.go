@@ -3,10 +3,18 @@ package main
 import (
 	"WifiSOS/utils"
 	"WifiSOS/wifi"
+	"bufio"
+	"crypto/ecdh"
 	"fmt"
 	"github.com/akamensky/argparse"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func main() {
@@ -17,6 +25,11 @@ func main() {
 	scanCommand := parser.NewCommand("scan", "扫描附近的WiFi网络")
 	savedCommand := parser.NewCommand("saved", "获取已保存的WiFi网络及密码")
 	bruteCommand := parser.NewCommand("brute", "对指定WiFi进行密码爆破")
+	surveyCommand := parser.NewCommand("survey", "按位置进行WiFi现场勘测并生成HTML报告")
+	importQRCommand := parser.NewCommand("import-qr", "识别图片中的WiFi二维码，导出为配置文件或直接连接")
+	decryptCommand := parser.NewCommand("decrypt", "解密加密保存的结果文件")
+	keygenCommand := parser.NewCommand("keygen", "生成用于加密结果文件的X25519密钥对")
+	reportCommand := parser.NewCommand("report", "根据保存的结果文件重新生成报告，无需重新扫描")
 
 	// 爆破命令的参数
 	ssid := bruteCommand.String("s", "ssid", &argparse.Options{
@@ -32,6 +45,269 @@ func main() {
 		Help:     "最大尝试次数",
 		Default:  "0",
 	})
+	bruteReveal := bruteCommand.Flag("", "reveal", &argparse.Options{
+		Help: "显示完整密码，默认只显示首尾字符和长度",
+	})
+	bruteFormat := bruteCommand.Selector("", "format", wifi.OutputFormats, &argparse.Options{
+		Required: false,
+		Help:     "输出格式: text, json, csv, yaml, markdown, html，结构化格式使用带版本号的架构，html为带图表的自包含报告",
+		Default:  wifi.OutputText,
+	})
+	brutePlaintext := bruteCommand.Flag("", "plaintext", &argparse.Options{
+		Help: "以明文保存结果文件，默认使用口令加密",
+	})
+	bruteRecipient := bruteCommand.String("", "recipient", &argparse.Options{
+		Required: false,
+		Help:     "使用接收者的公钥（keygen生成的.pub文件或公钥文本）加密结果文件，无需输入口令",
+	})
+
+	bruteResults := addResultFlags(bruteCommand)
+
+	// 扫描命令的参数
+	watch := scanCommand.Flag("w", "watch", &argparse.Options{
+		Help: "持续监测模式，显示每个BSSID的信号变化",
+	})
+	spectrum := scanCommand.Flag("", "spectrum", &argparse.Options{
+		Help: "以频谱图形式显示各频段的信道占用",
+	})
+	rulesPath := scanCommand.String("", "rules", &argparse.Options{
+		Required: false,
+		Help:     "自定义网络分类规则文件（JSON），优先于内置规则",
+	})
+	classFilter := scanCommand.String("", "class", &argparse.Options{
+		Required: false,
+		Help:     "只显示指定分类的网络，多个分类用逗号分隔，如 mobile-hotspot,iot-setup,other",
+	})
+	scanFormat := scanCommand.Selector("", "format", wifi.OutputFormats, &argparse.Options{
+		Required: false,
+		Help:     "输出格式: text, json, csv, yaml, markdown, html，结构化格式使用带版本号的架构，html为带图表的自包含报告",
+		Default:  wifi.OutputText,
+	})
+	watchInterval := scanCommand.Int("i", "interval", &argparse.Options{
+		Required: false,
+		Help:     "监测模式下的扫描间隔（秒）",
+		Default:  2,
+	})
+	watchRounds := scanCommand.Int("r", "rounds", &argparse.Options{
+		Required: false,
+		Help:     "监测模式下的扫描轮数，0表示持续到Ctrl+C",
+		Default:  0,
+	})
+	historySize := scanCommand.Int("k", "keep", &argparse.Options{
+		Required: false,
+		Help:     "监测模式下每个BSSID保留的采样数量",
+		Default:  wifi.DefaultHistorySize,
+	})
+
+	scanResults := addResultFlags(scanCommand)
+
+	// 已保存网络命令的参数
+	fromDir := savedCommand.String("", "from-dir", &argparse.Options{
+		Required: false,
+		Help:     "从目录读取导出的WLAN配置文件XML（netsh wlan export profile key=clear folder=...），可离线审计",
+	})
+
+	savedSource := savedCommand.Selector("", "source", []string{
+		wifi.SourceNetsh, wifi.SourceWindowsXML, wifi.SourceNetworkManager, wifi.SourceWpaSupplicant,
+		wifi.SourceAndroid, wifi.SourceOpenWrt,
+	}, &argparse.Options{
+		Required: false,
+		Help:     "已保存网络的来源: netsh, xml (导出的WLAN配置文件), nm (NetworkManager), wpa (wpa_supplicant), android (WifiConfigStore.xml), openwrt (/etc/config/wireless)，默认根据系统选择",
+	})
+	savedPath := savedCommand.String("p", "path", &argparse.Options{
+		Required: false,
+		Help:     "来源的文件或目录路径，可指向离线拷贝，默认使用系统位置",
+	})
+
+	exportTo := savedCommand.Selector("", "export-to", []string{
+		wifi.ExportNetworkManager, wifi.ExportWpaSupplicant, wifi.ExportWindows, wifi.ExportMobileconfig,
+		wifi.ExportKeePass, wifi.ExportBitwarden, wifi.ExportCSV,
+	}, &argparse.Options{
+		Required: false,
+		Help:     "将已保存的网络导出为其他平台的配置文件: nm, wpa, windows, mobileconfig，或密码管理器导入文件: keepass, bitwarden, csv",
+	})
+	exportDir := savedCommand.String("", "export-dir", &argparse.Options{
+		Required: false,
+		Help:     "导出目录，默认在当前目录下创建 wifi_export_格式_时间 目录",
+	})
+
+	audit := savedCommand.Flag("", "audit", &argparse.Options{
+		Required: false,
+		Help:     "审计已保存网络的密钥强度，按从弱到强排列",
+	})
+	auditWordlist := savedCommand.String("", "wordlist", &argparse.Options{
+		Required: false,
+		Help:     "审计时额外使用的字典文件，每行一个密码",
+	})
+	pbkdf2Rates := savedCommand.String("", "pbkdf2-rates", &argparse.Options{
+		Required: false,
+		Help:     "估算离线破解时间使用的PBKDF2速度，逗号分隔，支持k/M/G后缀，默认 2.5M,250M",
+	})
+	breachCorpus := savedCommand.String("", "breached", &argparse.Options{
+		Required: false,
+		Help:     "审计时查询本地泄露密码SHA-1数据集：按哈希排序的文件或范围文件目录，不访问网络",
+	})
+	hygiene := savedCommand.Flag("", "hygiene", &argparse.Options{
+		Required: false,
+		Help:     "检查已保存网络的配置卫生问题：自动连接的开放网络、WEP/TKIP、同名不同密钥、扫描历史中未出现的配置，并给出修复命令",
+	})
+	scanHistory := savedCommand.String("", "history", &argparse.Options{
+		Required: false,
+		Help:     "卫生检查使用的扫描历史：scan命令保存的结果文件或所在目录（默认当前目录）",
+	})
+	staleDays := savedCommand.Int("", "stale-days", &argparse.Options{
+		Required: false,
+		Help:     "卫生检查中配置文件在多少天内的扫描历史中未出现时视为不再使用",
+		Default:  30,
+	})
+	qrSSID := savedCommand.String("", "qr", &argparse.Options{
+		Required: false,
+		Help:     "为指定SSID或配置文件名称生成连接二维码并在终端中显示",
+	})
+	qrLevel := savedCommand.Selector("", "qr-level", []string{"L", "M", "Q", "H"}, &argparse.Options{
+		Required: false,
+		Help:     "二维码纠错级别",
+		Default:  "M",
+	})
+	qrOut := savedCommand.String("", "qr-out", &argparse.Options{
+		Required: false,
+		Help:     "将二维码写入文件，扩展名为.svg时写入SVG，否则写入PNG",
+	})
+	qrSize := savedCommand.Int("", "qr-size", &argparse.Options{
+		Required: false,
+		Help:     "PNG二维码的边长（像素）",
+		Default:  wifi.DefaultQRImageSize,
+	})
+	snapshotPath := savedCommand.String("", "snapshot", &argparse.Options{
+		Required: false,
+		Help:     "将已保存网络的快照写入JSON文件，快照只包含密钥指纹，可用于 --diff 比较；指纹可被用来离线猜测弱密码，请妥善保管",
+	})
+	diff := savedCommand.Flag("", "diff", &argparse.Options{
+		Required: false,
+		Help:     "比较两个快照文件（saved --diff 旧快照.json 新快照.json），列出新增、删除和变化的配置文件",
+	})
+	oldSnapshot := savedCommand.StringPositional(&argparse.Options{
+		Help: "--diff 比较的旧快照文件",
+	})
+	newSnapshot := savedCommand.StringPositional(&argparse.Options{
+		Help: "--diff 比较的新快照文件",
+	})
+	reveal := savedCommand.Flag("", "reveal", &argparse.Options{
+		Required: false,
+		Help:     "显示完整密钥，默认只显示首尾字符和长度",
+	})
+	savedFormat := savedCommand.Selector("", "format", wifi.OutputFormats, &argparse.Options{
+		Required: false,
+		Help:     "列出网络时的输出格式: text, json, csv, yaml, markdown, html，结构化格式使用带版本号的架构，html为带图表的自包含报告",
+		Default:  wifi.OutputText,
+	})
+	savedPlaintext := savedCommand.Flag("", "plaintext", &argparse.Options{
+		Help: "以明文保存结果文件，默认使用口令加密",
+	})
+	savedRecipient := savedCommand.String("", "recipient", &argparse.Options{
+		Required: false,
+		Help:     "使用接收者的公钥（keygen生成的.pub文件或公钥文本）加密结果文件，无需输入口令",
+	})
+
+	savedResults := addResultFlags(savedCommand)
+
+	// 勘测命令的参数
+	corpSSID := surveyCommand.String("c", "corp", &argparse.Options{
+		Required: false,
+		Help:     "企业SSID，用于检测覆盖盲区",
+	})
+	surveySamples := surveyCommand.Int("n", "samples", &argparse.Options{
+		Required: false,
+		Help:     "每个位置的扫描采样次数",
+		Default:  3,
+	})
+	surveyInterval := surveyCommand.Int("i", "interval", &argparse.Options{
+		Required: false,
+		Help:     "采样间隔（秒）",
+		Default:  2,
+	})
+	coverageThreshold := surveyCommand.Int("t", "threshold", &argparse.Options{
+		Required: false,
+		Help:     "覆盖盲区信号阈值（百分比）",
+		Default:  wifi.DefaultCoverageThreshold,
+	})
+
+	surveyResults := addResultFlags(surveyCommand)
+
+	// 二维码导入命令的参数
+	qrImage := importQRCommand.StringPositional(&argparse.Options{
+		Help: "包含WiFi二维码的PNG、JPEG或GIF图片",
+	})
+	qrExportTo := importQRCommand.Selector("", "export-to", []string{
+		wifi.ExportNetworkManager, wifi.ExportWpaSupplicant, wifi.ExportWindows, wifi.ExportMobileconfig,
+	}, &argparse.Options{
+		Required: false,
+		Help:     "将识别出的网络导出为配置文件: nm, wpa, windows, mobileconfig",
+	})
+	qrExportDir := importQRCommand.String("", "export-dir", &argparse.Options{
+		Required: false,
+		Help:     "导出目录，默认在当前目录下创建 wifi_export_格式_时间 目录",
+	})
+	qrConnect := importQRCommand.Flag("", "connect", &argparse.Options{
+		Required: false,
+		Help:     "保存识别出的网络并立即连接",
+	})
+	qrReveal := importQRCommand.Flag("", "reveal", &argparse.Options{
+		Help: "显示完整密钥，默认只显示首尾字符和长度",
+	})
+	qrFormat := importQRCommand.Selector("", "format", wifi.OutputFormats, &argparse.Options{
+		Required: false,
+		Help:     "输出格式: text, json, csv, yaml, markdown, html，结构化格式使用带版本号的架构，html为带图表的自包含报告",
+		Default:  wifi.OutputText,
+	})
+	qrPlaintext := importQRCommand.Flag("", "plaintext", &argparse.Options{
+		Help: "以明文保存结果文件，默认使用口令加密",
+	})
+	qrRecipient := importQRCommand.String("", "recipient", &argparse.Options{
+		Required: false,
+		Help:     "使用接收者的公钥（keygen生成的.pub文件或公钥文本）加密结果文件，无需输入口令",
+	})
+
+	qrResults := addResultFlags(importQRCommand)
+
+	// 解密命令的参数
+	encryptedFile := decryptCommand.StringPositional(&argparse.Options{
+		Help: "加密的结果文件（.enc）",
+	})
+	identityPath := decryptCommand.String("i", "identity", &argparse.Options{
+		Required: false,
+		Help:     "公钥加密的文件使用的私钥文件（keygen生成的.key文件）",
+	})
+	decryptOut := decryptCommand.String("o", "output", &argparse.Options{
+		Required: false,
+		Help:     "将解密结果写入文件，默认输出到终端",
+	})
+
+	// 密钥生成命令的参数
+	keyName := keygenCommand.StringPositional(&argparse.Options{
+		Help: "密钥文件名称，生成 名称.key（私钥）和 名称.pub（公钥）",
+	})
+
+	// 报告命令的参数
+	reportFile := reportCommand.StringPositional(&argparse.Options{
+		Help: "保存的结果文件（.json或加密的.json.enc），或包含结果文件的目录",
+	})
+	reportFormat := reportCommand.Selector("", "format", wifi.ReportFormats, &argparse.Options{
+		Required: false,
+		Help:     "报告格式: text, md, csv, html，读取目录时csv不可用",
+		Default:  wifi.OutputText,
+	})
+	reportIdentity := reportCommand.String("i", "identity", &argparse.Options{
+		Required: false,
+		Help:     "公钥加密的结果文件使用的私钥文件（keygen生成的.key文件）",
+	})
+	reportReveal := reportCommand.Flag("", "reveal", &argparse.Options{
+		Help: "显示完整密钥，仅当结果保存时也使用了 --reveal 才有原文",
+	})
+	reportOut := reportCommand.String("o", "output", &argparse.Options{
+		Required: false,
+		Help:     "将报告写入文件，默认输出到终端",
+	})
 
 	// 解析命令行参数
 	err := parser.Parse(os.Args)
@@ -42,9 +318,63 @@ func main() {
 
 	// 根据命令执行相应的功能
 	if scanCommand.Happened() {
-		scanWiFi()
+		if err := scanResults.apply(); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		wifi.SetLogOutput(statusOutput(*scanFormat))
+		if *watch {
+			watchWiFi(time.Duration(*watchInterval)*time.Second, *watchRounds, *historySize)
+		} else {
+			scanWiFi(*spectrum, *rulesPath, *classFilter, *scanFormat)
+		}
 	} else if savedCommand.Happened() {
-		getSavedWiFi()
+		if err := savedResults.apply(); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		if *diff {
+			if *oldSnapshot == "" || *newSnapshot == "" {
+				fmt.Print(parser.Usage("请指定旧快照和新快照文件"))
+				return
+			}
+			diffSavedSnapshots(*oldSnapshot, *newSnapshot)
+			return
+		} else if *oldSnapshot != "" {
+			fmt.Print(parser.Usage("快照文件只能与 --diff 一起使用"))
+			return
+		}
+
+		wifi.SetRevealSecrets(*reveal)
+		wifi.SetLogOutput(statusOutput(*savedFormat))
+		source, path := *savedSource, *savedPath
+		if *fromDir != "" {
+			source, path = wifi.SourceWindowsXML, *fromDir
+		}
+		if *snapshotPath != "" {
+			snapshotSavedWiFi(source, path, *snapshotPath)
+			return
+		} else if *qrSSID != "" {
+			qrSavedWiFi(source, path, *qrSSID, *qrLevel, *qrOut, *qrSize)
+			return
+		} else if *exportTo != "" {
+			exportSavedWiFi(source, path, *exportTo, *exportDir)
+			return
+		}
+
+		// 以下模式会保存包含密钥的结果文件，先准备加密
+		encrypter, err := newResultEncrypter(*savedPlaintext, *savedRecipient)
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		if *hygiene {
+			hygieneSavedWiFi(source, path, *scanHistory, *staleDays, encrypter)
+		} else if *audit || *breachCorpus != "" {
+			auditSavedWiFi(source, path, *auditWordlist, *pbkdf2Rates, *breachCorpus, *reveal, encrypter)
+		} else {
+			getSavedWiFi(source, path, *savedFormat, *reveal, encrypter)
+		}
 	} else if bruteCommand.Happened() {
 		// 将最大尝试次数转换为整数
 		max, err := strconv.Atoi(*maxAttempts)
@@ -52,90 +382,844 @@ func main() {
 			fmt.Println("错误: 最大尝试次数必须是一个整数")
 			return
 		}
-		bruteForceWiFi(*ssid, *dictPath, max)
+		if err := bruteResults.apply(); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		// 在开始爆破前输入口令，避免爆破结束后无人输入
+		encrypter, err := newResultEncrypter(*brutePlaintext, *bruteRecipient)
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		wifi.SetRevealSecrets(*bruteReveal)
+		wifi.SetLogOutput(statusOutput(*bruteFormat))
+		bruteForceWiFi(*ssid, *dictPath, max, *bruteFormat, *bruteReveal, encrypter)
+	} else if surveyCommand.Happened() {
+		if err := surveyResults.apply(); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		surveyWiFi(*corpSSID, *surveySamples, time.Duration(*surveyInterval)*time.Second, *coverageThreshold)
+	} else if importQRCommand.Happened() {
+		if *qrImage == "" {
+			fmt.Print(parser.Usage("请指定二维码图片"))
+			return
+		}
+		if err := qrResults.apply(); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		encrypter, err := newResultEncrypter(*qrPlaintext, *qrRecipient)
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		wifi.SetRevealSecrets(*qrReveal)
+		wifi.SetLogOutput(statusOutput(*qrFormat))
+		importQRWiFi(*qrImage, *qrExportTo, *qrExportDir, *qrConnect, *qrFormat, *qrReveal, encrypter)
+	} else if decryptCommand.Happened() {
+		if *encryptedFile == "" {
+			fmt.Print(parser.Usage("请指定要解密的文件"))
+			return
+		}
+		decryptResult(*encryptedFile, *identityPath, *decryptOut)
+	} else if keygenCommand.Happened() {
+		if *keyName == "" {
+			fmt.Print(parser.Usage("请指定密钥文件名称"))
+			return
+		}
+		generateKeyPair(*keyName)
+	} else if reportCommand.Happened() {
+		if *reportFile == "" {
+			fmt.Print(parser.Usage("请指定结果文件"))
+			return
+		}
+		renderReport(*reportFile, *reportFormat, *reportIdentity, *reportReveal, *reportOut)
 	} else {
 		// 如果没有指定命令，显示帮助信息
-		fmt.Print(parser.Usage("请指定一个命令: scan, saved 或 brute"))
+		fmt.Print(parser.Usage("请指定一个命令: scan, saved, brute, survey, import-qr, decrypt, keygen 或 report"))
 	}
 }
 
 // scanWiFi 扫描附近的WiFi网络
-func scanWiFi() {
-	fmt.Println("正在扫描附近的WiFi网络...")
-	
+func scanWiFi(spectrum bool, rulesPath string, classFilter string, format string) {
+	status := statusOutput(format)
+	run := utils.NewEnvelope(os.Args)
+	run.Backend = wifi.ScanBackend
+
+	// 加载网络分类规则
+	var userRules []wifi.ClassRule
+	if rulesPath != "" {
+		rules, err := wifi.LoadClassRules(rulesPath)
+		if err != nil {
+			fmt.Fprintf(status, "加载分类规则失败: %v\n", err)
+			return
+		}
+		userRules = rules
+	}
+	classifier, err := wifi.NewClassifier(userRules)
+	if err != nil {
+		fmt.Fprintf(status, "分类规则无效: %v\n", err)
+		return
+	}
+
+	fmt.Fprintln(status, "正在扫描附近的WiFi网络...")
+
 	// 执行扫描
 	networks, err := wifi.ScanNetworks()
 
 	if err != nil {
-		fmt.Println(fmt.Sprintf("扫描失败: %v", err))
+		fmt.Fprintln(status, fmt.Sprintf("扫描失败: %v", err))
 		return
 	}
 
+	// 对网络进行分类并按分类过滤
+	classifier.ClassifyNetworks(networks)
+	if classFilter != "" {
+		networks = wifi.FilterNetworksByClass(networks, strings.Split(classFilter, ","))
+	}
+
 	// 格式化并显示结果
-	result := wifi.FormatNetworksResult(networks)
+	run.Interface = wifi.InterfaceName()
+	payload := wifi.ScanPayload(networks)
+	result, err := renderResult(run, format, wifi.ScanSchema, payload, func() (string, error) {
+		return wifi.RenderNetworks(networks, format)
+	})
+	if err != nil {
+		fmt.Fprintf(status, "错误: %v\n", err)
+		return
+	}
+	if spectrum && format == wifi.OutputText {
+		result += "\n" + wifi.FormatSpectrum(networks, wifi.DefaultSpectrumWidth, wifi.DefaultSpectrumHeight)
+	}
 	fmt.Println(result)
 
 	// 保存结果
-	filename, err := utils.SaveResult("wifi_scan", result)
+	saveResult(utils.ResultName{Command: "wifi_scan"}, format, run, wifi.ScanSchema, payload, nil)
+}
+
+// watchWiFi 持续扫描并显示每个BSSID的信号变化，结束时导出CSV
+func watchWiFi(interval time.Duration, rounds int, historySize int) {
+	history := wifi.NewSignalHistory(historySize)
+
+	// 捕获Ctrl+C，以便在退出前保存信号序列
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+watchLoop:
+	for round := 1; rounds <= 0 || round <= rounds; round++ {
+		networks, err := wifi.ScanNetworks()
+		if err != nil {
+			fmt.Printf("扫描失败: %v\n", err)
+		} else {
+			history.Record(networks)
+			// 清屏后重新绘制表格
+			fmt.Print("\033[H\033[2J")
+			fmt.Println(wifi.FormatSignalHistory(history))
+			fmt.Printf("第 %d 轮扫描，按Ctrl+C结束监测\n", round)
+		}
+
+		if rounds > 0 && round == rounds {
+			break
+		}
+
+		select {
+		case <-interrupt:
+			fmt.Println("\n监测已停止")
+			break watchLoop
+		case <-time.After(interval):
+		}
+	}
+
+	// 保存信号时间序列
+	if utils.SaveDisabled() {
+		return
+	}
+	name := utils.ResultName{Command: "wifi_watch"}
+	content, err := wifi.FormatSignalHistoryCSV(history)
 	if err != nil {
-		fmt.Println(fmt.Sprintf("保存结果失败: %v", err))
-	} else {
-		fmt.Println(fmt.Sprintf("结果已保存到: %s", filename))
+		fmt.Printf("保存结果失败: %v\n", err)
+		return
 	}
+	filename, err := utils.SaveResultAs(name, "csv", content)
+	if err != nil {
+		fmt.Printf("保存结果失败: %v\n", err)
+		return
+	}
+	fmt.Printf("信号序列已保存到: %s\n", filename)
+	pruneResults(os.Stdout, name, "csv")
 }
 
 // getSavedWiFi 获取已保存的WiFi网络及密码
-func getSavedWiFi() {
-	fmt.Println("正在获取已保存的WiFi网络及密码...")
+func getSavedWiFi(source string, path string, format string, reveal bool, encrypter utils.Encrypter) {
+	status := statusOutput(format)
+	run := utils.NewEnvelope(os.Args)
+	run.Backend = savedBackend(source)
 
-	networks, err := wifi.GetSavedNetworks()
+	if path != "" {
+		fmt.Fprintf(status, "正在从 %s 读取已保存的WiFi网络及密码...\n", path)
+	} else {
+		fmt.Fprintln(status, "正在获取已保存的WiFi网络及密码...")
+	}
+
+	networks, err := wifi.LoadSavedNetworks(source, path)
 	if err != nil {
-		fmt.Printf("获取失败: %v\n", err)
+		fmt.Fprintf(status, "获取失败: %v\n", err)
 		return
 	}
 
 	// 格式化并显示结果
-	result := wifi.FormatSavedNetworksResult(networks)
+	run.Redacted = !reveal
+	payload := wifi.SavedPayload(networks, reveal)
+	result, err := renderResult(run, format, wifi.SavedSchema, payload, func() (string, error) {
+		return wifi.RenderSavedNetworks(networks, format, reveal)
+	})
+	if err != nil {
+		fmt.Fprintf(status, "错误: %v\n", err)
+		return
+	}
 	fmt.Println(result)
 
 	// 保存结果
-	filename, err := utils.SaveResult("saved_wifi", result)
+	saveResult(utils.ResultName{Command: "saved_wifi"}, format, run, wifi.SavedSchema, payload, encrypter)
+}
+
+// exportSavedWiFi 将已保存的网络导出为其他平台的配置文件
+func exportSavedWiFi(source string, path string, format string, dir string) {
+	networks, err := wifi.LoadSavedNetworks(source, path)
 	if err != nil {
-		fmt.Printf("保存结果失败: %v\n", err)
-	} else {
-		fmt.Printf("结果已保存到: %s\n", filename)
+		fmt.Printf("获取失败: %v\n", err)
+		return
+	}
+
+	if dir == "" {
+		dir = utils.ResultDirName("wifi_export_" + format)
+	}
+	result, err := wifi.ExportProfiles(networks, format, dir)
+	if err != nil {
+		fmt.Printf("导出失败: %v\n", err)
+		return
+	}
+	fmt.Print(wifi.FormatExportResult(result))
+}
+
+// snapshotSavedWiFi 将已保存网络的快照写入JSON文件
+func snapshotSavedWiFi(source string, path string, outPath string) {
+	networks, err := wifi.LoadSavedNetworks(source, path)
+	if err != nil {
+		fmt.Printf("获取失败: %v\n", err)
+		return
 	}
+	snapshot, err := wifi.NewSavedSnapshot(networks, savedBackend(source))
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	data, err := snapshot.Marshal()
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(outPath, data, 0600); err != nil {
+		fmt.Printf("保存快照失败: %v\n", err)
+		return
+	}
+	fmt.Printf("已保存 %d 个网络的快照到: %s\n", len(networks), outPath)
+}
+
+// diffSavedSnapshots 比较两个已保存网络快照
+func diffSavedSnapshots(oldPath string, newPath string) {
+	run := utils.NewEnvelope(os.Args)
+	run.Backend = "snapshot"
+	before, err := wifi.LoadSavedSnapshot(oldPath)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	after, err := wifi.LoadSavedSnapshot(newPath)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+
+	diff := wifi.DiffSnapshots(before, after)
+	fmt.Println(wifi.FormatSnapshotDiff(diff))
+
+	// 比较结果不包含密钥指纹，以明文保存
+	saveResult(utils.ResultName{Command: "saved_diff"}, wifi.OutputText, run, wifi.DiffSchema, diff, nil)
+}
+
+// qrSavedWiFi 为已保存的网络生成连接二维码
+func qrSavedWiFi(source string, path string, name string, level string, outPath string, size int) {
+	recovery, err := wifi.ParseQRLevel(level)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+
+	networks, err := wifi.LoadSavedNetworks(source, path)
+	if err != nil {
+		fmt.Printf("获取失败: %v\n", err)
+		return
+	}
+	network, found := wifi.FindSavedNetwork(networks, name)
+	if !found {
+		fmt.Printf("未找到已保存的网络: %s\n", name)
+		return
+	}
+
+	code, err := wifi.NewWiFiQRCode(network, recovery)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	fmt.Print(wifi.FormatQRTerminal(code))
+	fmt.Printf("SSID: %s  身份验证: %s  纠错级别: %s\n", network.SSID, network.AuthType(), strings.ToUpper(level))
+
+	if outPath != "" {
+		written, err := wifi.WriteQRFile(code, outPath, size)
+		if err != nil {
+			fmt.Printf("保存二维码失败: %v\n", err)
+			return
+		}
+		fmt.Printf("二维码已保存到: %s\n", written)
+	}
+}
+
+// importQRWiFi 识别图片中的WiFi二维码，并按需导出或连接
+func importQRWiFi(imagePath string, exportTo string, exportDir string, connect bool, format string, reveal bool, encrypter utils.Encrypter) {
+	status := statusOutput(format)
+	run := utils.NewEnvelope(os.Args)
+	run.Backend = "qrcode"
+
+	fmt.Fprintf(status, "正在识别 %s 中的WiFi二维码...\n", imagePath)
+	network, warnings, err := wifi.ImportQRImage(imagePath)
+	if err != nil {
+		fmt.Fprintf(status, "识别失败: %v\n", err)
+		return
+	}
+
+	run.AddWarnings(warnings...)
+	run.Redacted = !reveal
+	payload := wifi.SavedPayload([]wifi.SavedWiFi{network}, reveal)
+	result, err := renderResult(run, format, wifi.SavedSchema, payload, func() (string, error) {
+		return wifi.RenderSavedNetworks([]wifi.SavedWiFi{network}, format, reveal)
+	})
+	if err != nil {
+		fmt.Fprintf(status, "错误: %v\n", err)
+		return
+	}
+	fmt.Println(result)
+	for _, warning := range warnings {
+		fmt.Fprintf(status, "警告: %s\n", warning)
+	}
+
+	saveResult(utils.ResultName{Command: "import_qr", SSID: network.SSID}, format, run, wifi.SavedSchema, payload, encrypter)
+
+	if exportTo != "" {
+		if exportDir == "" {
+			exportDir = utils.ResultDirName("wifi_export_" + exportTo)
+		}
+		exported, err := wifi.ExportProfiles([]wifi.SavedWiFi{network}, exportTo, exportDir)
+		if err != nil {
+			fmt.Fprintf(status, "导出失败: %v\n", err)
+		} else {
+			fmt.Fprint(status, wifi.FormatExportResult(exported))
+		}
+	}
+
+	if connect {
+		fmt.Fprintf(status, "正在连接 %s...\n", network.SSID)
+		if err := wifi.ConnectNetwork(network); err != nil {
+			fmt.Fprintf(status, "错误: %v\n", wifi.RedactError(err))
+			return
+		}
+		fmt.Fprintf(status, "已连接到: %s\n", network.SSID)
+	}
+}
+
+// auditSavedWiFi 审计已保存网络的密钥强度
+func auditSavedWiFi(source string, path string, wordlistPath string, rates string, corpusPath string, reveal bool, encrypter utils.Encrypter) {
+	run := utils.NewEnvelope(os.Args)
+	run.Backend = savedBackend(source)
+	options := wifi.AuditOptions{Rates: wifi.DefaultPBKDF2Rates}
+	if rates != "" {
+		parsed, err := wifi.ParsePBKDF2Rates(rates)
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		options.Rates = parsed
+	}
+	if wordlistPath != "" {
+		wordlist, err := wifi.LoadAuditWordlist(wordlistPath)
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		options.Wordlist = wordlist
+	}
+	if corpusPath != "" {
+		corpus, err := wifi.OpenBreachCorpus(corpusPath)
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		defer corpus.Close()
+		options.Corpus = corpus
+	}
+
+	networks, err := wifi.LoadSavedNetworks(source, path)
+	if err != nil {
+		fmt.Printf("获取失败: %v\n", err)
+		return
+	}
+
+	audits, skipped := wifi.AuditSavedNetworks(networks, options)
+	result := wifi.FormatPassphraseAuditResult(audits, skipped, reveal)
+	fmt.Println(result)
+
+	run.Redacted = !reveal
+	saveResult(utils.ResultName{Command: "saved_audit"}, wifi.OutputText, run, wifi.AuditSchema, wifi.NewAuditPayload(audits, skipped, reveal), encrypter)
+}
+
+// hygieneSavedWiFi 检查已保存网络的配置卫生问题
+func hygieneSavedWiFi(source string, path string, historyPath string, staleDays int, encrypter utils.Encrypter) {
+	run := utils.NewEnvelope(os.Args)
+	run.Backend = savedBackend(source)
+	networks, err := wifi.LoadSavedNetworks(source, path)
+	if err != nil {
+		fmt.Printf("获取失败: %v\n", err)
+		return
+	}
+
+	if historyPath == "" {
+		historyPath = utils.ResultDir()
+	}
+	history, err := wifi.LoadScanHistory(historyPath)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	if len(history.Files) == 0 {
+		history = nil
+	}
+
+	since := time.Now().AddDate(0, 0, -staleDays)
+	report := wifi.CheckSavedHygiene(networks, source, history, since)
+	fmt.Println(wifi.FormatHygieneReport(report))
+
+	saveResult(utils.ResultName{Command: "saved_hygiene"}, wifi.OutputText, run, wifi.HygieneSchema, report, encrypter)
 }
 
 // bruteForceWiFi 对指定WiFi进行密码爆破
-func bruteForceWiFi(ssid string, dictPath string, maxAttempts int) {
-	fmt.Printf("正在对WiFi '%s' 进行密码爆破...\n", ssid)
+func bruteForceWiFi(ssid string, dictPath string, maxAttempts int, format string, reveal bool, encrypter utils.Encrypter) {
+	status := statusOutput(format)
+	run := utils.NewEnvelope(os.Args)
+	run.Backend = wifi.ScanBackend
+	run.Interface = wifi.InterfaceName()
+
+	fmt.Fprintf(status, "正在对WiFi '%s' 进行密码爆破...\n", ssid)
 
 	if dictPath != "" {
-		fmt.Printf("使用自定义密码字典: %s\n", dictPath)
+		fmt.Fprintf(status, "使用自定义密码字典: %s\n", dictPath)
 	} else {
-		fmt.Println("使用内置密码字典")
+		fmt.Fprintln(status, "使用内置密码字典")
 	}
 
 	if maxAttempts > 0 {
-		fmt.Printf("最大尝试次数: %d\n", maxAttempts)
+		fmt.Fprintf(status, "最大尝试次数: %d\n", maxAttempts)
 	}
 
-	result, err := wifi.BruteForceWiFi(ssid, dictPath, maxAttempts)
+	result, err := wifi.BruteForceWiFi(ssid, dictPath, maxAttempts, reveal)
 	if err != nil {
-		fmt.Printf("爆破失败: %v\n", err)
+		fmt.Fprintf(status, "爆破失败: %v\n", wifi.RedactError(err))
 		return
 	}
 
 	// 格式化并显示结果
-	formattedResult := wifi.FormatBruteForceResult(result)
+	run.Redacted = !reveal
+	payload := wifi.NewBruteRecord(result, reveal)
+	formattedResult, err := renderResult(run, format, wifi.BruteSchema, payload, func() (string, error) {
+		return wifi.RenderBruteForceResult(result, format, reveal)
+	})
+	if err != nil {
+		fmt.Fprintf(status, "错误: %v\n", err)
+		return
+	}
 	fmt.Println(formattedResult)
 
 	// 保存结果
-	filename, err := utils.SaveResult("brute_force_"+ssid, formattedResult)
+	saveResult(utils.ResultName{Command: "brute_force", SSID: ssid}, format, run, wifi.BruteSchema, payload, encrypter)
+}
+
+// surveyWiFi 按位置进行现场勘测，结束后生成HTML报告
+func surveyWiFi(corpSSID string, samples int, interval time.Duration, threshold int) {
+	if samples <= 0 {
+		samples = 1
+	}
+
+	survey := wifi.NewSiteSurvey(corpSSID, threshold)
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println("开始WiFi现场勘测，输入位置名称后回车开始采样，输入空行结束勘测")
+	for {
+		fmt.Print("位置名称: ")
+		line, err := reader.ReadString('\n')
+		name := strings.TrimSpace(line)
+		if name == "" {
+			break
+		}
+
+		location := survey.Location(name)
+		for i := 0; i < samples; i++ {
+			fmt.Printf("正在采样 %s (%d/%d)...\n", name, i+1, samples)
+			networks, scanErr := wifi.ScanNetworks()
+			if scanErr != nil {
+				fmt.Printf("扫描失败: %v\n", scanErr)
+				continue
+			}
+			location.AddSample(networks)
+			if i < samples-1 {
+				time.Sleep(interval)
+			}
+		}
+
+		summary := location.Summarize(survey.CorporateSSID, survey.CoverageThreshold)
+		fmt.Println(wifi.FormatSurveyLocationResult(summary))
+
+		if err != nil {
+			break
+		}
+	}
+
+	if len(survey.Locations) == 0 {
+		fmt.Println("未记录任何位置，不生成报告")
+		return
+	}
+
+	// 生成并保存HTML报告
+	if utils.SaveDisabled() {
+		return
+	}
+	report, err := wifi.FormatSurveyHTML(survey)
+	if err != nil {
+		fmt.Printf("生成报告失败: %v\n", err)
+		return
+	}
+
+	name := utils.ResultName{Command: "wifi_survey"}
+	filename, err := utils.SaveResultAs(name, "html", report)
 	if err != nil {
 		fmt.Printf("保存结果失败: %v\n", err)
+		return
+	}
+	fmt.Printf("勘测报告已保存到: %s\n", filename)
+	pruneResults(os.Stdout, name, "html")
+}
+
+// newResultEncrypter 根据参数创建结果文件的加密方式
+// 指定公钥时使用公钥加密，否则读取口令加密，plaintext为true或不保存结果时返回nil
+func newResultEncrypter(plaintext bool, recipient string) (utils.Encrypter, error) {
+	if utils.SaveDisabled() {
+		return nil, nil
+	}
+	if plaintext {
+		if recipient != "" {
+			return nil, fmt.Errorf("--plaintext 和 --recipient 不能同时使用")
+		}
+		return nil, nil
+	}
+	if recipient != "" {
+		key, err := utils.LoadPublicKey(recipient)
+		if err != nil {
+			return nil, err
+		}
+		return utils.NewRecipientEncrypter(key), nil
+	}
+
+	fmt.Fprintf(os.Stderr, "结果文件将使用口令加密，可通过 decrypt 命令查看（使用 --plaintext 以明文保存，或设置环境变量 %s）\n", utils.PassphraseEnv)
+	passphrase, err := utils.ReadPassphrase(true)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPassphraseEncrypter(passphrase), nil
+}
+
+// renderResult 按格式生成结果，html格式由结果文件的内容生成带图表的报告，其余格式由render生成
+func renderResult(run *utils.Envelope, format string, schema string, payload interface{}, render func() (string, error)) (string, error) {
+	if format != wifi.OutputHTML {
+		return render()
+	}
+	run.AddWarnings(wifi.TakeWarnings()...)
+	if err := run.SetPayload(schema, payload); err != nil {
+		return "", err
+	}
+	return wifi.RenderHTMLReport([]*utils.Envelope{run}, true)
+}
+
+// saveResult 将运行信息和结果载荷保存为结果文件，encrypter为nil时以明文保存
+func saveResult(name utils.ResultName, format string, run *utils.Envelope, schema string, payload interface{}, encrypter utils.Encrypter) {
+	if utils.SaveDisabled() {
+		return
+	}
+	status := statusOutput(format)
+	run.AddWarnings(wifi.TakeWarnings()...)
+	if err := run.SetPayload(schema, payload); err != nil {
+		fmt.Fprintf(status, "保存结果失败: %v\n", err)
+		return
+	}
+	filename, err := utils.SaveEnvelope(name, run, encrypter)
+	if err != nil {
+		fmt.Fprintf(status, "保存结果失败: %v\n", err)
+		return
+	} else if encrypter != nil {
+		fmt.Fprintf(status, "结果已加密保存到: %s\n", filename)
 	} else {
-		fmt.Printf("结果已保存到: %s\n", filename)
+		fmt.Fprintf(status, "结果已保存到: %s\n", filename)
+	}
+	pruneResults(status, name, "json")
+}
+
+// pruneResults 按 --retain 删除同一类结果中较旧的文件
+func pruneResults(status io.Writer, name utils.ResultName, ext string) {
+	removed, err := utils.PruneResults(name, ext)
+	for _, path := range removed {
+		fmt.Fprintf(status, "已删除旧结果: %s\n", path)
+	}
+	if err != nil {
+		fmt.Fprintf(status, "%v\n", err)
+	}
+}
+
+// resultFlags 保存结果文件的命令共用的参数
+type resultFlags struct {
+	outDir   *string
+	template *string
+	noSave   *bool
+	retain   *int
+}
+
+// addResultFlags 为命令添加结果文件的保存目录、文件名模板和保留数量参数
+func addResultFlags(command *argparse.Command) *resultFlags {
+	return &resultFlags{
+		outDir: command.String("", "out-dir", &argparse.Options{
+			Required: false,
+			Help:     "结果文件的保存目录，不存在时自动创建，默认当前目录",
+		}),
+		template: command.String("", "name-template", &argparse.Options{
+			Required: false,
+			Help:     "结果文件名模板，可用字段: {cmd} {ssid} {time} {host} {ext}，为空的字段连同前面的分隔符一起省略",
+			Default:  utils.DefaultNameTemplate,
+		}),
+		noSave: command.Flag("", "no-save", &argparse.Options{
+			Help: "只显示结果，不保存结果文件",
+		}),
+		retain: command.Int("", "retain", &argparse.Options{
+			Required: false,
+			Help:     "每类结果只保留最新的N个文件，保存后自动删除更早的文件，0表示不限制",
+			Default:  0,
+		}),
+	}
+}
+
+// apply 检查并应用结果文件参数
+func (f *resultFlags) apply() error {
+	return utils.SetResultOptions(utils.ResultOptions{
+		Dir:      *f.outDir,
+		Template: *f.template,
+		NoSave:   *f.noSave,
+		Retain:   *f.retain,
+	})
+}
+
+// savedBackend 返回已保存网络的来源，未指定时为当前系统的默认来源
+func savedBackend(source string) string {
+	if source == "" {
+		return wifi.DefaultSavedSource()
+	}
+	return source
+}
+
+// statusOutput 返回提示信息的输出位置，结构化格式时输出到标准错误，使标准输出只包含结果
+func statusOutput(format string) io.Writer {
+	if format != wifi.OutputText {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// decryptResult 解密结果文件，输出到终端或写入文件
+func decryptResult(path string, identityPath string, outPath string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("读取文件失败: %v\n", err)
+		return
+	}
+	decrypter := &resultDecrypter{identityPath: identityPath}
+	plaintext, err := decrypter.decrypt(data)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	if outPath == "" {
+		fmt.Print(string(plaintext))
+		return
+	}
+	if err := os.WriteFile(outPath, plaintext, 0600); err != nil {
+		fmt.Printf("写入文件失败: %v\n", err)
+		return
+	}
+	fmt.Printf("已解密到: %s\n", outPath)
+}
+
+// resultDecrypter 解密结果文件，口令加密的文件读取口令，公钥加密的文件使用identityPath指定的私钥
+// 解密多个文件时口令和私钥只读取一次
+type resultDecrypter struct {
+	identityPath string
+	passphrase   []byte
+	identity     *ecdh.PrivateKey
+}
+
+// decrypt 解密结果文件的内容
+func (d *resultDecrypter) decrypt(data []byte) ([]byte, error) {
+	needsPassphrase, err := utils.NeedsPassphrase(data)
+	if err != nil {
+		return nil, err
+	}
+	if needsPassphrase && d.passphrase == nil {
+		d.passphrase, err = utils.ReadPassphrase(false)
+	} else if !needsPassphrase && d.identity == nil {
+		if d.identityPath == "" {
+			return nil, fmt.Errorf("该文件使用公钥加密，请通过 -i 指定私钥文件")
+		}
+		d.identity, err = utils.LoadPrivateKey(d.identityPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if needsPassphrase {
+		return utils.Decrypt(data, d.passphrase, nil)
+	}
+	return utils.Decrypt(data, nil, d.identity)
+}
+
+// loadEnvelope 读取结果文件，加密的结果文件先解密
+func (d *resultDecrypter) loadEnvelope(path string) (*utils.Envelope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	if utils.IsEncrypted(data) {
+		data, err = d.decrypt(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return utils.ParseEnvelope(data)
+}
+
+// loadEnvelopes 读取path指定的结果文件，path为目录时读取其中所有的 .json 和 .json.enc 结果文件，
+// 跳过不是结果文件的JSON，并按开始时间排序
+func loadEnvelopes(path string, identityPath string) ([]*utils.Envelope, error) {
+	decrypter := &resultDecrypter{identityPath: identityPath}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	if !info.IsDir() {
+		envelope, err := decrypter.loadEnvelope(path)
+		if err != nil {
+			return nil, err
+		}
+		return []*utils.Envelope{envelope}, nil
+	}
+
+	var files []string
+	for _, pattern := range []string{"*.json", "*.json." + utils.EncryptedExt} {
+		matches, err := filepath.Glob(filepath.Join(path, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("读取目录失败: %v", err)
+		}
+		files = append(files, matches...)
+	}
+	var envelopes []*utils.Envelope
+	for _, file := range files {
+		envelope, err := decrypter.loadEnvelope(file)
+		if err != nil {
+			if strings.HasSuffix(file, "."+utils.EncryptedExt) {
+				return nil, err
+			}
+			continue
+		}
+		envelopes = append(envelopes, envelope)
+	}
+	if len(envelopes) == 0 {
+		return nil, fmt.Errorf("%s 中没有WifiSOS结果文件", path)
+	}
+	sort.SliceStable(envelopes, func(i, j int) bool {
+		return envelopes[i].Started.Before(envelopes[j].Started)
+	})
+	return envelopes, nil
+}
+
+// renderReport 根据保存的结果文件重新生成报告，path为目录时将其中所有结果文件合并为一份报告
+func renderReport(path string, format string, identityPath string, reveal bool, outPath string) {
+	envelopes, err := loadEnvelopes(path, identityPath)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	for _, envelope := range envelopes {
+		if reveal && envelope.Redacted {
+			fmt.Fprintln(os.Stderr, "注意: 部分结果保存时未使用 --reveal，只能显示密钥掩码")
+			break
+		}
+	}
+
+	report, err := wifi.RenderReport(envelopes, format, reveal)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	if outPath == "" {
+		fmt.Print(report)
+		return
+	}
+	if err := os.WriteFile(outPath, []byte(report), 0600); err != nil {
+		fmt.Printf("写入文件失败: %v\n", err)
+		return
+	}
+	fmt.Printf("报告已保存到: %s\n", outPath)
+}
+
+// generateKeyPair 生成X25519密钥对，私钥写入 name.key，公钥写入 name.pub
+func generateKeyPair(name string) {
+	privatePath, publicPath := name+".key", name+".pub"
+	for _, path := range []string{privatePath, publicPath} {
+		if _, err := os.Stat(path); err == nil {
+			fmt.Printf("错误: %s 已存在，不会覆盖\n", path)
+			return
+		}
+	}
+
+	key, err := utils.GenerateKeyPair()
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(privatePath, []byte(utils.FormatPrivateKey(key)+"\n"), 0600); err != nil {
+		fmt.Printf("写入私钥失败: %v\n", err)
+		return
+	}
+	publicKey := utils.FormatPublicKey(key.PublicKey())
+	if err := os.WriteFile(publicPath, []byte(publicKey+"\n"), 0644); err != nil {
+		fmt.Printf("写入公钥失败: %v\n", err)
+		return
 	}
+	fmt.Printf("私钥已保存到: %s（请妥善保管，解密时使用 -i %s）\n", privatePath, privatePath)
+	fmt.Printf("公钥已保存到: %s\n", publicPath)
+	fmt.Printf("加密结果时使用: --recipient %s\n", publicPath)
 }
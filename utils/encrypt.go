@@ -0,0 +1,319 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptedExt 加密结果文件追加的扩展名
+const EncryptedExt = "enc"
+
+// 加密文件格式：魔数、版本、加密方式，之后是各方式的参数、nonce和AES-256-GCM密文
+// 魔数到nonce的全部内容作为GCM的附加数据，防止被篡改
+var encryptedMagic = []byte("WSOSENC")
+
+const (
+	encryptedVersion = 1
+
+	modePassphrase = 1 // scrypt派生密钥
+	modeRecipient  = 2 // X25519临时密钥协商
+
+	// scrypt参数，N=2^15、r=8、p=1，在普通电脑上约需100毫秒
+	scryptLogN = 15
+	scryptR    = 8
+	scryptP    = 1
+
+	// 解密时接受的scrypt参数上限，内存128*r*N不超过256MiB（写入参数的8倍），p不超过4
+	scryptMaxMemory = 256 << 20
+	scryptMaxP      = 4
+
+	saltLength = 16
+	keyLength  = 32
+)
+
+// 公钥和私钥文件的前缀，避免把两者弄混
+const (
+	publicKeyPrefix  = "wifisos-x25519-public:"
+	privateKeyPrefix = "wifisos-x25519-private:"
+)
+
+// hkdfInfo X25519共享密钥派生AES密钥时使用的上下文
+const hkdfInfo = "WifiSOS result encryption"
+
+// Encrypter 加密结果文件的内容
+type Encrypter interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+}
+
+// passphraseEncrypter 使用口令加密
+type passphraseEncrypter struct {
+	passphrase []byte
+}
+
+// NewPassphraseEncrypter 返回使用口令加密的Encrypter，密钥由scrypt派生
+func NewPassphraseEncrypter(passphrase []byte) Encrypter {
+	return &passphraseEncrypter{passphrase: passphrase}
+}
+
+// Encrypt 使用口令加密
+func (e *passphraseEncrypter) Encrypt(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("生成随机数失败: %v", err)
+	}
+	key, err := scrypt.Key(e.passphrase, salt, 1<<scryptLogN, scryptR, scryptP, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥失败: %v", err)
+	}
+
+	header := append(encryptedHeader(modePassphrase), scryptLogN, scryptR, scryptP)
+	header = append(header, salt...)
+	return seal(key, header, plaintext)
+}
+
+// recipientEncrypter 使用接收者的X25519公钥加密
+type recipientEncrypter struct {
+	recipient *ecdh.PublicKey
+}
+
+// NewRecipientEncrypter 返回使用X25519公钥加密的Encrypter，只有对应私钥的持有者可以解密
+func NewRecipientEncrypter(recipient *ecdh.PublicKey) Encrypter {
+	return &recipientEncrypter{recipient: recipient}
+}
+
+// Encrypt 生成临时密钥对与接收者公钥协商出共享密钥后加密
+func (e *recipientEncrypter) Encrypt(plaintext []byte) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("生成临时密钥失败: %v", err)
+	}
+	shared, err := ephemeral.ECDH(e.recipient)
+	if err != nil {
+		return nil, fmt.Errorf("密钥协商失败: %v", err)
+	}
+	ephemeralPublic := ephemeral.PublicKey().Bytes()
+	key, err := deriveRecipientKey(shared, ephemeralPublic, e.recipient.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	header := append(encryptedHeader(modeRecipient), ephemeralPublic...)
+	return seal(key, header, plaintext)
+}
+
+// encryptedHeader 返回加密文件的魔数、版本和加密方式
+func encryptedHeader(mode byte) []byte {
+	header := append([]byte{}, encryptedMagic...)
+	return append(header, encryptedVersion, mode)
+}
+
+// deriveRecipientKey 使用HKDF-SHA256从共享密钥派生AES密钥，盐为临时公钥和接收者公钥
+func deriveRecipientKey(shared []byte, ephemeralPublic []byte, recipientPublic []byte) ([]byte, error) {
+	salt := append(append([]byte{}, ephemeralPublic...), recipientPublic...)
+	key := make([]byte, keyLength)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(hkdfInfo)), key); err != nil {
+		return nil, fmt.Errorf("派生密钥失败: %v", err)
+	}
+	return key, nil
+}
+
+// newGCM 创建AES-256-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 生成随机nonce后加密，返回 header + nonce + 密文
+func seal(key []byte, header []byte, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("初始化加密失败: %v", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成随机数失败: %v", err)
+	}
+	header = append(header, nonce...)
+	return append(header, gcm.Seal(nil, nonce, plaintext, header)...), nil
+}
+
+// open 解密 header之后的 nonce + 密文
+func open(key []byte, data []byte, headerLength int) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("初始化解密失败: %v", err)
+	}
+	if len(data) < headerLength+gcm.NonceSize()+gcm.Overhead() {
+		return nil, fmt.Errorf("加密文件已损坏")
+	}
+	aad := data[:headerLength+gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, aad[headerLength:], data[len(aad):], aad)
+	if err != nil {
+		return nil, fmt.Errorf("解密失败，口令或私钥错误，或文件已被修改")
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted 判断数据是否为加密结果文件
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// encryptedMode 返回加密文件使用的加密方式
+func encryptedMode(data []byte) (byte, error) {
+	if !IsEncrypted(data) || len(data) < len(encryptedMagic)+2 {
+		return 0, fmt.Errorf("不是WifiSOS加密文件")
+	}
+	if version := data[len(encryptedMagic)]; version != encryptedVersion {
+		return 0, fmt.Errorf("不支持的加密文件版本: %d", version)
+	}
+	return data[len(encryptedMagic)+1], nil
+}
+
+// NeedsPassphrase 判断加密文件是否需要口令解密，为false时需要私钥
+func NeedsPassphrase(data []byte) (bool, error) {
+	mode, err := encryptedMode(data)
+	if err != nil {
+		return false, err
+	}
+	return mode == modePassphrase, nil
+}
+
+// Decrypt 解密结果文件，口令加密的文件使用passphrase，公钥加密的文件使用identity
+func Decrypt(data []byte, passphrase []byte, identity *ecdh.PrivateKey) ([]byte, error) {
+	mode, err := encryptedMode(data)
+	if err != nil {
+		return nil, err
+	}
+	offset := len(encryptedMagic) + 2
+
+	switch mode {
+	case modePassphrase:
+		if len(data) < offset+3+saltLength {
+			return nil, fmt.Errorf("加密文件已损坏")
+		}
+		logN, r, p := data[offset], data[offset+1], data[offset+2]
+		// 限制参数范围，避免被构造的文件消耗大量内存和计算时间
+		if logN == 0 || logN > 30 || r == 0 || p == 0 || p > scryptMaxP || 128*uint64(r)<<logN > scryptMaxMemory {
+			return nil, fmt.Errorf("加密文件的scrypt参数无效")
+		}
+		salt := data[offset+3 : offset+3+saltLength]
+		key, err := scrypt.Key(passphrase, salt, 1<<logN, int(r), int(p), keyLength)
+		if err != nil {
+			return nil, fmt.Errorf("派生密钥失败: %v", err)
+		}
+		return open(key, data, offset+3+saltLength)
+	case modeRecipient:
+		if identity == nil {
+			return nil, fmt.Errorf("该文件使用公钥加密，需要指定私钥")
+		}
+		publicLength := len(identity.PublicKey().Bytes())
+		if len(data) < offset+publicLength {
+			return nil, fmt.Errorf("加密文件已损坏")
+		}
+		ephemeralPublic, err := ecdh.X25519().NewPublicKey(data[offset : offset+publicLength])
+		if err != nil {
+			return nil, fmt.Errorf("加密文件已损坏: %v", err)
+		}
+		shared, err := identity.ECDH(ephemeralPublic)
+		if err != nil {
+			return nil, fmt.Errorf("密钥协商失败: %v", err)
+		}
+		key, err := deriveRecipientKey(shared, ephemeralPublic.Bytes(), identity.PublicKey().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		return open(key, data, offset+publicLength)
+	}
+	return nil, fmt.Errorf("不支持的加密方式: %d", mode)
+}
+
+// GenerateKeyPair 生成X25519密钥对
+func GenerateKeyPair() (*ecdh.PrivateKey, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("生成密钥失败: %v", err)
+	}
+	return key, nil
+}
+
+// FormatPublicKey 返回公钥的文本形式
+func FormatPublicKey(key *ecdh.PublicKey) string {
+	return publicKeyPrefix + base64.StdEncoding.EncodeToString(key.Bytes())
+}
+
+// FormatPrivateKey 返回私钥的文本形式
+func FormatPrivateKey(key *ecdh.PrivateKey) string {
+	return privateKeyPrefix + base64.StdEncoding.EncodeToString(key.Bytes())
+}
+
+// decodeKey 解析带前缀的Base64密钥文本
+func decodeKey(text string, prefix string) ([]byte, error) {
+	text = strings.TrimSpace(text)
+	encoded, found := strings.CutPrefix(text, prefix)
+	if !found {
+		return nil, fmt.Errorf("密钥应以 %s 开头", prefix)
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+// readKeyText 读取密钥，value可以是密钥文件路径或密钥文本本身
+func readKeyText(value string, prefix string) (string, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), prefix) {
+		return value, nil
+	}
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return "", fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+	return string(data), nil
+}
+
+// LoadPublicKey 从文件或文本加载X25519公钥
+func LoadPublicKey(value string) (*ecdh.PublicKey, error) {
+	text, err := readKeyText(value, publicKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := decodeKey(text, publicKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("无效的公钥: %v", err)
+	}
+	key, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("无效的公钥: %v", err)
+	}
+	return key, nil
+}
+
+// LoadPrivateKey 从文件加载X25519私钥
+func LoadPrivateKey(path string) (*ecdh.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取私钥文件失败: %v", err)
+	}
+	raw, err := decodeKey(string(data), privateKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("无效的私钥: %v", err)
+	}
+	key, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("无效的私钥: %v", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// passphraseSample 为用口令 "correct horse battery" 加密的结果文件，用于确认文件格式保持兼容
+const passphraseSample = "V1NPU0VOQwEBDwgBwjEp/GgT71Ks6r+BdyBpdVXR/9I5Qet7NrzNnAaEYOPQsL8t/xzb3o03pOUqJM3ep1QJLqs6a84W1SoKz37246/31HY3za4Lh79wwqifH5ya"
+
+// passphrasePlaintext 为passphraseSample的明文
+const passphrasePlaintext = "{\"ssid\":\"HomeNet\",\"password\":\"p@ss\"}\n"
+
+func TestDecryptPassphraseSample(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(passphraseSample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(data) {
+		t.Fatal("样本应被识别为加密文件")
+	}
+	if needs, err := NeedsPassphrase(data); err != nil || !needs {
+		t.Fatalf("NeedsPassphrase = %v, %v，期望 true", needs, err)
+	}
+	plaintext, err := Decrypt(data, []byte("correct horse battery"), nil)
+	if err != nil || string(plaintext) != passphrasePlaintext {
+		t.Fatalf("Decrypt = %q, %v，期望 %q", plaintext, err, passphrasePlaintext)
+	}
+	if _, err := Decrypt(data, []byte("wrong passphrase"), nil); err == nil {
+		t.Error("错误的口令应解密失败")
+	}
+}
+
+func TestDecryptRejectsTampering(t *testing.T) {
+	data, _ := base64.StdEncoding.DecodeString(passphraseSample)
+	offset := len(encryptedMagic) + 2
+	tests := []struct {
+		name string
+		pos  int
+	}{
+		{"scrypt参数", offset + 2},
+		{"盐", offset + 3},
+		{"nonce", offset + 3 + saltLength},
+		{"密文", len(data) - 20},
+		{"认证标签", len(data) - 1},
+	}
+	for _, test := range tests {
+		tampered := bytes.Clone(data)
+		tampered[test.pos] ^= 0x01
+		if _, err := Decrypt(tampered, []byte("correct horse battery"), nil); err == nil {
+			t.Errorf("修改%s后应解密失败", test.name)
+		}
+	}
+
+	// 过大的scrypt参数会消耗大量内存，应在派生密钥前拒绝
+	for _, params := range [][3]byte{{30, 8, 1}, {22, 32, 16}, {19, 8, 1}, {15, 8, 5}, {0, 8, 1}, {15, 0, 1}, {15, 8, 0}} {
+		costly := bytes.Clone(data)
+		copy(costly[offset:], params[:])
+		if _, err := Decrypt(costly, []byte("correct horse battery"), nil); err == nil || !strings.Contains(err.Error(), "scrypt参数无效") {
+			t.Errorf("scrypt参数 %v 应在派生密钥前拒绝，得到 %v", params, err)
+		}
+	}
+	if _, err := Decrypt(data[:offset+10], []byte("correct horse battery"), nil); err == nil {
+		t.Error("截断的文件应解密失败")
+	}
+}
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	plaintext := []byte("包含密码的结果")
+	data, err := NewPassphraseEncrypter([]byte("longpassphrase")).Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := NewPassphraseEncrypter([]byte("longpassphrase")).Encrypt(plaintext)
+	if bytes.Equal(data, other) {
+		t.Error("相同口令两次加密的结果应使用不同的盐和nonce")
+	}
+	decrypted, err := Decrypt(data, []byte("longpassphrase"), nil)
+	if err != nil || !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypt = %q, %v，期望 %q", decrypted, err, plaintext)
+	}
+}
+
+func TestRecipientRoundTrip(t *testing.T) {
+	identity, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "team.key")
+	if err := os.WriteFile(privatePath, []byte(FormatPrivateKey(identity)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	loadedIdentity, err := LoadPrivateKey(privatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := LoadPublicKey(FormatPublicKey(identity.PublicKey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPublicKey(FormatPrivateKey(identity)); err == nil {
+		t.Error("私钥文本不应被当作公钥加载")
+	}
+
+	plaintext := []byte("brute force result")
+	data, err := NewRecipientEncrypter(recipient).Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needs, err := NeedsPassphrase(data); err != nil || needs {
+		t.Fatalf("NeedsPassphrase = %v, %v，期望 false", needs, err)
+	}
+	decrypted, err := Decrypt(data, nil, loadedIdentity)
+	if err != nil || !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypt = %q, %v，期望 %q", decrypted, err, plaintext)
+	}
+
+	other, _ := GenerateKeyPair()
+	if _, err := Decrypt(data, nil, other); err == nil {
+		t.Error("其他私钥应解密失败")
+	}
+	if _, err := Decrypt(data, []byte("longpassphrase"), nil); err == nil {
+		t.Error("公钥加密的文件没有私钥时应解密失败")
+	}
+}
+
+func TestPassphraseEnvLength(t *testing.T) {
+	t.Setenv(PassphraseEnv, "short")
+	if _, err := ReadPassphrase(true); err == nil {
+		t.Error("加密时环境变量中过短的口令应被拒绝")
+	}
+	// 解密时只需与加密时的口令一致
+	if passphrase, err := ReadPassphrase(false); err != nil || string(passphrase) != "short" {
+		t.Errorf("ReadPassphrase(false) = %q, %v", passphrase, err)
+	}
+	t.Setenv(PassphraseEnv, "longpassphrase")
+	if passphrase, err := ReadPassphrase(true); err != nil || string(passphrase) != "longpassphrase" {
+		t.Errorf("ReadPassphrase(true) = %q, %v", passphrase, err)
+	}
+}
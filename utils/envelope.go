@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+// EnvelopeSchema 结果文件的架构名称，载荷的架构由PayloadSchema单独标明
+const EnvelopeSchema = "wifisos.result/v1"
+
+// ToolName 写入结果文件的工具名称
+const ToolName = "WifiSOS"
+
+// Version 工具版本，发布构建时通过 -ldflags "-X WifiSOS/utils.Version=1.2.3" 设置
+var Version = "dev"
+
+// Envelope 保存的结果文件，记录一次运行的环境信息和结果载荷，report命令可据此重新生成报告
+type Envelope struct {
+	Schema        string          `json:"schema"`
+	Tool          string          `json:"tool"`
+	Version       string          `json:"version"`
+	Command       []string        `json:"command"`
+	Host          string          `json:"host"`
+	OS            string          `json:"os"`
+	Interface     string          `json:"interface"` // 使用的无线网卡，读取离线文件时为空
+	Backend       string          `json:"backend"`   // 获取数据的方式，如netsh、nm或snapshot
+	Started       time.Time       `json:"started"`
+	Finished      time.Time       `json:"finished"`
+	Warnings      []string        `json:"warnings"`
+	Redacted      bool            `json:"redacted"` // 为true时载荷中的密钥只保留掩码
+	PayloadSchema string          `json:"payload_schema"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope 在运行开始时创建结果文件，args为完整的命令行
+func NewEnvelope(args []string) *Envelope {
+	host, _ := os.Hostname()
+	return &Envelope{
+		Schema:   EnvelopeSchema,
+		Tool:     ToolName,
+		Version:  Version,
+		Command:  append([]string{}, args...),
+		Host:     host,
+		OS:       runtime.GOOS,
+		Started:  time.Now(),
+		Warnings: []string{},
+	}
+}
+
+// AddWarnings 追加运行过程中产生的警告
+func (e *Envelope) AddWarnings(warnings ...string) {
+	e.Warnings = append(e.Warnings, warnings...)
+}
+
+// SetPayload 设置结果载荷并记录结束时间
+func (e *Envelope) SetPayload(schema string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("生成结果载荷失败: %v", err)
+	}
+	e.PayloadSchema = schema
+	e.Payload = data
+	e.Finished = time.Now()
+	return nil
+}
+
+// Duration 返回运行耗时
+func (e *Envelope) Duration() time.Duration {
+	return e.Finished.Sub(e.Started)
+}
+
+// ParseEnvelope 解析结果文件
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("不是WifiSOS结果文件: %v", err)
+	}
+	if envelope.Schema != EnvelopeSchema {
+		if envelope.Schema == "" {
+			return nil, fmt.Errorf("不是WifiSOS结果文件")
+		}
+		return nil, fmt.Errorf("不支持的结果文件架构: %s", envelope.Schema)
+	}
+	return &envelope, nil
+}
+
+// SaveEnvelope 将结果文件保存为 .json，encrypter不为nil时加密保存为 .json.enc
+func SaveEnvelope(name ResultName, envelope *Envelope, encrypter Encrypter) (string, error) {
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("生成结果文件失败: %v", err)
+	}
+	return SaveEncryptedResult(name, "json", string(data)+"\n", encrypter)
+}
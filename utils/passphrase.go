@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// PassphraseEnv 提供结果文件口令的环境变量，设置后不再交互输入
+const PassphraseEnv = "WIFISOS_PASSPHRASE"
+
+// minPassphraseLength 加密时口令的最小长度
+const minPassphraseLength = 8
+
+// stdinReader 非终端输入时共用的读取器，避免两次读取之间丢失缓冲的内容
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// readLine 从终端读取一行，终端输入时不回显
+func readLine(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		data, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("读取口令失败: %v", err)
+		}
+		return string(data), nil
+	}
+
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("读取口令失败: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ReadPassphrase 读取结果文件的口令，优先使用环境变量
+// confirm为true时用于加密，检查长度，交互输入时要求输入两次
+func ReadPassphrase(confirm bool) ([]byte, error) {
+	if value := os.Getenv(PassphraseEnv); value != "" {
+		if confirm && len([]rune(value)) < minPassphraseLength {
+			return nil, fmt.Errorf("环境变量 %s 中的口令长度至少为 %d 个字符", PassphraseEnv, minPassphraseLength)
+		}
+		return []byte(value), nil
+	}
+
+	passphrase, err := readLine("请输入结果文件口令: ")
+	if err != nil {
+		return nil, err
+	}
+	if !confirm {
+		return []byte(passphrase), nil
+	}
+	if len([]rune(passphrase)) < minPassphraseLength {
+		return nil, fmt.Errorf("口令长度至少为 %d 个字符", minPassphraseLength)
+	}
+	again, err := readLine("请再次输入口令: ")
+	if err != nil {
+		return nil, err
+	}
+	if again != passphrase {
+		return nil, fmt.Errorf("两次输入的口令不一致")
+	}
+	return []byte(passphrase), nil
+}
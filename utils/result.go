@@ -1,24 +1,310 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 )
 
-// SaveResult 将结果保存到文件
-func SaveResult(prefix string, content string) (string, error) {
+// DefaultNameTemplate 默认的结果文件名模板，未知的SSID等字段为空时连同前面的分隔符一起省略
+const DefaultNameTemplate = "{cmd}_{ssid}_{time}.{ext}"
 
-	// 生成文件名，格式为: prefix_timestamp.txt
-	timestamp := time.Now().Format("20060102_150405")
-	filename := filepath.Join(fmt.Sprintf("%s_%s.txt", prefix, timestamp))
+// resultTimeFormat 文件名中的时间格式
+const resultTimeFormat = "20060102_150405"
 
-	// 写入文件
-	err := os.WriteFile(filename, []byte(content), 0644)
+// 文件名各部分的最大长度（字节），避免超过文件系统255字节的限制
+const (
+	maxNameFieldLength = 64
+	maxNameStemLength  = 180
+)
+
+// 文件名冲突时追加序号的上限
+const maxNameCollisions = 1000
+
+// nameFields 文件名模板支持的字段
+var nameFields = []string{"cmd", "ssid", "time", "host", "ext"}
+
+// placeholderPattern 匹配模板中的字段
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// ResultName 结果文件名中的字段
+type ResultName struct {
+	Command string // 结果类型，如 wifi_scan、saved_wifi
+	SSID    string // 针对单个网络的结果填写SSID，否则为空
+}
+
+// ResultOptions 结果文件的保存位置、文件名和保留策略
+type ResultOptions struct {
+	Dir      string // 保存目录，为空时为当前目录
+	Template string // 文件名模板，为空时使用DefaultNameTemplate
+	NoSave   bool   // 不保存结果文件
+	Retain   int    // 每类结果只保留最新的文件数量，0表示不限制
+}
+
+// resultOptions 当前的结果保存设置
+var resultOptions = ResultOptions{Template: DefaultNameTemplate}
+
+// SetResultOptions 检查并设置结果文件的保存方式，保存目录不存在时创建
+func SetResultOptions(options ResultOptions) error {
+	if options.Template == "" {
+		options.Template = DefaultNameTemplate
+	}
+	if _, err := nameStem(options.Template); err != nil {
+		return err
+	}
+	if options.Retain < 0 {
+		return fmt.Errorf("保留的结果数量不能为负数")
+	}
+	if options.Dir != "" && !options.NoSave {
+		if err := os.MkdirAll(options.Dir, 0700); err != nil {
+			return fmt.Errorf("创建结果目录失败: %v", err)
+		}
+	}
+	resultOptions = options
+	return nil
+}
+
+// SaveDisabled 判断是否指定了不保存结果文件
+func SaveDisabled() bool {
+	return resultOptions.NoSave
+}
+
+// ResultDir 返回结果文件的保存目录
+func ResultDir() string {
+	if resultOptions.Dir == "" {
+		return "."
+	}
+	return resultOptions.Dir
+}
+
+// nameStem 检查模板并返回去掉扩展名字段的部分，{ext}只能位于模板末尾，省略时自动追加
+func nameStem(template string) (string, error) {
+	stem := strings.TrimSuffix(template, ".{ext}")
+	for _, match := range placeholderPattern.FindAllStringSubmatch(stem, -1) {
+		known := false
+		for _, field := range nameFields {
+			known = known || match[1] == field
+		}
+		if !known {
+			return "", fmt.Errorf("文件名模板中有未知字段 %s，可用字段: {cmd}、{ssid}、{time}、{host}、{ext}", match[0])
+		}
+		if match[1] == "ext" {
+			return "", fmt.Errorf("文件名模板中的 {ext} 只能位于末尾，如 {cmd}_{time}.{ext}")
+		}
+	}
+	if strings.ContainsAny(placeholderPattern.ReplaceAllString(stem, ""), "{}") {
+		return "", fmt.Errorf("文件名模板中的花括号不匹配: %s", template)
+	}
+	return stem, nil
+}
+
+// windowsReservedNames Windows中不能用作文件名的设备名
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// replaceUnsafeRunes 将路径分隔符、Windows保留字符和控制字符替换为下划线
+func replaceUnsafeRunes(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, text)
+}
+
+// SanitizeFilename 将任意文本转换为安全的文件名：替换路径分隔符、Windows保留字符和控制字符，
+// 去掉首尾的点和空格，避开Windows设备名，并按UTF-8字符边界截断到maxLength字节
+func SanitizeFilename(name string, maxLength int) string {
+	sanitized := strings.Trim(replaceUnsafeRunes(name), " .")
+
+	if base, _, _ := strings.Cut(sanitized, "."); windowsReservedNames[strings.ToUpper(strings.TrimSpace(base))] {
+		sanitized = "_" + sanitized
+	}
+	if len(sanitized) > maxLength {
+		cut := 0
+		for i := range sanitized {
+			if i > maxLength {
+				break
+			}
+			cut = i
+		}
+		sanitized = strings.TrimRight(sanitized[:cut], " .")
+	}
+	if sanitized == "" {
+		return "_"
+	}
+	return sanitized
+}
+
+// nameValues 返回文件名各字段的值，time为空时表示匹配任意时间
+func nameValues(name ResultName, timestamp string) map[string]string {
+	host, _ := os.Hostname()
+	values := map[string]string{
+		"cmd":  SanitizeFilename(name.Command, maxNameFieldLength),
+		"ssid": "",
+		"host": SanitizeFilename(host, maxNameFieldLength),
+		"time": timestamp,
+	}
+	if name.SSID != "" {
+		values["ssid"] = SanitizeFilename(name.SSID, maxNameFieldLength)
+	}
+	return values
+}
+
+// expandStem 展开模板，字段由expand转换，字段之间的文本由literal转换
+func expandStem(stem string, values map[string]string, expand func(field string, value string) string, literal func(string) string) string {
+	// 值为空的字段连同前面（位于开头时为后面）的一个分隔符一起省略
+	for field, value := range values {
+		if value == "" && field != "time" {
+			omit := regexp.MustCompile(`[_\-. ]\{` + field + `\}|\{` + field + `\}[_\-. ]?`)
+			stem = omit.ReplaceAllString(stem, "")
+		}
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, match := range placeholderPattern.FindAllStringSubmatchIndex(stem, -1) {
+		builder.WriteString(literal(stem[last:match[0]]))
+		field := stem[match[2]:match[3]]
+		builder.WriteString(expand(field, values[field]))
+		last = match[1]
+	}
+	builder.WriteString(literal(stem[last:]))
+	return builder.String()
+}
+
+// resultFilename 返回不含序号的结果文件名
+func resultFilename(name ResultName, ext string, now time.Time) string {
+	stem, _ := nameStem(resultOptions.Template)
+	expanded := expandStem(stem, nameValues(name, now.Format(resultTimeFormat)),
+		func(field string, value string) string { return value },
+		replaceUnsafeRunes)
+	return SanitizeFilename(expanded, maxNameStemLength) + "." + ext
+}
+
+// writeResultFile 在结果目录中创建结果文件，文件已存在时追加序号
+func writeResultFile(name ResultName, ext string, data []byte) (string, error) {
+	filename := resultFilename(name, ext, time.Now())
+	return WriteNewFile(resultOptions.Dir, strings.TrimSuffix(filename, "."+ext), ext, data)
+}
+
+// WriteNewFile 在dir中以独占方式创建 stem.ext 并写入data，权限为仅当前用户可读写
+// 文件已存在时在扩展名前追加 _2、_3 等序号，不会覆盖已有文件，也不会沿用已有文件的权限
+func WriteNewFile(dir string, stem string, ext string, data []byte) (string, error) {
+	filename := stem + "." + ext
+	for n := 1; n <= maxNameCollisions; n++ {
+		candidate := filename
+		if n > 1 {
+			candidate = fmt.Sprintf("%s_%d.%s", stem, n, ext)
+		}
+		path := filepath.Join(dir, candidate)
+
+		// 文件可能包含密码等敏感信息，仅当前用户可读写
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("写入文件失败: %v", err)
+		}
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return "", fmt.Errorf("写入文件失败: %v", err)
+		}
+		return path, nil
+	}
+	return "", fmt.Errorf("写入文件失败: %s 及带序号的文件名都已存在", filename)
+}
+
+// SaveResultAs 将结果保存为指定扩展名的文件
+func SaveResultAs(name ResultName, ext string, content string) (string, error) {
+	return writeResultFile(name, ext, []byte(content))
+}
+
+// SaveEncryptedResult 加密后保存结果，扩展名为 ext.enc，encrypter为nil时按明文保存
+func SaveEncryptedResult(name ResultName, ext string, content string, encrypter Encrypter) (string, error) {
+	if encrypter == nil {
+		return SaveResultAs(name, ext, content)
+	}
+
+	data, err := encrypter.Encrypt([]byte(content))
+	if err != nil {
+		return "", fmt.Errorf("加密结果失败: %v", err)
+	}
+	return writeResultFile(name, ext+"."+EncryptedExt, data)
+}
+
+// PruneResults 按保留数量删除同一类结果中较旧的文件，返回删除的文件
+// 同一类结果指由相同模板和字段生成、扩展名为ext或加密后的ext.enc的文件，按修改时间从新到旧保留
+func PruneResults(name ResultName, ext string) ([]string, error) {
+	if resultOptions.Retain <= 0 {
+		return nil, nil
+	}
+
+	stem, _ := nameStem(resultOptions.Template)
+	pattern := expandStem(stem, nameValues(name, ""),
+		func(field string, value string) string {
+			if field == "time" {
+				return `\d{8}_\d{6}`
+			}
+			return regexp.QuoteMeta(value)
+		},
+		func(text string) string { return regexp.QuoteMeta(replaceUnsafeRunes(text)) })
+	matcher, err := regexp.Compile(`^` + pattern + `(_\d+)?\.` + regexp.QuoteMeta(ext) + `(\.` + EncryptedExt + `)?$`)
 	if err != nil {
-		return "", fmt.Errorf("写入文件失败: %v", err)
+		return nil, fmt.Errorf("清理旧结果失败: %v", err)
 	}
 
-	return filename, nil
+	entries, err := os.ReadDir(ResultDir())
+	if err != nil {
+		return nil, fmt.Errorf("清理旧结果失败: %v", err)
+	}
+	type resultFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []resultFile
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !matcher.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, resultFile{filepath.Join(resultOptions.Dir, entry.Name()), info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.After(files[j].modTime)
+		}
+		return files[i].path > files[j].path
+	})
+
+	var removed []string
+	for _, file := range files[min(resultOptions.Retain, len(files)):] {
+		if err := os.Remove(file.path); err != nil {
+			return removed, fmt.Errorf("删除旧结果失败: %v", err)
+		}
+		removed = append(removed, file.path)
+	}
+	return removed, nil
+}
+
+// ResultDirName 返回结果目录下带时间戳的目录路径，格式为: prefix_timestamp
+func ResultDirName(prefix string) string {
+	name := fmt.Sprintf("%s_%s", SanitizeFilename(prefix, maxNameFieldLength), time.Now().Format(resultTimeFormat))
+	return filepath.Join(resultOptions.Dir, name)
 }
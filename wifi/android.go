@@ -0,0 +1,311 @@
+package wifi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Android上WifiConfigStore.xml的默认位置（Android 11及以上）
+const DefaultAndroidConfigStore = "/data/misc/apexdata/com.android.wifi/WifiConfigStore.xml"
+
+// androidKeyMgmt Android WifiConfiguration.KeyMgmt位编号对应的身份验证名称
+var androidKeyMgmt = []string{
+	0:  "Open",
+	1:  "WPA-PSK",
+	2:  "WPA-EAP",
+	3:  "IEEE8021X",
+	4:  "WPA2-PSK",
+	5:  "OSEN",
+	6:  "WPA-PSK",
+	7:  "WPA-EAP",
+	8:  "WPA3-SAE",
+	9:  "OWE",
+	10: "WPA3-EAP-192",
+	11: "WPA-PSK",
+	12: "WPA-EAP",
+	13: "WAPI-PSK",
+	14: "WAPI-CERT",
+}
+
+// androidPairwiseCiphers Android WifiConfiguration.PairwiseCipher位编号对应的名称
+var androidPairwiseCiphers = []string{
+	0: "NONE",
+	1: "TKIP",
+	2: "CCMP",
+	3: "GCMP-256",
+	4: "SMS4",
+	5: "GCMP-128",
+}
+
+// androidEAPMethods Android WifiEnterpriseConfig.Eap编号对应的名称
+var androidEAPMethods = []string{"PEAP", "EAP-TLS", "EAP-TTLS", "EAP-PWD", "EAP-SIM", "EAP-AKA", "EAP-AKA'", "UNAUTH-TLS", "WAPI-CERT"}
+
+// androidPhase2Methods Android WifiEnterpriseConfig.Phase2编号对应的名称
+var androidPhase2Methods = []string{"", "PAP", "MSCHAP", "EAP-MSCHAPv2", "EAP-GTC", "EAP-SIM", "EAP-AKA", "EAP-AKA'"}
+
+// androidValue 表示WifiConfigStore.xml中一个带name属性的值
+type androidValue struct {
+	Kind  string // string、int、boolean、byte-array、null 等
+	Value string
+}
+
+// androidNetwork 表示一个Network元素中解析出的各部分配置
+type androidNetwork struct {
+	config     map[string]androidValue
+	enterprise map[string]androidValue
+}
+
+// ParseAndroidConfigStore 解析Android的WifiConfigStore.xml
+func ParseAndroidConfigStore(data []byte) ([]SavedWiFi, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var networks []SavedWiFi
+	var current *androidNetwork
+	var section map[string]androidValue
+	var valueName, valueKind string
+	var text strings.Builder
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析WifiConfigStore失败: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "Network":
+				current = &androidNetwork{
+					config:     make(map[string]androidValue),
+					enterprise: make(map[string]androidValue),
+				}
+			case "WifiConfiguration":
+				if current != nil {
+					section = current.config
+				}
+			case "WifiEnterpriseConfiguration":
+				if current != nil {
+					section = current.enterprise
+				}
+			case "item":
+				// string-array的元素，如WEPKeys，以空格分隔保存
+				if valueName != "" && valueKind == "string-array" {
+					if value := xmlAttr(t, "value"); value != "" {
+						text.WriteString(value + " ")
+					}
+				}
+			default:
+				if section == nil {
+					continue
+				}
+				valueName, valueKind = xmlAttr(t, "name"), t.Name.Local
+				text.Reset()
+				// int、boolean等类型的值保存在value属性中
+				if value := xmlAttr(t, "value"); value != "" {
+					section[valueName] = androidValue{Kind: valueKind, Value: value}
+					valueName = ""
+				}
+			}
+		case xml.CharData:
+			if valueName != "" {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "Network":
+				if current != nil {
+					networks = append(networks, current.toSavedWiFi())
+				}
+				current, section = nil, nil
+			case "WifiConfiguration", "WifiEnterpriseConfiguration":
+				section = nil
+			default:
+				if section != nil && valueName != "" && t.Name.Local == valueKind {
+					section[valueName] = androidValue{Kind: valueKind, Value: text.String()}
+					valueName = ""
+				}
+			}
+		}
+	}
+
+	return networks, nil
+}
+
+// xmlAttr 返回XML元素的属性值
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// unquoteAndroidString 去除Android配置中字符串两侧的引号，不带引号的值为十六进制编码
+func unquoteAndroidString(value string) (string, bool) {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1], false
+	}
+	return value, true
+}
+
+// androidBitSet 解析Java BitSet序列化的十六进制字节数组，返回已设置的位编号
+func androidBitSet(value string) []int {
+	raw, err := hex.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil
+	}
+	var bits []int
+	for i, b := range raw {
+		for bit := 0; bit < 8; bit++ {
+			if b&(1<<bit) != 0 {
+				bits = append(bits, i*8+bit)
+			}
+		}
+	}
+	return bits
+}
+
+// androidName 按编号从名称表中取值，超出范围时返回编号
+func androidName(names []string, index int) string {
+	if index >= 0 && index < len(names) && names[index] != "" {
+		return names[index]
+	}
+	return strconv.Itoa(index)
+}
+
+// toSavedWiFi 将Android网络配置转换为SavedWiFi记录
+func (n *androidNetwork) toSavedWiFi() SavedWiFi {
+	config := n.config
+	network := SavedWiFi{
+		ConnectionMode: ConnectionAuto,
+		NonBroadcast:   config["HiddenSSID"].Value == "true",
+		NetworkType:    "infrastructure",
+		Scope:          ScopeCurrentUser,
+	}
+
+	ssid, isHex := unquoteAndroidString(config["SSID"].Value)
+	if isHex {
+		if decoded, err := hex.DecodeString(ssid); err == nil {
+			ssid = string(decoded)
+		}
+	}
+	network.SSID = ssid
+	network.ProfileName = config["ConfigKey"].Value
+	if config["Shared"].Value == "true" {
+		network.Scope = ScopeAllUsers
+	}
+	if config["AllowAutojoin"].Value == "false" {
+		network.ConnectionMode = ConnectionManual
+	}
+	switch config["MacRandomizationSetting"].Value {
+	case "0":
+		network.MACRandomization = "禁用"
+	case "1":
+		network.MACRandomization = "持久随机"
+	case "2", "3":
+		network.MACRandomization = "每次随机"
+	}
+	if config["MeteredOverride"].Value == "1" || config["MeteredHint"].Value == "true" {
+		network.Cost = "按流量计费"
+	}
+
+	// 身份验证和加密方式
+	var wepKeys []string
+	if value, ok := config["WEPKeys"]; ok && value.Kind == "string-array" {
+		wepKeys = strings.Fields(value.Value)
+	}
+	var authentications []string
+	for _, bit := range androidBitSet(config["AllowedKeyMgmt"].Value) {
+		name := androidName(androidKeyMgmt, bit)
+		if name == "Open" && len(wepKeys) > 0 {
+			name = "WEP"
+		}
+		authentications = appendUnique(authentications, name)
+	}
+	network.Authentication = strings.Join(authentications, "/")
+	var ciphers []string
+	for _, bit := range androidBitSet(config["AllowedPairwiseCiphers"].Value) {
+		if name := androidName(androidPairwiseCiphers, bit); name != "NONE" {
+			ciphers = append(ciphers, name)
+		}
+	}
+	network.Cipher = strings.Join(ciphers, "/")
+
+	psk := config["PreSharedKey"].Value
+	switch {
+	case len(n.enterprise) > 0 && isEAPKeyMgmt(network.Authentication):
+		network.KeyStatus = KeyEnterprise
+		network.Enterprise = n.enterpriseConfig()
+	case psk != "":
+		key, isHex := unquoteAndroidString(psk)
+		network.KeyStatus = KeyPresent
+		network.Password = key
+		network.KeyType = "passPhrase"
+		if isHex {
+			network.KeyType = "networkKey"
+		}
+	case len(wepKeys) > 0:
+		key, _ := unquoteAndroidString(wepKeys[0])
+		network.KeyStatus = KeyPresent
+		network.Password = key
+		network.KeyType = "networkKey"
+	case network.Authentication == "" || network.Authentication == "Open" || network.Authentication == "OWE":
+		if network.Authentication == "" {
+			network.Authentication = "Open"
+		}
+		network.KeyStatus = KeyAbsent
+	default:
+		network.KeyStatus = KeyUnknown
+	}
+
+	return network
+}
+
+// enterpriseConfig 解析WifiEnterpriseConfiguration中的802.1X设置
+func (n *androidNetwork) enterpriseConfig() *EnterpriseConfig {
+	values := n.enterprise
+	config := &EnterpriseConfig{ValidationKnown: true}
+	if method, err := strconv.Atoi(values["EapMethod"].Value); err == nil {
+		config.EAPMethod = androidName(androidEAPMethods, method)
+	}
+	if phase2, err := strconv.Atoi(values["Phase2Method"].Value); err == nil && phase2 > 0 {
+		config.InnerMethod = androidName(androidPhase2Methods, phase2)
+	}
+	for _, key := range []string{"CaCert", "CaPath"} {
+		if value := values[key].Value; value != "" {
+			config.TrustedRootCAs = append(config.TrustedRootCAs, value)
+		}
+	}
+	config.ServerValidation = len(config.TrustedRootCAs) > 0
+	for _, key := range []string{"DomSuffixMatch", "DomainMatch", "SubjectMatch", "AltSubjectMatch"} {
+		if value := values[key].Value; value != "" {
+			config.ServerNames = value
+			break
+		}
+	}
+	if values["ClientCert"].Value != "" {
+		config.CredentialSource = "certificate"
+	} else if values["Identity"].Value != "" {
+		config.CredentialSource = "user"
+	}
+	return config
+}
+
+// LoadAndroidConfigStore 从文件加载Android已保存的网络
+func LoadAndroidConfigStore(path string) ([]SavedWiFi, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	return ParseAndroidConfigStore(data)
+}
@@ -0,0 +1,113 @@
+package wifi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// androidConfigStoreSample 为Android 11及以上WifiConfigStore.xml的片段，省略了与解析无关的元素
+const androidConfigStoreSample = `<?xml version='1.0' encoding='utf-8' standalone='yes' ?>
+<WifiConfigStoreData>
+<int name="Version" value="3" />
+<NetworkList>
+<Network>
+<WifiConfiguration>
+<string name="ConfigKey">&quot;HomeNet&quot;WPA_PSK</string>
+<string name="SSID">&quot;HomeNet&quot;</string>
+<null name="BSSID" />
+<string name="PreSharedKey">&quot;pa&amp;ss &lt;word&gt;&quot;</string>
+<null name="WEPKeys" />
+<int name="WEPTxKeyIndex" value="0" />
+<boolean name="HiddenSSID" value="false" />
+<byte-array name="AllowedKeyMgmt" num="1">02</byte-array>
+<byte-array name="AllowedProtocols" num="1">03</byte-array>
+<byte-array name="AllowedPairwiseCiphers" num="1">06</byte-array>
+<boolean name="Shared" value="true" />
+<int name="MeteredOverride" value="0" />
+<int name="MacRandomizationSetting" value="1" />
+</WifiConfiguration>
+<NetworkStatus>
+<string name="SelectionStatus">NETWORK_SELECTION_ENABLED</string>
+</NetworkStatus>
+<IpConfiguration>
+<string name="IpAssignment">DHCP</string>
+</IpConfiguration>
+</Network>
+<Network>
+<WifiConfiguration>
+<string name="ConfigKey">e58a9ee585ace5aea4NONE</string>
+<string name="SSID">e58a9ee585ace5aea4</string>
+<boolean name="HiddenSSID" value="true" />
+<byte-array name="AllowedKeyMgmt" num="1">01</byte-array>
+<boolean name="Shared" value="false" />
+<int name="MacRandomizationSetting" value="0" />
+</WifiConfiguration>
+</Network>
+<Network>
+<WifiConfiguration>
+<string name="ConfigKey">&quot;OldPrinter&quot;WEP</string>
+<string name="SSID">&quot;OldPrinter&quot;</string>
+<string-array name="WEPKeys" num="4">
+<item value="&quot;abcde&quot;" />
+<item value="" />
+<item value="" />
+<item value="" />
+</string-array>
+<byte-array name="AllowedKeyMgmt" num="1">01</byte-array>
+<boolean name="Shared" value="true" />
+</WifiConfiguration>
+</Network>
+<Network>
+<WifiConfiguration>
+<string name="ConfigKey">&quot;eduroam&quot;WPA_EAP IEEE8021X</string>
+<string name="SSID">&quot;eduroam&quot;</string>
+<byte-array name="AllowedKeyMgmt" num="1">0c</byte-array>
+<boolean name="Shared" value="true" />
+<boolean name="AllowAutojoin" value="false" />
+<boolean name="MeteredHint" value="true" />
+</WifiConfiguration>
+<WifiEnterpriseConfiguration>
+<string name="Identity">alice@example.edu</string>
+<string name="AnonIdentity">anonymous@example.edu</string>
+<string name="Password">hunter2</string>
+<string name="CaCert"></string>
+<string name="DomSuffixMatch">radius.example.edu</string>
+<int name="EapMethod" value="0" />
+<int name="Phase2Method" value="3" />
+</WifiEnterpriseConfiguration>
+</Network>
+</NetworkList>
+</WifiConfigStoreData>
+`
+
+func TestParseAndroidConfigStore(t *testing.T) {
+	want := []SavedWiFi{
+		{SSID: "HomeNet", Password: "pa&ss <word>", KeyStatus: KeyPresent, ProfileName: `"HomeNet"WPA_PSK`, Scope: ScopeAllUsers,
+			Authentication: "WPA-PSK", Cipher: "TKIP/CCMP", KeyType: "passPhrase", ConnectionMode: ConnectionAuto,
+			MACRandomization: "持久随机", NetworkType: "infrastructure"},
+		{SSID: "办公室", KeyStatus: KeyAbsent, ProfileName: "e58a9ee585ace5aea4NONE", Scope: ScopeCurrentUser,
+			Authentication: "Open", ConnectionMode: ConnectionAuto, NonBroadcast: true, MACRandomization: "禁用",
+			NetworkType: "infrastructure"},
+		{SSID: "OldPrinter", Password: "abcde", KeyStatus: KeyPresent, ProfileName: `"OldPrinter"WEP`, Scope: ScopeAllUsers,
+			Authentication: "WEP", KeyType: "networkKey", ConnectionMode: ConnectionAuto, NetworkType: "infrastructure"},
+		{SSID: "eduroam", KeyStatus: KeyEnterprise, ProfileName: `"eduroam"WPA_EAP IEEE8021X`, Scope: ScopeAllUsers,
+			Authentication: "WPA-EAP/IEEE8021X", ConnectionMode: ConnectionManual, Cost: "按流量计费", NetworkType: "infrastructure",
+			Enterprise: &EnterpriseConfig{
+				EAPMethod: "PEAP", InnerMethod: "EAP-MSCHAPv2", ValidationKnown: true,
+				ServerNames: "radius.example.edu", CredentialSource: "user",
+			}},
+	}
+
+	got, err := ParseAndroidConfigStore([]byte(androidConfigStoreSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("解析到 %d 个网络，期望 %d 个", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("网络 #%d = %+v，期望 %+v", i+1, got[i], want[i])
+		}
+	}
+}
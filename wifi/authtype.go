@@ -0,0 +1,141 @@
+package wifi
+
+import (
+	"strings"
+)
+
+// AuthType 表示归一化后的身份验证类型
+// netsh、WLAN配置文件XML、NetworkManager、Android等来源的身份验证名称各不相同，导出和审计时统一按此类型处理
+type AuthType int
+
+const (
+	AuthUnknown AuthType = iota
+	AuthOpen
+	AuthOWE
+	AuthWEP
+	AuthWPAPSK
+	AuthWPA2PSK
+	AuthWPA3SAE
+	AuthWPAEAP
+	AuthWPA2EAP
+	AuthWPA3EAP
+)
+
+// String 返回身份验证类型的显示名称
+func (a AuthType) String() string {
+	switch a {
+	case AuthOpen:
+		return "Open"
+	case AuthOWE:
+		return "OWE"
+	case AuthWEP:
+		return "WEP"
+	case AuthWPAPSK:
+		return "WPA-PSK"
+	case AuthWPA2PSK:
+		return "WPA2-PSK"
+	case AuthWPA3SAE:
+		return "WPA3-SAE"
+	case AuthWPAEAP:
+		return "WPA-EAP"
+	case AuthWPA2EAP:
+		return "WPA2-EAP"
+	case AuthWPA3EAP:
+		return "WPA3-EAP"
+	}
+	return "未知"
+}
+
+// IsPersonal 判断是否为使用预共享密钥的个人认证
+func (a AuthType) IsPersonal() bool {
+	return a == AuthWEP || a == AuthWPAPSK || a == AuthWPA2PSK || a == AuthWPA3SAE
+}
+
+// IsEnterprise 判断是否为802.1X企业认证
+func (a AuthType) IsEnterprise() bool {
+	return a == AuthWPAEAP || a == AuthWPA2EAP || a == AuthWPA3EAP
+}
+
+// parseAuthPart 解析单个身份验证名称
+func parseAuthPart(part string) AuthType {
+	lower := strings.ToLower(part)
+	for _, sep := range []string{"-", "_", " "} {
+		lower = strings.ReplaceAll(lower, sep, "")
+	}
+
+	switch {
+	case lower == "":
+		return AuthUnknown
+	case lower == "open" || lower == "none" || strings.Contains(lower, "开放式") || lower == "开放":
+		return AuthOpen
+	case strings.Contains(lower, "owe") || strings.Contains(lower, "enhancedopen") || strings.Contains(lower, "增强型开放"):
+		return AuthOWE
+	case strings.Contains(lower, "wep") || lower == "shared":
+		return AuthWEP
+	case strings.Contains(lower, "sae"):
+		return AuthWPA3SAE
+	}
+
+	personal := strings.Contains(lower, "psk") || strings.Contains(lower, "personal") || strings.Contains(lower, "个人")
+	switch {
+	case strings.Contains(lower, "wpa3") && personal:
+		return AuthWPA3SAE
+	case strings.Contains(lower, "wpa3"):
+		return AuthWPA3EAP
+	case strings.Contains(lower, "wpa2") && personal:
+		return AuthWPA2PSK
+	case strings.Contains(lower, "wpa") && personal:
+		return AuthWPAPSK
+	case strings.Contains(lower, "wpa2"):
+		return AuthWPA2EAP
+	case strings.Contains(lower, "wpa") || strings.Contains(lower, "eap") || strings.Contains(lower, "8021x"):
+		return AuthWPAEAP
+	}
+	return AuthUnknown
+}
+
+// ParseAuthType 将各来源的身份验证名称归一化，多个值用"/"分隔时取最强的一种
+// WPA2-PSK与WPA3-SAE过渡模式按WPA2-PSK处理，以便导出的配置在旧设备上也能连接
+func ParseAuthType(authentication string) AuthType {
+	return strongestAuthType(authentication, func(AuthType) bool { return true })
+}
+
+// strongestAuthType 返回满足条件的最强身份验证类型
+func strongestAuthType(authentication string, accept func(AuthType) bool) AuthType {
+	best := AuthUnknown
+	hasWPA2PSK := false
+	for _, part := range strings.Split(authentication, "/") {
+		auth := parseAuthPart(strings.TrimSpace(part))
+		if !accept(auth) {
+			continue
+		}
+		if auth == AuthWPA2PSK || auth == AuthWPAPSK {
+			hasWPA2PSK = true
+		}
+		if auth > best {
+			best = auth
+		}
+	}
+	if best == AuthWPA3SAE && hasWPA2PSK {
+		return AuthWPA2PSK
+	}
+	return best
+}
+
+// AuthType 返回已保存网络归一化后的身份验证类型
+// wpa_supplicant等来源可能同时列出PSK和EAP，按是否包含802.1X配置选择其中一类
+func (s SavedWiFi) AuthType() AuthType {
+	auth := strongestAuthType(s.Authentication, func(a AuthType) bool { return a.IsEnterprise() == s.IsEnterprise() })
+	if auth == AuthUnknown {
+		auth = ParseAuthType(s.Authentication)
+	}
+	switch {
+	case auth == AuthOpen && strings.Contains(strings.ToUpper(s.Cipher), "WEP"):
+		// Windows配置文件中WEP网络的身份验证为open，加密方式为WEP
+		return AuthWEP
+	case auth == AuthUnknown && s.IsEnterprise():
+		// 部分来源只标记了802.1X而没有给出身份验证名称
+		return AuthWPA2EAP
+	}
+	return auth
+}
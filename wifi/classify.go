@@ -0,0 +1,196 @@
+package wifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 内置网络分类
+const (
+	ClassISPRouter     = "isp-router"
+	ClassHomeRouter    = "home-router"
+	ClassMobileHotspot = "mobile-hotspot"
+	ClassIoTSetup      = "iot-setup"
+	ClassWiFiDirect    = "wifi-direct"
+	ClassGuest         = "guest"
+)
+
+// classLabels 内置分类的显示名称
+var classLabels = map[string]string{
+	ClassISPRouter:     "运营商路由器",
+	ClassHomeRouter:    "家用路由器",
+	ClassMobileHotspot: "手机热点",
+	ClassIoTSetup:      "IoT配网热点",
+	ClassWiFiDirect:    "WiFi直连设备",
+	ClassGuest:         "访客网络",
+}
+
+// ClassRule 表示一条网络分类规则，所有已设置的条件都满足时规则匹配
+type ClassRule struct {
+	Class    string   `json:"class"`              // 分类名称
+	SSID     string   `json:"ssid,omitempty"`     // SSID正则表达式
+	OUI      []string `json:"oui,omitempty"`      // BSSID厂商前缀，如 "24:0A:C4"
+	Security string   `json:"security,omitempty"` // 安全类型正则表达式
+
+	ssidRe     *regexp.Regexp
+	securityRe *regexp.Regexp
+	ouis       map[string]bool
+}
+
+// defaultClassRules 内置分类规则，按顺序匹配
+var defaultClassRules = []ClassRule{
+	{Class: ClassWiFiDirect, SSID: `^DIRECT-`},
+	{Class: ClassIoTSetup, SSID: `(?i)^(HP-Setup|Setup-|ESP[_-]|ESP32|Tuya|SmartLife|SL-|Sonoff|ITEAD-|XMCamera|EZVIZ|HIK-|Hikvision|Broadlink|midea_|Wyze|Ring Setup|Nest-|Chromecast|Amazon-|Roku-)|_miap[0-9a-f]{4}$|\.setup$`},
+	// 乐鑫（Espressif）芯片常见于各类IoT设备，开放网络通常为配网热点
+	{Class: ClassIoTSetup, OUI: []string{"18:FE:34", "24:0A:C4", "30:AE:A4", "5C:CF:7F", "60:01:94", "84:CC:A8", "A4:CF:12", "EC:FA:BC"}, Security: `(?i)^(open|开放)`},
+	// 手机热点按系统默认名称匹配，品牌名后必须跟型号，避免Galaxy Hotel、PixelStudio等名称被误判；
+	// 同一品牌的路由器默认SSID用连字符或下划线连接（如HONOR-A1B2、Redmi_5G），由家用路由器规则匹配
+	{Class: ClassMobileHotspot, SSID: `\b(iPhone|iPad)\b|^Android(AP|_[0-9A-Za-z]{4}$)|\bGalaxy (S|A|M|Note|Tab ?[AS]?|Z ?(Flip|Fold))[0-9]*\b|\bRedmi (Note ?[0-9]+|K[0-9]+|[0-9]+[A-Z]?)\b|\bOPPO (Reno|Find|[AFK][0-9])|\bvivo [XYSTU][0-9]+|\biQOO\b|\bOnePlus ?([0-9]+|Nord)\b|\b(HONOR|Honor) ([0-9]+|Magic|X[0-9])|\bPixel ?[0-9]+a?\b|\bHUAWEI (P[0-9]+|Mate ?[0-9X]+|nova ?[0-9]+)|\bXiaomi ?1[0-9]\b|的手机|的热点`},
+	// 运营商默认SSID区分大小写，SKY和VM后为固定长度的编号，避免Skylights等名称被误判
+	{Class: ClassISPRouter, SSID: `^(ChinaNet|CMCC|CU_|ChinaUnicom|China ?Mobile|China ?Telecom|@ChinaNet|xfinitywifi|BTHub|BT-|SKY[0-9A-Z]{5}$|VM[0-9]{7}$|Livebox-|Orange-|FRITZ!Box|Vodafone|Telstra|Optus)`},
+	// public只在作为独立的词出现时匹配，避免把PublicWorks等内部网络当作访客网络
+	{Class: ClassGuest, SSID: `(?i)(guest|visitor|访客|来宾|free[-_ ]?wi-?fi|(^|[-_ ])public([-_ ]?wi-?fi)?([-_ ]|$))`},
+	// 厂商名后必须紧跟分隔符或结束，避免FastFood_Office、ASUSTeK-Lab等名称被误判；NETGEAR和Linksys的默认SSID为厂商名加数字
+	{Class: ClassHomeRouter, SSID: `(?i)^((TP-LINK|MERCURY|FAST|Tenda|Xiaomi|Redmi|HUAWEI|HONOR|H3C|NETGEAR|ASUS|Linksys|D-Link|dlink)([_-]|$)|(NETGEAR|Linksys)[0-9]{2,5}([_-]|$))`},
+}
+
+// Classifier 根据规则对网络进行分类
+type Classifier struct {
+	rules []ClassRule
+}
+
+// compile 编译规则中的正则表达式和厂商前缀
+func (r *ClassRule) compile() error {
+	if r.Class == "" {
+		return fmt.Errorf("规则缺少分类名称")
+	}
+	if r.SSID == "" && len(r.OUI) == 0 && r.Security == "" {
+		return fmt.Errorf("规则 %s 没有任何匹配条件", r.Class)
+	}
+
+	var err error
+	if r.SSID != "" {
+		if r.ssidRe, err = regexp.Compile(r.SSID); err != nil {
+			return fmt.Errorf("规则 %s 的SSID正则表达式无效: %v", r.Class, err)
+		}
+	}
+	if r.Security != "" {
+		if r.securityRe, err = regexp.Compile(r.Security); err != nil {
+			return fmt.Errorf("规则 %s 的安全类型正则表达式无效: %v", r.Class, err)
+		}
+	}
+	if len(r.OUI) > 0 {
+		r.ouis = make(map[string]bool)
+		for _, oui := range r.OUI {
+			r.ouis[normalizeOUI(oui)] = true
+		}
+	}
+	return nil
+}
+
+// Match 判断网络是否满足规则的所有条件
+func (r *ClassRule) Match(network WiFiNetwork) bool {
+	if r.ssidRe != nil && !r.ssidRe.MatchString(network.SSID) {
+		return false
+	}
+	if r.securityRe != nil && !r.securityRe.MatchString(network.Security) {
+		return false
+	}
+	if r.ouis != nil && !r.ouis[normalizeOUI(network.BSSID)] {
+		return false
+	}
+	return true
+}
+
+// normalizeOUI 提取MAC地址的前3个字节作为厂商前缀，统一为大写冒号分隔格式
+func normalizeOUI(mac string) string {
+	hex := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r >= 'A' && r <= 'F':
+			return r
+		case r >= 'a' && r <= 'f':
+			return r - 'a' + 'A'
+		}
+		return -1
+	}, mac)
+	if len(hex) < 6 {
+		return ""
+	}
+	return hex[0:2] + ":" + hex[2:4] + ":" + hex[4:6]
+}
+
+// NewClassifier 创建分类器，用户规则优先于内置规则匹配
+func NewClassifier(userRules []ClassRule) (*Classifier, error) {
+	classifier := &Classifier{}
+	for _, rule := range append(append([]ClassRule(nil), userRules...), defaultClassRules...) {
+		if err := rule.compile(); err != nil {
+			return nil, err
+		}
+		classifier.rules = append(classifier.rules, rule)
+	}
+	return classifier, nil
+}
+
+// LoadClassRules 从JSON文件加载用户分类规则
+func LoadClassRules(path string) ([]ClassRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取分类规则文件失败: %v", err)
+	}
+	var rules []ClassRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("解析分类规则文件失败: %v", err)
+	}
+	return rules, nil
+}
+
+// Classify 返回网络的分类，未匹配任何规则时返回空字符串
+func (c *Classifier) Classify(network WiFiNetwork) string {
+	for i := range c.rules {
+		if c.rules[i].Match(network) {
+			return c.rules[i].Class
+		}
+	}
+	return ""
+}
+
+// ClassifyNetworks 为每个网络设置分类
+func (c *Classifier) ClassifyNetworks(networks []WiFiNetwork) {
+	for i := range networks {
+		networks[i].Class = c.Classify(networks[i])
+	}
+}
+
+// FilterNetworksByClass 只保留属于指定分类的网络，"other"表示未分类的网络
+func FilterNetworksByClass(networks []WiFiNetwork, classes []string) []WiFiNetwork {
+	wanted := make(map[string]bool)
+	for _, class := range classes {
+		class = strings.TrimSpace(class)
+		if class == "other" {
+			class = ""
+		}
+		wanted[class] = true
+	}
+
+	var filtered []WiFiNetwork
+	for _, network := range networks {
+		if wanted[network.Class] {
+			filtered = append(filtered, network)
+		}
+	}
+	return filtered
+}
+
+// ClassLabel 返回分类的显示名称，用户自定义分类直接使用分类名称
+func ClassLabel(class string) string {
+	if class == "" {
+		return "未分类"
+	}
+	if label, ok := classLabels[class]; ok {
+		return label
+	}
+	return class
+}
@@ -0,0 +1,65 @@
+package wifi
+
+import "testing"
+
+func TestDefaultClassRules(t *testing.T) {
+	classifier, err := NewClassifier(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ssid     string
+		bssid    string
+		security string
+		want     string
+	}{
+		{"DIRECT-4A-HP OfficeJet", "", "WPA2-Personal", ClassWiFiDirect},
+		{"ESP_1A2B3C", "", "Open", ClassIoTSetup},
+		{"", "24:0A:C4:11:22:33", "Open", ClassIoTSetup},
+		{"Sensor", "24:0A:C4:11:22:33", "WPA2-Personal", ""},
+		{"iPhone 15", "", "WPA2-Personal", ClassMobileHotspot},
+		{"张三的手机", "", "WPA2-Personal", ClassMobileHotspot},
+		{"ChinaNet-AbCd", "", "WPA2-Personal", ClassISPRouter},
+		{"CMCC-5G", "", "WPA2-Personal", ClassISPRouter},
+		{"Company-Guest", "", "Open", ClassGuest},
+		{"Library_Public", "", "Open", ClassGuest},
+		{"Public WiFi", "", "Open", ClassGuest},
+		{"PublicWorks", "", "WPA2-Personal", ""},
+		{"Republic", "", "WPA2-Personal", ""},
+		{"TP-LINK_5G_1A2B", "", "WPA2-Personal", ClassHomeRouter},
+		{"FAST_8A3C", "", "WPA2-Personal", ClassHomeRouter},
+		{"ASUS", "", "WPA2-Personal", ClassHomeRouter},
+		{"ASUS_5G", "", "WPA2-Personal", ClassHomeRouter},
+		{"NETGEAR45", "", "WPA2-Personal", ClassHomeRouter},
+		{"Linksys01234", "", "WPA2-Personal", ClassHomeRouter},
+		{"HUAWEI-A1B2", "", "WPA2-Personal", ClassHomeRouter},
+		{"FastFood_Office", "", "WPA2-Personal", ""},
+		{"ASUSTeK-Lab", "", "WPA2-Personal", ""},
+		{"Tendance", "", "WPA2-Personal", ""},
+		{"Tom's iPhone", "", "WPA2-Personal", ClassMobileHotspot},
+		{"AndroidAP_3F2A", "", "WPA2-Personal", ClassMobileHotspot},
+		{"Galaxy S23 Ultra", "", "WPA2-Personal", ClassMobileHotspot},
+		{"Galaxy Z Flip5", "", "WPA2-Personal", ClassMobileHotspot},
+		{"Redmi Note 12", "", "WPA2-Personal", ClassMobileHotspot},
+		{"OPPO Reno8", "", "WPA2-Personal", ClassMobileHotspot},
+		{"HONOR 90", "", "WPA2-Personal", ClassMobileHotspot},
+		{"Pixel 7a", "", "WPA2-Personal", ClassMobileHotspot},
+		{"HUAWEI Mate 60", "", "WPA2-Personal", ClassMobileHotspot},
+		{"HONOR-A1B2", "", "WPA2-Personal", ClassHomeRouter},
+		{"Redmi_5G", "", "WPA2-Personal", ClassHomeRouter},
+		{"Opportunity-Corp", "", "WPA2-Personal", ""},
+		{"Galaxy Hotel", "", "WPA2-Personal", ""},
+		{"PixelStudio", "", "WPA2-Personal", ""},
+		{"Android Devs", "", "WPA2-Personal", ""},
+		{"SKY1A2B3", "", "WPA2-Personal", ClassISPRouter},
+		{"Skylights", "", "WPA2-Personal", ""},
+		{"SKYLIGHTS", "", "WPA2-Personal", ""},
+		{"chinanet-lab", "", "WPA2-Personal", ""},
+	}
+	for _, test := range tests {
+		network := WiFiNetwork{SSID: test.ssid, BSSID: test.bssid, Security: test.security}
+		if got := classifier.Classify(network); got != test.want {
+			t.Errorf("Classify(%q, %q, %q) = %q，期望 %q", test.ssid, test.bssid, test.security, got, test.want)
+		}
+	}
+}
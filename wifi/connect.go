@@ -0,0 +1,74 @@
+package wifi
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// addWLANProfile 校验配置文件后通过netsh添加到系统，同名配置文件会被覆盖
+func addWLANProfile(profile *WLANProfile) error {
+	if err := profile.Validate(); err != nil {
+		return fmt.Errorf("配置文件无效: %v", err)
+	}
+	profileXML, err := profile.Marshal()
+	if err != nil {
+		return err
+	}
+
+	// 创建临时文件，添加后立即删除
+	file, err := os.CreateTemp("", "wifisos_*.xml")
+	if err != nil {
+		return fmt.Errorf("创建临时配置文件失败: %v", err)
+	}
+	tempFile := file.Name()
+	defer os.Remove(tempFile)
+	_, err = file.Write(profileXML)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("创建临时配置文件失败: %v", err)
+	}
+
+	addCmd := exec.Command("netsh", "wlan", "add", "profile", "filename="+tempFile)
+	if output, err := addCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("添加WiFi配置文件失败: %v, 输出: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ConnectNetwork 保存网络配置并连接
+// Windows上通过netsh添加WLAN配置文件后连接，其他系统通过nmcli连接
+func ConnectNetwork(network SavedWiFi) error {
+	if runtime.GOOS == "windows" {
+		profile, err := NewWLANProfile(network)
+		if err != nil {
+			return fmt.Errorf("创建配置文件失败: %v", err)
+		}
+		if err := addWLANProfile(profile); err != nil {
+			return err
+		}
+		connectCmd := exec.Command("netsh", "wlan", "connect", "name="+profile.Name)
+		if output, err := connectCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("连接失败: %v, 输出: %s", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	if network.IsEnterprise() {
+		return fmt.Errorf("802.1X企业网络需要身份和凭据，请导出配置后手动连接")
+	}
+	args := []string{"device", "wifi", "connect", network.SSID}
+	if network.HasPassword() {
+		args = append(args, "password", network.Password)
+	}
+	if network.NonBroadcast {
+		args = append(args, "hidden", "yes")
+	}
+	connectCmd := exec.Command("nmcli", args...)
+	if output, err := connectCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("连接失败: %v, 输出: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+package wifi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// EnterpriseConfig 表示802.1X企业认证配置
+type EnterpriseConfig struct {
+	EAPMethod        string   // 外层EAP方法，如 PEAP、EAP-TLS、EAP-TTLS
+	InnerMethod      string   // 内层认证方法，如 EAP-MSCHAPv2
+	ServerValidation bool     // 是否验证服务器证书
+	ValidationKnown  bool     // 是否获取到服务器证书验证设置
+	PromptDisabled   bool     // 是否禁止提示用户信任新服务器
+	ServerNames      string   // 允许连接的RADIUS服务器名称
+	TrustedRootCAs   []string // 受信任的根CA证书指纹（Windows）或CA证书路径（Linux）
+	CredentialSource string   // 认证凭据来源，如用户凭据、计算机凭据
+}
+
+// ValidationDisabled 判断是否明确关闭了服务器证书验证
+func (e *EnterpriseConfig) ValidationDisabled() bool {
+	return e.ValidationKnown && !e.ServerValidation
+}
+
+// Warnings 返回企业认证配置中的常见安全问题
+func (e *EnterpriseConfig) Warnings() []string {
+	var warnings []string
+	if e.ValidationDisabled() {
+		warnings = append(warnings, "未验证服务器证书，可被伪造AP窃取凭据")
+	} else if e.ValidationKnown && len(e.TrustedRootCAs) == 0 && e.EAPMethod != "EAP-TLS" {
+		warnings = append(warnings, "未指定受信任的根CA")
+	}
+	if e.ValidationKnown && e.ServerNames == "" && !e.ValidationDisabled() {
+		warnings = append(warnings, "未限制RADIUS服务器名称")
+	}
+	return warnings
+}
+
+// eapMethodNames EAP方法编号对应的名称
+var eapMethodNames = map[int]string{
+	4:  "EAP-MD5",
+	6:  "EAP-GTC",
+	13: "EAP-TLS",
+	17: "LEAP",
+	18: "EAP-SIM",
+	21: "EAP-TTLS",
+	23: "EAP-AKA",
+	25: "PEAP",
+	26: "EAP-MSCHAPv2",
+	43: "EAP-FAST",
+	50: "EAP-AKA'",
+}
+
+// eapMethodName 返回EAP方法编号对应的名称
+func eapMethodName(code int) string {
+	if name, ok := eapMethodNames[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("EAP-%d", code)
+}
+
+// parseEAPTypeText 解析netsh输出中"EAP 类型"一行的EAP方法
+func parseEAPTypeText(text string) string {
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "peap"):
+		return "PEAP"
+	case strings.Contains(lower, "ttls"):
+		return "EAP-TTLS"
+	case strings.Contains(lower, "smart card") || strings.Contains(text, "智能卡") || strings.Contains(lower, "eap-tls"):
+		return "EAP-TLS"
+	case strings.Contains(lower, "eap-sim"):
+		return "EAP-SIM"
+	case strings.Contains(lower, "aka'"):
+		return "EAP-AKA'"
+	case strings.Contains(lower, "eap-aka"):
+		return "EAP-AKA"
+	}
+	return strings.TrimSpace(text)
+}
+
+// parseEAPConfigXML 从WLAN配置文件XML中解析802.1X配置
+// 不同EAP方法使用不同的命名空间和元素，这里按元素本地名称遍历以兼容各版本
+func parseEAPConfigXML(data []byte) (*EnterpriseConfig, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	config := &EnterpriseConfig{}
+	found := false
+	validationSeen := false
+
+	var path []string
+	var methods []int
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析EAP配置失败: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			// TTLS的内层认证方法以元素名表示，如 <MSCHAPv2Authentication/>
+			if len(path) > 0 && path[len(path)-1] == "Phase2Authentication" && config.InnerMethod == "" {
+				config.InnerMethod = strings.TrimSuffix(name, "Authentication")
+			}
+			if name == "EAPConfig" || name == "OneX" {
+				found = true
+			}
+			if name == "ServerValidation" {
+				validationSeen = true
+			}
+			path = append(path, name)
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		case xml.CharData:
+			if len(path) == 0 || !found {
+				continue
+			}
+			value := strings.TrimSpace(string(t))
+			if value == "" {
+				continue
+			}
+			switch path[len(path)-1] {
+			case "Type":
+				if code, err := strconv.Atoi(value); err == nil {
+					methods = append(methods, code)
+				}
+			case "PerformServerValidation":
+				config.ValidationKnown = true
+				config.ServerValidation = parseXMLBool(value)
+			case "DisableUserPromptForServerValidation", "DisablePrompt":
+				config.PromptDisabled = parseXMLBool(value)
+			case "ServerNames":
+				config.ServerNames = value
+			case "TrustedRootCA", "TrustedRootCAHash":
+				config.TrustedRootCAs = appendUnique(config.TrustedRootCAs, normalizeThumbprint(value))
+			case "authMode":
+				config.CredentialSource = value
+			}
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	// 第一个EAP方法为外层方法，之后第一个不同的方法为内层方法
+	if len(methods) > 0 {
+		config.EAPMethod = eapMethodName(methods[0])
+		for _, code := range methods[1:] {
+			if code != methods[0] {
+				config.InnerMethod = eapMethodName(code)
+				break
+			}
+		}
+	}
+
+	// 包含服务器验证设置但没有PerformServerValidation元素时，按默认的验证处理
+	if !config.ValidationKnown && (validationSeen || config.ServerNames != "" || len(config.TrustedRootCAs) > 0) {
+		config.ValidationKnown = true
+		config.ServerValidation = true
+	}
+	return config, nil
+}
+
+// parseXMLBool 解析XML中的布尔值
+func parseXMLBool(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return value == "true" || value == "1"
+}
+
+// normalizeThumbprint 将证书指纹统一为大写、空格分隔的十六进制格式
+func normalizeThumbprint(value string) string {
+	hex := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	var parts []string
+	for i := 0; i+2 <= len(hex); i += 2 {
+		parts = append(parts, hex[i:i+2])
+	}
+	return strings.Join(parts, " ")
+}
+
+// EAP方法编号及证书指纹长度
+const (
+	eapTypeTLS          = 13
+	eapTypePEAP         = 25
+	eapTypeMSCHAPv2     = 26
+	thumbprintHexLength = 40
+)
+
+// eapServerValidation 表示PEAP和EAP-TLS共用的服务器证书验证设置
+type eapServerValidation struct {
+	DisableUserPromptForServerValidation bool     `xml:"DisableUserPromptForServerValidation"`
+	ServerNames                          string   `xml:"ServerNames"`
+	TrustedRootCA                        []string `xml:"TrustedRootCA"`
+}
+
+// peapMethodConfig 表示PEAP方法配置，内层固定为EAP-MSCHAPv2
+type peapMethodConfig struct {
+	XMLName xml.Name `xml:"http://www.microsoft.com/provisioning/BaseEapConnectionPropertiesV1 Eap"`
+	Type    int      `xml:"Type"`
+	EapType struct {
+		XMLName          xml.Name            `xml:"http://www.microsoft.com/provisioning/MsPeapConnectionPropertiesV1 EapType"`
+		ServerValidation eapServerValidation `xml:"ServerValidation"`
+		FastReconnect    bool                `xml:"FastReconnect"`
+		InnerEapOptional bool                `xml:"InnerEapOptional"`
+		Eap              struct {
+			XMLName xml.Name `xml:"http://www.microsoft.com/provisioning/BaseEapConnectionPropertiesV1 Eap"`
+			Type    int      `xml:"Type"`
+			EapType struct {
+				XMLName                xml.Name `xml:"http://www.microsoft.com/provisioning/MsChapV2ConnectionPropertiesV1 EapType"`
+				UseWinLogonCredentials bool     `xml:"UseWinLogonCredentials"`
+			}
+		}
+		EnableQuarantineChecks bool `xml:"EnableQuarantineChecks"`
+		RequireCryptoBinding   bool `xml:"RequireCryptoBinding"`
+		PeapExtensions         struct {
+			PerformServerValidation bool `xml:"http://www.microsoft.com/provisioning/MsPeapConnectionPropertiesV2 PerformServerValidation"`
+			AcceptServerName        bool `xml:"http://www.microsoft.com/provisioning/MsPeapConnectionPropertiesV2 AcceptServerName"`
+		} `xml:"PeapExtensions"`
+	}
+}
+
+// tlsMethodConfig 表示EAP-TLS方法配置，使用证书存储中的用户证书
+type tlsMethodConfig struct {
+	XMLName xml.Name `xml:"http://www.microsoft.com/provisioning/BaseEapConnectionPropertiesV1 Eap"`
+	Type    int      `xml:"Type"`
+	EapType struct {
+		XMLName           xml.Name `xml:"http://www.microsoft.com/provisioning/EapTlsConnectionPropertiesV1 EapType"`
+		CredentialsSource struct {
+			CertificateStore struct {
+				SimpleCertSelection bool `xml:"SimpleCertSelection"`
+			} `xml:"CertificateStore"`
+		} `xml:"CredentialsSource"`
+		ServerValidation        eapServerValidation `xml:"ServerValidation"`
+		DifferentUsername       bool                `xml:"DifferentUsername"`
+		PerformServerValidation bool                `xml:"http://www.microsoft.com/provisioning/EapTlsConnectionPropertiesV2 PerformServerValidation"`
+		AcceptServerName        bool                `xml:"http://www.microsoft.com/provisioning/EapTlsConnectionPropertiesV2 AcceptServerName"`
+	}
+}
+
+// windowsThumbprints 返回受信任CA中的证书指纹，转换为WLAN配置文件使用的小写空格分隔格式
+func windowsThumbprints(config *EnterpriseConfig) []string {
+	var thumbprints []string
+	for _, ca := range config.TrustedRootCAs {
+		compact := strings.Join(strings.Fields(ca), "")
+		if len(compact) == thumbprintHexLength && isHexString(compact) {
+			thumbprints = append(thumbprints, strings.ToLower(normalizeThumbprint(compact)))
+		}
+	}
+	return thumbprints
+}
+
+// newEAPConfig 根据802.1X配置生成WLAN配置文件中的EAP配置，支持PEAP（EAP-MSCHAPv2）和EAP-TLS
+func newEAPConfig(config *EnterpriseConfig) (*EAPConfig, error) {
+	validation := eapServerValidation{
+		DisableUserPromptForServerValidation: config.PromptDisabled,
+		ServerNames:                          config.ServerNames,
+		TrustedRootCA:                        windowsThumbprints(config),
+	}
+	performValidation := !config.ValidationDisabled()
+
+	var method int
+	var methodConfig interface{}
+	switch config.EAPMethod {
+	case "PEAP":
+		if config.InnerMethod != "" && !strings.EqualFold(strings.TrimPrefix(config.InnerMethod, "EAP-"), "MSCHAPv2") {
+			return nil, fmt.Errorf("不支持的PEAP内层认证方法: %s", config.InnerMethod)
+		}
+		peap := &peapMethodConfig{Type: eapTypePEAP}
+		peap.EapType.ServerValidation = validation
+		peap.EapType.FastReconnect = true
+		peap.EapType.Eap.Type = eapTypeMSCHAPv2
+		peap.EapType.PeapExtensions.PerformServerValidation = performValidation
+		peap.EapType.PeapExtensions.AcceptServerName = config.ServerNames != ""
+		method, methodConfig = eapTypePEAP, peap
+	case "EAP-TLS":
+		tls := &tlsMethodConfig{Type: eapTypeTLS}
+		tls.EapType.CredentialsSource.CertificateStore.SimpleCertSelection = true
+		tls.EapType.ServerValidation = validation
+		tls.EapType.PerformServerValidation = performValidation
+		tls.EapType.AcceptServerName = config.ServerNames != ""
+		method, methodConfig = eapTypeTLS, tls
+	case "":
+		return nil, fmt.Errorf("未知的EAP方法")
+	default:
+		return nil, fmt.Errorf("不支持的EAP方法: %s", config.EAPMethod)
+	}
+
+	data, err := xml.Marshal(methodConfig)
+	if err != nil {
+		return nil, fmt.Errorf("生成EAP配置失败: %v", err)
+	}
+	return &EAPConfig{EapHostConfig: EapHostConfig{
+		EapMethod: EapMethod{Type: method},
+		Config:    EapMethodConfig{InnerXML: string(data)},
+	}}, nil
+}
+
+// exportProfileXML 使用netsh导出指定配置文件的XML
+func exportProfileXML(name string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "wifisos_profile")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := exec.Command("netsh", "wlan", "export", "profile", "name="+name, "folder="+dir, "key=clear")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("导出配置文件失败: %v, 输出: %s", err, string(output))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+	if err != nil || len(files) == 0 {
+		return nil, fmt.Errorf("未找到导出的配置文件")
+	}
+	return os.ReadFile(files[0])
+}
+
+// loadEnterpriseConfig 导出配置文件XML并补充802.1X详细配置
+func loadEnterpriseConfig(network *SavedWiFi) error {
+	data, err := exportProfileXML(network.ProfileName)
+	if err != nil {
+		return err
+	}
+	config, err := parseEAPConfigXML(data)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return nil
+	}
+
+	// XML中未解析到EAP方法或凭据来源时，保留netsh文本输出中的结果
+	if network.Enterprise != nil {
+		if network.Enterprise.EAPMethod != "" && config.EAPMethod == "" {
+			config.EAPMethod = network.Enterprise.EAPMethod
+		}
+		if network.Enterprise.CredentialSource != "" {
+			config.CredentialSource = network.Enterprise.CredentialSource
+		}
+	}
+	network.Enterprise = config
+	return nil
+}
+
+// formatEnterpriseConfig 格式化802.1X配置
+func formatEnterpriseConfig(output *strings.Builder, config *EnterpriseConfig) {
+	output.WriteString("  802.1X: 是\n")
+	writeOptionalField(output, "EAP方法", config.EAPMethod)
+	writeOptionalField(output, "内层认证", config.InnerMethod)
+	writeOptionalField(output, "凭据来源", config.CredentialSource)
+	if config.ValidationKnown {
+		output.WriteString(fmt.Sprintf("  验证服务器证书: %s\n", formatBool(config.ServerValidation)))
+		output.WriteString(fmt.Sprintf("  禁止提示信任新服务器: %s\n", formatBool(config.PromptDisabled)))
+		writeOptionalField(output, "服务器名称", config.ServerNames)
+		for _, thumbprint := range config.TrustedRootCAs {
+			output.WriteString(fmt.Sprintf("  受信任根CA: %s\n", thumbprint))
+		}
+	} else {
+		output.WriteString("  验证服务器证书: 未知\n")
+	}
+	for _, warning := range config.Warnings() {
+		output.WriteString(fmt.Sprintf("  警告: %s\n", warning))
+	}
+}
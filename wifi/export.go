@@ -0,0 +1,625 @@
+package wifi
+
+import (
+	"WifiSOS/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 配置文件导出格式
+const (
+	ExportNetworkManager = "nm"           // NetworkManager keyfile，每个网络一个.nmconnection文件
+	ExportWpaSupplicant  = "wpa"          // wpa_supplicant.conf，所有网络写入同一个文件
+	ExportWindows        = "windows"      // Windows WLAN配置文件XML，每个网络一个文件
+	ExportMobileconfig   = "mobileconfig" // Apple配置描述文件，所有网络写入同一个文件
+)
+
+// ExportResult 表示一次配置文件导出的结果
+type ExportResult struct {
+	Format   string
+	Dir      string
+	Files    []string
+	Exported int
+	Skipped  []string // 无法导出的网络及原因
+	Warnings []string // 已导出但导入后需要手动处理的问题
+}
+
+// exportFile 表示一个待写入的导出文件，stem为不含扩展名的文件名
+type exportFile struct {
+	stem    string
+	ext     string
+	content []byte
+}
+
+// exportNameLength 导出文件名中网络名称部分的最大长度（字节）
+const exportNameLength = 64
+
+// ExportProfiles 将已保存的网络导出为指定平台的原生配置文件，写入dir目录
+// 导出的文件包含明文密钥，权限设置为仅当前用户可读写
+func ExportProfiles(networks []SavedWiFi, format string, dir string) (*ExportResult, error) {
+	result := &ExportResult{Format: format, Dir: dir}
+
+	var files []exportFile
+	switch format {
+	case ExportNetworkManager, ExportWindows:
+		formatter, ext := formatWindowsProfile, "xml"
+		if format == ExportNetworkManager {
+			formatter, ext = formatNetworkManagerFile, "nmconnection"
+		}
+		for _, network := range networks {
+			content, warnings, err := formatter(network)
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", network.SSID, err))
+				continue
+			}
+			result.Exported++
+			result.Warnings = append(result.Warnings, prefixWarnings(network.SSID, warnings)...)
+			files = append(files, exportFile{stem: exportFileName(network), ext: ext, content: content})
+		}
+	case ExportWpaSupplicant:
+		var blocks []string
+		for _, network := range networks {
+			block, warnings, err := FormatWpaSupplicantNetwork(network)
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", network.SSID, err))
+				continue
+			}
+			result.Exported++
+			result.Warnings = append(result.Warnings, prefixWarnings(network.SSID, warnings)...)
+			blocks = append(blocks, block)
+		}
+		if len(blocks) > 0 {
+			content := "ctrl_interface=DIR=/var/run/wpa_supplicant GROUP=netdev\nupdate_config=1\n\n" + strings.Join(blocks, "\n")
+			files = append(files, exportFile{stem: "wpa_supplicant", ext: "conf", content: []byte(content)})
+		}
+	case ExportMobileconfig:
+		var payloads []string
+		for _, network := range networks {
+			payload, warnings, err := mobileconfigPayload(network)
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", network.SSID, err))
+				continue
+			}
+			result.Exported++
+			result.Warnings = append(result.Warnings, prefixWarnings(network.SSID, warnings)...)
+			payloads = append(payloads, payload)
+		}
+		if len(payloads) > 0 {
+			files = append(files, exportFile{stem: "wifi", ext: "mobileconfig", content: []byte(formatMobileconfig(payloads))})
+		}
+	case ExportKeePass, ExportBitwarden, ExportCSV:
+		var entries []vaultEntry
+		for _, network := range networks {
+			entry, err := newVaultEntry(network)
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", network.SSID, err))
+				continue
+			}
+			result.Exported++
+			entries = append(entries, entry)
+		}
+		if len(entries) > 0 {
+			content, err := formatVaultFile(format, entries)
+			if err != nil {
+				return nil, err
+			}
+			stem, ext := vaultFileName(format)
+			files = append(files, exportFile{stem: stem, ext: ext, content: content})
+		}
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("创建导出目录失败: %v", err)
+	}
+	// 以独占方式创建文件，不覆盖目录中已有的文件，同名网络或已有文件时追加序号
+	for _, file := range files {
+		path, err := utils.WriteNewFile(dir, file.stem, file.ext, file.content)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, path)
+	}
+	return result, nil
+}
+
+// prefixWarnings 为警告信息加上SSID前缀
+func prefixWarnings(ssid string, warnings []string) []string {
+	var result []string
+	for _, warning := range warnings {
+		result = append(result, fmt.Sprintf("%s: %s", ssid, warning))
+	}
+	return result
+}
+
+// exportFileName 返回网络导出文件的基本名称，替换不允许的字符并避开Windows设备名
+func exportFileName(network SavedWiFi) string {
+	name := network.SSID
+	if name == "" {
+		name = network.ProfileName
+	}
+	if strings.Trim(name, " .") == "" {
+		name = "wifi"
+	}
+	return utils.SanitizeFilename(name, exportNameLength)
+}
+
+// newUUID 生成随机的UUID（版本4）
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// macRandomizationMode 将各来源的MAC随机化设置归一化为 random、stable 或 permanent，无法识别时返回空
+func macRandomizationMode(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "random", "always", "每天更改", "每次随机":
+		return "random"
+	case "stable", "启用", "持久随机":
+		return "stable"
+	case "permanent", "preserve", "never", "禁用":
+		return "permanent"
+	}
+	return ""
+}
+
+// eapMethodKeyword 将EAP方法显示名称转换为NetworkManager和wpa_supplicant使用的小写名称
+func eapMethodKeyword(method string) string {
+	for keyword, display := range eapMethodAliases {
+		if strings.EqualFold(display, method) {
+			return keyword
+		}
+	}
+	return strings.TrimPrefix(strings.ToLower(method), "eap-")
+}
+
+// eapMethodCode 返回EAP方法显示名称对应的编号
+func eapMethodCode(method string) (int, bool) {
+	for code, name := range eapMethodNames {
+		if strings.EqualFold(name, method) {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// caCertPaths 返回受信任CA中的证书文件路径，Windows的证书指纹无法在其他平台使用
+func caCertPaths(config *EnterpriseConfig) []string {
+	var paths []string
+	for _, ca := range config.TrustedRootCAs {
+		if strings.HasPrefix(ca, "/") || strings.Contains(ca, "://") {
+			paths = append(paths, ca)
+		}
+	}
+	return paths
+}
+
+// enterpriseWarnings 返回导出802.1X配置时需要手动补充的内容
+func enterpriseWarnings(config *EnterpriseConfig) []string {
+	warnings := []string{"802.1X身份和凭据未导出，导入后需要手动填写"}
+	if len(config.TrustedRootCAs) > 0 && len(caCertPaths(config)) == 0 {
+		warnings = append(warnings, "受信任根CA为证书指纹，需要在目标设备上手动安装CA证书")
+	}
+	return warnings
+}
+
+// keyWarnings 检查个人认证网络的密钥是否可以导出
+func keyWarnings(network SavedWiFi) []string {
+	if network.AuthType().IsPersonal() && !network.HasPassword() {
+		return []string{fmt.Sprintf("密钥%s，导入后需要手动输入", network.KeyStatus)}
+	}
+	return nil
+}
+
+// escapeKeyfileValue 按keyfile格式转义值，是unescapeKeyfileValue的逆过程
+func escapeKeyfileValue(value string) string {
+	var result strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\':
+			result.WriteString(`\\`)
+		case '\n':
+			result.WriteString(`\n`)
+		case '\t':
+			result.WriteString(`\t`)
+		case '\r':
+			result.WriteString(`\r`)
+		case ' ':
+			// 首尾空格会被去除，需要转义
+			if i == 0 || i == len(value)-1 {
+				result.WriteString(`\s`)
+			} else {
+				result.WriteByte(c)
+			}
+		default:
+			result.WriteByte(c)
+		}
+	}
+	return result.String()
+}
+
+// keyfileSSID 返回keyfile中的SSID值，包含分号或不可打印字符时使用字节列表形式
+func keyfileSSID(ssid string) string {
+	printable := utf8.ValidString(ssid) && !strings.ContainsRune(ssid, ';')
+	for _, r := range ssid {
+		if !unicode.IsPrint(r) {
+			printable = false
+		}
+	}
+	if printable {
+		return escapeKeyfileValue(ssid)
+	}
+	var result strings.Builder
+	for _, b := range []byte(ssid) {
+		result.WriteString(fmt.Sprintf("%d;", b))
+	}
+	return result.String()
+}
+
+// FormatNetworkManagerKeyfile 生成NetworkManager的.nmconnection文件内容
+func FormatNetworkManagerKeyfile(network SavedWiFi) (string, []string, error) {
+	if network.SSID == "" {
+		return "", nil, fmt.Errorf("SSID为空")
+	}
+	auth := network.AuthType()
+	if auth == AuthUnknown {
+		return "", nil, fmt.Errorf("无法识别的身份验证方式: %s", network.Authentication)
+	}
+	warnings := keyWarnings(network)
+
+	var output strings.Builder
+	output.WriteString("[connection]\n")
+	output.WriteString(fmt.Sprintf("id=%s\n", escapeKeyfileValue(network.SSID)))
+	output.WriteString(fmt.Sprintf("uuid=%s\n", newUUID()))
+	output.WriteString("type=wifi\n")
+	if network.ConnectionMode == ConnectionManual {
+		output.WriteString("autoconnect=false\n")
+	}
+
+	output.WriteString("\n[wifi]\n")
+	mode := "infrastructure"
+	if network.NetworkType == "adhoc" || strings.EqualFold(network.NetworkType, "IBSS") {
+		mode = "adhoc"
+	}
+	output.WriteString(fmt.Sprintf("mode=%s\n", mode))
+	output.WriteString(fmt.Sprintf("ssid=%s\n", keyfileSSID(network.SSID)))
+	if network.NonBroadcast {
+		output.WriteString("hidden=true\n")
+	}
+	if mac := macRandomizationMode(network.MACRandomization); mac != "" {
+		output.WriteString(fmt.Sprintf("cloned-mac-address=%s\n", mac))
+	}
+
+	if auth != AuthOpen {
+		output.WriteString("\n[wifi-security]\n")
+		switch auth {
+		case AuthOWE:
+			output.WriteString("key-mgmt=owe\n")
+		case AuthWEP:
+			output.WriteString("key-mgmt=none\n")
+			output.WriteString("auth-alg=open\n")
+			if network.HasPassword() {
+				// 1为十六进制或ASCII密钥，2为需要哈希的口令
+				output.WriteString("wep-key-type=1\n")
+				output.WriteString(fmt.Sprintf("wep-key0=%s\n", escapeKeyfileValue(network.Password)))
+			}
+		case AuthWPAPSK, AuthWPA2PSK, AuthWPA3SAE:
+			keyMgmt := "wpa-psk"
+			if auth == AuthWPA3SAE {
+				keyMgmt = "sae"
+			}
+			output.WriteString(fmt.Sprintf("key-mgmt=%s\n", keyMgmt))
+			if network.HasPassword() {
+				output.WriteString("psk-flags=0\n")
+				output.WriteString(fmt.Sprintf("psk=%s\n", escapeKeyfileValue(network.Password)))
+			}
+		default:
+			output.WriteString("key-mgmt=wpa-eap\n")
+		}
+	}
+
+	if auth.IsEnterprise() {
+		config := network.Enterprise
+		if config == nil {
+			config = &EnterpriseConfig{}
+		}
+		if config.EAPMethod == "" {
+			return "", nil, fmt.Errorf("未知的EAP方法")
+		}
+		warnings = append(warnings, enterpriseWarnings(config)...)
+		output.WriteString("\n[802-1x]\n")
+		output.WriteString(fmt.Sprintf("eap=%s;\n", eapMethodKeyword(config.EAPMethod)))
+		if config.InnerMethod != "" {
+			output.WriteString(fmt.Sprintf("phase2-auth=%s\n", eapMethodKeyword(config.InnerMethod)))
+		}
+		if paths := caCertPaths(config); len(paths) > 0 {
+			output.WriteString(fmt.Sprintf("ca-cert=%s\n", escapeKeyfileValue(paths[0])))
+		} else if config.ServerValidation {
+			output.WriteString("system-ca-certs=true\n")
+		}
+		if config.ServerNames != "" {
+			output.WriteString(fmt.Sprintf("domain-suffix-match=%s\n", escapeKeyfileValue(config.ServerNames)))
+		}
+	}
+
+	output.WriteString("\n[ipv4]\nmethod=auto\n")
+	output.WriteString("\n[ipv6]\naddr-gen-mode=stable-privacy\nmethod=auto\n")
+	return output.String(), warnings, nil
+}
+
+// formatNetworkManagerFile 生成.nmconnection文件内容
+func formatNetworkManagerFile(network SavedWiFi) ([]byte, []string, error) {
+	content, warnings, err := FormatNetworkManagerKeyfile(network)
+	return []byte(content), warnings, err
+}
+
+// wpaQuotable 判断字符串能否直接写入wpa_supplicant的双引号字符串
+func wpaQuotable(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < 0x20 || value[i] == 0x7f || value[i] == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// wpaString 返回wpa_supplicant中的字符串值，无法加引号时使用十六进制形式
+func wpaString(value string) string {
+	if wpaQuotable(value) {
+		return `"` + value + `"`
+	}
+	return hex.EncodeToString([]byte(value))
+}
+
+// FormatWpaSupplicantNetwork 生成wpa_supplicant.conf中的network块
+func FormatWpaSupplicantNetwork(network SavedWiFi) (string, []string, error) {
+	if network.SSID == "" {
+		return "", nil, fmt.Errorf("SSID为空")
+	}
+	auth := network.AuthType()
+	if auth == AuthUnknown {
+		return "", nil, fmt.Errorf("无法识别的身份验证方式: %s", network.Authentication)
+	}
+	warnings := keyWarnings(network)
+
+	var output strings.Builder
+	output.WriteString("network={\n")
+	output.WriteString(fmt.Sprintf("\tssid=%s\n", wpaString(network.SSID)))
+	if network.NonBroadcast {
+		output.WriteString("\tscan_ssid=1\n")
+	}
+	if network.NetworkType == "adhoc" || strings.EqualFold(network.NetworkType, "IBSS") {
+		output.WriteString("\tmode=1\n")
+	}
+	if network.ConnectionMode == ConnectionManual {
+		output.WriteString("\tdisabled=1\n")
+	}
+	switch macRandomizationMode(network.MACRandomization) {
+	case "random", "stable":
+		output.WriteString("\tmac_addr=1\n")
+	}
+
+	switch auth {
+	case AuthOpen:
+		output.WriteString("\tkey_mgmt=NONE\n")
+	case AuthOWE:
+		output.WriteString("\tkey_mgmt=OWE\n")
+	case AuthWEP:
+		output.WriteString("\tkey_mgmt=NONE\n")
+		if network.HasPassword() {
+			key := network.Password
+			if !(isHexString(key) && (len(key) == 10 || len(key) == 26)) {
+				if !wpaQuotable(key) {
+					return "", nil, fmt.Errorf("WEP密钥包含无法写入的字符")
+				}
+				key = `"` + key + `"`
+			}
+			output.WriteString(fmt.Sprintf("\twep_key0=%s\n", key))
+			output.WriteString("\twep_tx_keyidx=0\n")
+		}
+	case AuthWPAPSK, AuthWPA2PSK, AuthWPA3SAE:
+		keyMgmt := "WPA-PSK"
+		if auth == AuthWPA3SAE {
+			keyMgmt = "SAE"
+			output.WriteString("\tieee80211w=2\n")
+		}
+		output.WriteString(fmt.Sprintf("\tkey_mgmt=%s\n", keyMgmt))
+		if network.HasPassword() {
+			key := network.Password
+			switch {
+			case len(key) == 64 && isHexString(key) && auth != AuthWPA3SAE:
+				// 64位十六进制为原始PSK，不加引号
+			case strings.ContainsAny(key, "\r\n"):
+				return "", nil, fmt.Errorf("密钥包含换行符，无法写入wpa_supplicant.conf")
+			default:
+				key = `"` + key + `"`
+			}
+			if auth == AuthWPA3SAE {
+				output.WriteString(fmt.Sprintf("\tsae_password=%s\n", key))
+			} else {
+				output.WriteString(fmt.Sprintf("\tpsk=%s\n", key))
+			}
+		}
+	default:
+		output.WriteString("\tkey_mgmt=WPA-EAP\n")
+		config := network.Enterprise
+		if config == nil || config.EAPMethod == "" {
+			return "", nil, fmt.Errorf("未知的EAP方法")
+		}
+		warnings = append(warnings, enterpriseWarnings(config)...)
+		output.WriteString(fmt.Sprintf("\teap=%s\n", strings.ToUpper(eapMethodKeyword(config.EAPMethod))))
+		if config.InnerMethod != "" {
+			output.WriteString(fmt.Sprintf("\tphase2=\"auth=%s\"\n", strings.ToUpper(eapMethodKeyword(config.InnerMethod))))
+		}
+		if paths := caCertPaths(config); len(paths) > 0 {
+			output.WriteString(fmt.Sprintf("\tca_cert=%s\n", wpaString(paths[0])))
+		}
+		if config.ServerNames != "" {
+			output.WriteString(fmt.Sprintf("\tdomain_suffix_match=%s\n", wpaString(config.ServerNames)))
+		}
+		output.WriteString("\t#identity=\"\"\n")
+	}
+
+	output.WriteString("}\n")
+	return output.String(), warnings, nil
+}
+
+// formatWindowsProfile 生成Windows WLAN配置文件XML
+func formatWindowsProfile(network SavedWiFi) ([]byte, []string, error) {
+	profile, err := NewWLANProfile(network)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, nil, err
+	}
+	data, err := profile.Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings := keyWarnings(network)
+	if network.IsEnterprise() {
+		warnings = append(warnings, "802.1X凭据未导出，首次连接时需要输入")
+		if len(network.Enterprise.TrustedRootCAs) > 0 && len(windowsThumbprints(network.Enterprise)) == 0 {
+			warnings = append(warnings, "受信任根CA为证书文件路径，需要在Windows上导入CA证书")
+		}
+	}
+	return data, warnings, nil
+}
+
+// plistEscape 转义plist中的字符串
+func plistEscape(value string) string {
+	var output strings.Builder
+	_ = xml.EscapeText(&output, []byte(value))
+	return output.String()
+}
+
+// mobileconfigPayload 生成Apple配置描述文件中的一个com.apple.wifi.managed负载
+func mobileconfigPayload(network SavedWiFi) (string, []string, error) {
+	if network.SSID == "" {
+		return "", nil, fmt.Errorf("SSID为空")
+	}
+	auth := network.AuthType()
+	var encryption string
+	switch auth {
+	case AuthOpen, AuthOWE:
+		encryption = "None"
+	case AuthWEP:
+		encryption = "WEP"
+	case AuthWPAPSK, AuthWPA2PSK, AuthWPA2EAP, AuthWPAEAP:
+		encryption = "WPA2"
+	case AuthWPA3SAE, AuthWPA3EAP:
+		encryption = "WPA3"
+	default:
+		return "", nil, fmt.Errorf("无法识别的身份验证方式: %s", network.Authentication)
+	}
+	warnings := keyWarnings(network)
+
+	uuid := strings.ToUpper(newUUID())
+	var output strings.Builder
+	output.WriteString("\t\t<dict>\n")
+	writeBool := func(key string, value bool) {
+		output.WriteString(fmt.Sprintf("\t\t\t<key>%s</key>\n\t\t\t<%t/>\n", key, value))
+	}
+	writeString := func(key string, value string) {
+		output.WriteString(fmt.Sprintf("\t\t\t<key>%s</key>\n\t\t\t<string>%s</string>\n", key, plistEscape(value)))
+	}
+	writeBool("AutoJoin", network.ConnectionMode != ConnectionManual)
+	if macRandomizationMode(network.MACRandomization) == "permanent" {
+		writeBool("DisableAssociationMACRandomization", true)
+	}
+	writeString("EncryptionType", encryption)
+	writeBool("HIDDEN_NETWORK", network.NonBroadcast)
+	if auth.IsPersonal() && network.HasPassword() {
+		writeString("Password", network.Password)
+	}
+
+	if auth.IsEnterprise() {
+		config := network.Enterprise
+		if config == nil || config.EAPMethod == "" {
+			return "", nil, fmt.Errorf("未知的EAP方法")
+		}
+		code, ok := eapMethodCode(config.EAPMethod)
+		if !ok {
+			return "", nil, fmt.Errorf("不支持的EAP方法: %s", config.EAPMethod)
+		}
+		warnings = append(warnings, enterpriseWarnings(config)...)
+		output.WriteString("\t\t\t<key>EAPClientConfiguration</key>\n\t\t\t<dict>\n")
+		output.WriteString(fmt.Sprintf("\t\t\t\t<key>AcceptEAPTypes</key>\n\t\t\t\t<array>\n\t\t\t\t\t<integer>%d</integer>\n\t\t\t\t</array>\n", code))
+		if config.EAPMethod == "EAP-TTLS" && config.InnerMethod != "" {
+			inner := strings.TrimPrefix(config.InnerMethod, "EAP-")
+			output.WriteString(fmt.Sprintf("\t\t\t\t<key>TTLSInnerAuthentication</key>\n\t\t\t\t<string>%s</string>\n", plistEscape(inner)))
+		}
+		if config.ServerNames != "" {
+			output.WriteString("\t\t\t\t<key>TLSTrustedServerNames</key>\n\t\t\t\t<array>\n")
+			for _, name := range strings.FieldsFunc(config.ServerNames, func(r rune) bool { return r == ';' || r == ',' }) {
+				output.WriteString(fmt.Sprintf("\t\t\t\t\t<string>%s</string>\n", plistEscape(strings.TrimSpace(name))))
+			}
+			output.WriteString("\t\t\t\t</array>\n")
+		}
+		output.WriteString("\t\t\t</dict>\n")
+	}
+
+	writeString("PayloadDisplayName", "Wi-Fi ("+network.SSID+")")
+	writeString("PayloadIdentifier", "com.wifisos.wifi."+uuid)
+	writeString("PayloadType", "com.apple.wifi.managed")
+	writeString("PayloadUUID", uuid)
+	output.WriteString("\t\t\t<key>PayloadVersion</key>\n\t\t\t<integer>1</integer>\n")
+	writeString("SSID_STR", network.SSID)
+	output.WriteString("\t\t</dict>\n")
+	return output.String(), warnings, nil
+}
+
+// formatMobileconfig 将多个WiFi负载组合为完整的配置描述文件
+func formatMobileconfig(payloads []string) string {
+	uuid := strings.ToUpper(newUUID())
+	var output strings.Builder
+	output.WriteString(xml.Header)
+	output.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	output.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	output.WriteString("\t<key>PayloadContent</key>\n\t<array>\n")
+	for _, payload := range payloads {
+		output.WriteString(payload)
+	}
+	output.WriteString("\t</array>\n")
+	output.WriteString(fmt.Sprintf("\t<key>PayloadDisplayName</key>\n\t<string>%s</string>\n", plistEscape("WiFi配置")))
+	output.WriteString(fmt.Sprintf("\t<key>PayloadIdentifier</key>\n\t<string>com.wifisos.profile.%s</string>\n", uuid))
+	output.WriteString("\t<key>PayloadRemovalDisallowed</key>\n\t<false/>\n")
+	output.WriteString("\t<key>PayloadType</key>\n\t<string>Configuration</string>\n")
+	output.WriteString(fmt.Sprintf("\t<key>PayloadUUID</key>\n\t<string>%s</string>\n", uuid))
+	output.WriteString("\t<key>PayloadVersion</key>\n\t<integer>1</integer>\n")
+	output.WriteString("</dict>\n</plist>\n")
+	return output.String()
+}
+
+// FormatExportResult 格式化导出结果
+func FormatExportResult(result *ExportResult) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("已导出 %d 个网络为 %s 格式，跳过 %d 个\n", result.Exported, result.Format, len(result.Skipped)))
+	for _, file := range result.Files {
+		output.WriteString(fmt.Sprintf("  %s\n", file))
+	}
+	if len(result.Skipped) > 0 {
+		output.WriteString("\n已跳过:\n")
+		for _, skipped := range result.Skipped {
+			output.WriteString(fmt.Sprintf("  %s\n", skipped))
+		}
+	}
+	if len(result.Warnings) > 0 {
+		output.WriteString("\n需要注意:\n")
+		for _, warning := range result.Warnings {
+			output.WriteString(fmt.Sprintf("  %s\n", warning))
+		}
+	}
+	return output.String()
+}
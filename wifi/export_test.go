@@ -0,0 +1,47 @@
+package wifi
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestExportProfilesDoesNotOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "HomeNet.xml")
+	if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	networks := []SavedWiFi{
+		{SSID: "HomeNet", Password: "password1", KeyStatus: KeyPresent, Authentication: "WPA2-Personal", Cipher: "CCMP"},
+		{SSID: "HomeNet", Password: "password2", KeyStatus: KeyPresent, Authentication: "WPA2-Personal", Cipher: "CCMP"},
+		{SSID: "CON", Password: "password3", KeyStatus: KeyPresent, Authentication: "WPA2-Personal", Cipher: "CCMP"},
+		{SSID: "../a:b", Password: "password4", KeyStatus: KeyPresent, Authentication: "WPA2-Personal", Cipher: "CCMP"},
+	}
+	result, err := ExportProfiles(networks, ExportWindows, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"HomeNet_2.xml", "HomeNet_3.xml", "_CON.xml", "_a_b.xml"}
+	if len(result.Files) != len(want) {
+		t.Fatalf("导出了 %v，期望 %v", result.Files, want)
+	}
+	for i, path := range result.Files {
+		if path != filepath.Join(dir, want[i]) {
+			t.Errorf("文件 #%d = %s，期望 %s", i+1, path, want[i])
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("%s 的权限为 %v，期望 0600", path, info.Mode().Perm())
+		}
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "old" {
+		t.Error("已有的文件被覆盖")
+	}
+}
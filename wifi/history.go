@@ -0,0 +1,306 @@
+package wifi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 默认每个BSSID保留的采样数量
+const DefaultHistorySize = 60
+
+// sparkLevels 迷你图使用的字符，从低到高
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// SignalSample 表示一次信号采样，Signal为-1表示本轮未扫描到
+type SignalSample struct {
+	Time   time.Time
+	Signal int
+}
+
+// Visible 判断本次采样是否扫描到该网络
+func (s SignalSample) Visible() bool {
+	return s.Signal >= 0
+}
+
+// SignalSeries 表示单个BSSID的信号时间序列，使用环形缓冲区保存
+type SignalSeries struct {
+	SSID    string
+	BSSID   string
+	Channel ChannelInfo
+	samples []SignalSample
+	next    int
+	full    bool
+}
+
+// SignalStats 表示信号序列的统计信息
+type SignalStats struct {
+	Min     int
+	Max     int
+	Avg     float64
+	StdDev  float64
+	Visible int
+	Total   int
+}
+
+// newSignalSeries 创建指定容量的信号序列
+func newSignalSeries(network WiFiNetwork, capacity int) *SignalSeries {
+	return &SignalSeries{
+		SSID:    network.SSID,
+		BSSID:   network.BSSID,
+		Channel: network.ChannelInfo(),
+		samples: make([]SignalSample, capacity),
+	}
+}
+
+// Add 向环形缓冲区添加一个采样，缓冲区满时覆盖最旧的采样
+func (s *SignalSeries) Add(sample SignalSample) {
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % len(s.samples)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Samples 按时间顺序返回缓冲区中的采样
+func (s *SignalSeries) Samples() []SignalSample {
+	if !s.full {
+		return append([]SignalSample(nil), s.samples[:s.next]...)
+	}
+	result := make([]SignalSample, 0, len(s.samples))
+	result = append(result, s.samples[s.next:]...)
+	result = append(result, s.samples[:s.next]...)
+	return result
+}
+
+// Stats 计算可见采样的最小值、平均值、最大值和标准差
+func (s *SignalSeries) Stats() SignalStats {
+	var stats SignalStats
+	var sum float64
+	samples := s.Samples()
+	stats.Total = len(samples)
+
+	for _, sample := range samples {
+		if !sample.Visible() {
+			continue
+		}
+		if stats.Visible == 0 || sample.Signal < stats.Min {
+			stats.Min = sample.Signal
+		}
+		if sample.Signal > stats.Max {
+			stats.Max = sample.Signal
+		}
+		sum += float64(sample.Signal)
+		stats.Visible++
+	}
+
+	if stats.Visible == 0 {
+		return stats
+	}
+
+	stats.Avg = sum / float64(stats.Visible)
+	var variance float64
+	for _, sample := range samples {
+		if sample.Visible() {
+			diff := float64(sample.Signal) - stats.Avg
+			variance += diff * diff
+		}
+	}
+	stats.StdDev = math.Sqrt(variance / float64(stats.Visible))
+	return stats
+}
+
+// Sparkline 将信号序列渲染为迷你图，未扫描到的采样显示为空格
+func (s *SignalSeries) Sparkline() string {
+	return sparkline(s.Samples())
+}
+
+// sparkline 按0-100%的固定刻度渲染迷你图，便于不同BSSID之间比较
+func sparkline(samples []SignalSample) string {
+	var line strings.Builder
+	for _, sample := range samples {
+		if !sample.Visible() {
+			line.WriteRune(' ')
+			continue
+		}
+		line.WriteRune(signalLevel(sample.Signal))
+	}
+	return line.String()
+}
+
+// signalLevel 返回信号强度对应的迷你图字符，超出0-100%的值按边界处理
+func signalLevel(signal int) rune {
+	signal = min(max(signal, 0), 100)
+	level := signal * (len(sparkLevels) - 1) / 100
+	return sparkLevels[level]
+}
+
+// SignalHistory 记录每个BSSID的信号变化
+type SignalHistory struct {
+	Capacity int
+	series   map[string]*SignalSeries
+	order    []string
+}
+
+// NewSignalHistory 创建信号历史记录，capacity为每个BSSID保留的采样数量
+func NewSignalHistory(capacity int) *SignalHistory {
+	if capacity <= 0 {
+		capacity = DefaultHistorySize
+	}
+	return &SignalHistory{
+		Capacity: capacity,
+		series:   make(map[string]*SignalSeries),
+	}
+}
+
+// Record 记录一轮扫描结果，本轮未出现的BSSID记为不可见
+func (h *SignalHistory) Record(networks []WiFiNetwork) {
+	now := time.Now()
+	seen := make(map[string]bool)
+
+	for _, network := range networks {
+		key := strings.ToLower(network.BSSID)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		series, ok := h.series[key]
+		if !ok {
+			series = newSignalSeries(network, h.Capacity)
+			h.series[key] = series
+			h.order = append(h.order, key)
+		}
+		// 信道可能因AP自动选择而变化，保留最新值
+		series.Channel = network.ChannelInfo()
+
+		signal, ok := network.SignalValue()
+		if !ok {
+			signal = -1
+		}
+		series.Add(SignalSample{Time: now, Signal: signal})
+	}
+
+	for _, key := range h.order {
+		if !seen[key] {
+			h.series[key].Add(SignalSample{Time: now, Signal: -1})
+		}
+	}
+}
+
+// Series 返回所有BSSID的信号序列，按平均信号从强到弱排序
+func (h *SignalHistory) Series() []*SignalSeries {
+	result := make([]*SignalSeries, 0, len(h.order))
+	for _, key := range h.order {
+		result = append(result, h.series[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Stats().Avg > result[j].Stats().Avg
+	})
+	return result
+}
+
+// FormatSignalHistory 格式化信号历史，用于终端显示
+func FormatSignalHistory(history *SignalHistory) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("=== WiFi信号监测 - %s ===\n\n", time.Now().Format("2006-01-02 15:04:05")))
+
+	series := history.Series()
+	if len(series) == 0 {
+		output.WriteString("未发现WiFi网络\n")
+		return output.String()
+	}
+
+	// 计算SSID的最大长度，用于对齐显示
+	maxSSIDLen := 20
+	for _, s := range series {
+		if len(s.SSID) > maxSSIDLen {
+			maxSSIDLen = len(s.SSID)
+		}
+	}
+
+	output.WriteString(fmt.Sprintf("%-*s | %-17s | %-22s | %s | %4s | %6s | %4s | %6s | %s\n",
+		maxSSIDLen, "SSID",
+		"BSSID",
+		"信道",
+		padRunes("信号变化", history.Capacity),
+		"最小",
+		"平均",
+		"最大",
+		"标准差",
+		"可见率"))
+	separatorLen := maxSSIDLen + 3 + 17 + 3 + 22 + 3 + history.Capacity + 3 + 4 + 3 + 6 + 3 + 4 + 3 + 6 + 3 + 6
+	output.WriteString(strings.Repeat("-", separatorLen) + "\n")
+
+	for _, s := range series {
+		stats := s.Stats()
+		if stats.Visible == 0 {
+			output.WriteString(fmt.Sprintf("%-*s | %-17s | %-22s | %s | %4s | %6s | %4s | %6s | %d/%d\n",
+				maxSSIDLen, s.SSID, s.BSSID, s.Channel,
+				padRunes(s.Sparkline(), history.Capacity),
+				"N/A", "N/A", "N/A", "N/A",
+				stats.Visible, stats.Total))
+			continue
+		}
+		output.WriteString(fmt.Sprintf("%-*s | %-17s | %-22s | %s | %3d%% | %5.1f%% | %3d%% | %6.1f | %d/%d\n",
+			maxSSIDLen, s.SSID, s.BSSID, s.Channel,
+			padRunes(s.Sparkline(), history.Capacity),
+			stats.Min, stats.Avg, stats.Max, stats.StdDev,
+			stats.Visible, stats.Total))
+	}
+
+	output.WriteString("\n注意:\n")
+	output.WriteString(fmt.Sprintf("- 信号变化: %s 对应 0%% 到 100%%，空白表示该轮未扫描到\n", string(sparkLevels)))
+	output.WriteString("- 标准差越大表示信号越不稳定\n")
+
+	return output.String()
+}
+
+// FormatSignalHistoryCSV 将信号时间序列导出为CSV，本轮未扫描到时signal列为空
+func FormatSignalHistoryCSV(history *SignalHistory) (string, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write([]string{"time", "ssid", "bssid", "band", "channel", "width", "signal"}); err != nil {
+		return "", fmt.Errorf("生成CSV失败: %v", err)
+	}
+	for _, key := range history.order {
+		s := history.series[key]
+		for _, sample := range s.Samples() {
+			signal := ""
+			if sample.Visible() {
+				signal = strconv.Itoa(sample.Signal)
+			}
+			record := []string{
+				sample.Time.Format(time.RFC3339),
+				s.SSID,
+				s.BSSID,
+				s.Channel.Band,
+				strconv.Itoa(s.Channel.Primary),
+				strconv.Itoa(s.Channel.Width),
+				signal,
+			}
+			if err := writer.Write(record); err != nil {
+				return "", fmt.Errorf("生成CSV失败: %v", err)
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("生成CSV失败: %v", err)
+	}
+	return buffer.String(), nil
+}
+
+// padRunes 按字符数而不是字节数右侧补齐空格
+func padRunes(value string, width int) string {
+	if count := utf8.RuneCountInString(value); count < width {
+		return value + strings.Repeat(" ", width-count)
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+package wifi
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSignalLevel(t *testing.T) {
+	tests := []struct {
+		signal int
+		want   rune
+	}{
+		{-20, '▁'},
+		{0, '▁'},
+		{50, '▄'},
+		{100, '█'},
+		{150, '█'},
+	}
+	for _, test := range tests {
+		if got := signalLevel(test.signal); got != test.want {
+			t.Errorf("signalLevel(%d) = %q，期望 %q", test.signal, got, test.want)
+		}
+	}
+}
+
+func TestFormatSignalHistoryCSV(t *testing.T) {
+	// SSID包含逗号、引号和换行，导出后应能被标准CSV解析器还原
+	ssid := "Cafe, \"Free\"\nWiFi"
+	history := NewSignalHistory(0)
+	history.Record([]WiFiNetwork{{SSID: ssid, BSSID: "aa:bb:cc:dd:ee:01", Signal: "75%", Channel: "36", ChannelWidth: 80}})
+	history.Record(nil)
+
+	output, err := FormatSignalHistoryCSV(history)
+	if err != nil {
+		t.Fatalf("FormatSignalHistoryCSV 返回错误: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		t.Fatalf("解析CSV失败: %v\n%s", err, output)
+	}
+	if len(records) != 3 {
+		t.Fatalf("CSV行数 = %d，期望 3", len(records))
+	}
+	if want := []string{"time", "ssid", "bssid", "band", "channel", "width", "signal"}; !reflect.DeepEqual(records[0], want) {
+		t.Errorf("表头 = %v，期望 %v", records[0], want)
+	}
+	if want := []string{ssid, "aa:bb:cc:dd:ee:01", Band5G, "36", "80", "75"}; !reflect.DeepEqual(records[1][1:], want) {
+		t.Errorf("第一条记录 = %q，期望 %q", records[1][1:], want)
+	}
+	if records[2][6] != "" {
+		t.Errorf("未扫描到时signal = %q，期望为空", records[2][6])
+	}
+}
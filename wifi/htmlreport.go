@@ -0,0 +1,547 @@
+package wifi
+
+import (
+	"WifiSOS/utils"
+	"fmt"
+	"html/template"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// chartColors 图表中各类别依次使用的颜色
+var chartColors = []string{"#2e86de", "#27ae60", "#f39c12", "#c0392b", "#8e44ad", "#16a085", "#d35400", "#7f8c8d", "#2c3e50", "#e84393"}
+
+// strengthColors 各密钥强度等级的颜色，从极弱到很强
+var strengthColors = []string{"#c0392b", "#e67e22", "#f1c40f", "#27ae60", "#1e8449"}
+
+// bandColors 信道占用图中各频段的颜色
+var bandColors = map[string]string{Band2G: "#2e86de", Band5G: "#27ae60", Band6G: "#8e44ad"}
+
+// 饼图和柱状图的尺寸（像素）
+const (
+	pieRadius     = 90
+	pieCenter     = 100
+	barWidth      = 28
+	barGap        = 10
+	barMargin     = 10
+	barAreaHeight = 150
+	barTop        = 20
+)
+
+// pieSlice 饼图中的一个扇区，Path为SVG路径
+type pieSlice struct {
+	Label   string
+	Count   int
+	Percent string
+	Color   string
+	Path    string
+}
+
+// pieChart 饼图，只有一个类别时Full为true，画整圆
+type pieChart struct {
+	Title  string
+	Total  int
+	Full   bool
+	Slices []pieSlice
+}
+
+// newPieChart 按数量从多到少生成饼图
+func newPieChart(title string, counts map[string]int) *pieChart {
+	chart := &pieChart{Title: title}
+	var labels []string
+	for label, count := range counts {
+		labels = append(labels, label)
+		chart.Total += count
+	}
+	if chart.Total == 0 {
+		return nil
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if counts[labels[i]] != counts[labels[j]] {
+			return counts[labels[i]] > counts[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+	chart.Full = len(labels) == 1
+
+	// 从12点方向开始顺时针绘制
+	angle := -math.Pi / 2
+	point := func(a float64) string {
+		return fmt.Sprintf("%.2f,%.2f", pieCenter+pieRadius*math.Cos(a), pieCenter+pieRadius*math.Sin(a))
+	}
+	for i, label := range labels {
+		share := float64(counts[label]) / float64(chart.Total)
+		end := angle + share*2*math.Pi
+		large := 0
+		if share > 0.5 {
+			large = 1
+		}
+		chart.Slices = append(chart.Slices, pieSlice{
+			Label:   label,
+			Count:   counts[label],
+			Percent: fmt.Sprintf("%.1f%%", share*100),
+			Color:   chartColors[i%len(chartColors)],
+			Path:    fmt.Sprintf("M%d,%d L%s A%d,%d 0 %d 1 %s Z", pieCenter, pieCenter, point(angle), pieRadius, pieRadius, large, point(end)),
+		})
+		angle = end
+	}
+	return chart
+}
+
+// chartBar 柱状图中的一根柱子及其标签的位置
+type chartBar struct {
+	Label   string
+	Value   int
+	Color   string
+	X       int
+	Y       int
+	Height  int
+	CenterX int
+}
+
+// barChart 柱状图
+type barChart struct {
+	Title    string
+	Width    int
+	Height   int
+	Baseline int
+	BarWidth int
+	Bars     []chartBar
+}
+
+// barValue 柱状图的一项数据
+type barValue struct {
+	label string
+	value int
+	color string
+}
+
+// newBarChart 按给定顺序生成柱状图，柱高按最大值缩放
+func newBarChart(title string, values []barValue) *barChart {
+	if len(values) == 0 {
+		return nil
+	}
+	maxValue := 0
+	for _, v := range values {
+		maxValue = max(maxValue, v.value)
+	}
+	chart := &barChart{
+		Title:    title,
+		Width:    2*barMargin + len(values)*(barWidth+barGap) - barGap,
+		Height:   barTop + barAreaHeight + 20,
+		Baseline: barTop + barAreaHeight,
+		BarWidth: barWidth,
+	}
+	for i, v := range values {
+		height := 0
+		if maxValue > 0 {
+			height = v.value * barAreaHeight / maxValue
+		}
+		x := barMargin + i*(barWidth+barGap)
+		chart.Bars = append(chart.Bars, chartBar{
+			Label:   v.label,
+			Value:   v.value,
+			Color:   v.color,
+			X:       x,
+			Y:       chart.Baseline - height,
+			Height:  height,
+			CenterX: x + barWidth/2,
+		})
+	}
+	return chart
+}
+
+// htmlTable 报告中的表格，点击表头排序
+type htmlTable struct {
+	Header []string
+	Rows   [][]string
+}
+
+// newHTMLTable 由记录生成表格，所有记录都为空的列不显示
+func newHTMLTable(records []outputRecord) *htmlTable {
+	if len(records) == 0 {
+		return nil
+	}
+	table := &htmlTable{}
+	columns, rows := outputColumns(records)
+	for _, j := range columns {
+		table.Header = append(table.Header, rows[0][j].Label)
+	}
+	for _, fields := range rows {
+		var row []string
+		for _, j := range columns {
+			row = append(row, fields[j].Value)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// htmlSection 报告中一次运行的结果
+type htmlSection struct {
+	ID       string
+	Title    string
+	Started  string
+	Meta     []outputField
+	Warnings []string
+	Summary  []outputField
+	Pies     []*pieChart
+	Bars     []*barChart
+	Table    *htmlTable
+}
+
+// addPie 添加饼图，没有数据时忽略
+func (s *htmlSection) addPie(chart *pieChart) {
+	if chart != nil {
+		s.Pies = append(s.Pies, chart)
+	}
+}
+
+// addBar 添加柱状图，没有数据时忽略
+func (s *htmlSection) addBar(chart *barChart) {
+	if chart != nil {
+		s.Bars = append(s.Bars, chart)
+	}
+}
+
+// summary 添加摘要中的一项
+func (s *htmlSection) summary(label string, value string) {
+	s.Summary = append(s.Summary, outputField{Label: label, Value: value})
+}
+
+// authLabel 返回身份验证名称归一化后的显示名称，无法识别时使用原文
+func authLabel(authentication string) string {
+	if auth := ParseAuthType(authentication); auth != AuthUnknown {
+		return auth.String()
+	}
+	if authentication == "" {
+		return "未知"
+	}
+	return authentication
+}
+
+// addScanCharts 添加扫描结果的摘要、安全类型分布和各频段的信道占用
+func (s *htmlSection) addScanCharts(records []ScanRecord) {
+	ssids := make(map[string]bool)
+	security := make(map[string]int)
+	open := 0
+	channels := make(map[string]map[int]int)
+	for _, record := range records {
+		ssids[record.SSID] = true
+		auth := ParseAuthType(record.Security)
+		if auth == AuthOpen {
+			open++
+		}
+		security[authLabel(record.Security)]++
+		if record.Channel != 0 {
+			if channels[record.Band] == nil {
+				channels[record.Band] = make(map[int]int)
+			}
+			channels[record.Band][record.Channel]++
+		}
+	}
+	s.summary("BSSID", strconv.Itoa(len(records)))
+	s.summary("SSID", strconv.Itoa(len(ssids)))
+	s.summary("开放网络", strconv.Itoa(open))
+	s.addPie(newPieChart("安全类型分布", security))
+
+	bands := []string{Band2G, Band5G, Band6G}
+	for band := range channels {
+		if band != Band2G && band != Band5G && band != Band6G {
+			bands = append(bands, band)
+		}
+	}
+	for _, band := range bands {
+		if len(channels[band]) == 0 {
+			continue
+		}
+		var numbers []int
+		for channel := range channels[band] {
+			numbers = append(numbers, channel)
+		}
+		sort.Ints(numbers)
+		color := bandColors[band]
+		if color == "" {
+			color = chartColors[len(chartColors)-1]
+		}
+		var values []barValue
+		for _, channel := range numbers {
+			values = append(values, barValue{strconv.Itoa(channel), channels[band][channel], color})
+		}
+		title := "信道占用（BSSID数量）"
+		if band != "" {
+			title = fmt.Sprintf("%s 信道占用（BSSID数量）", band)
+		}
+		s.addBar(newBarChart(title, values))
+	}
+}
+
+// addSavedCharts 添加已保存网络的摘要和身份验证类型分布
+func (s *htmlSection) addSavedCharts(records []SavedRecord) {
+	auth := make(map[string]int)
+	withKey := 0
+	for _, record := range records {
+		auth[authLabel(record.Authentication)]++
+		if record.KeyStatus == keyStatusNames[KeyPresent] {
+			withKey++
+		}
+	}
+	s.summary("已保存网络", strconv.Itoa(len(records)))
+	s.summary("已获取密钥", strconv.Itoa(withKey))
+	s.addPie(newPieChart("身份验证类型分布", auth))
+}
+
+// addAuditCharts 添加密钥审计的摘要和强度分布
+func (s *htmlSection) addAuditCharts(payload AuditPayload) {
+	counts := make([]int, len(strengthLabels))
+	findings := 0
+	for _, audit := range payload.Audits {
+		if audit.Strength >= 0 && audit.Strength < len(counts) {
+			counts[audit.Strength]++
+		}
+		findings += len(audit.Findings)
+	}
+	s.summary("审计网络", strconv.Itoa(len(payload.Audits)))
+	s.summary("未审计", strconv.Itoa(len(payload.Skipped)))
+	s.summary("极弱或弱", strconv.Itoa(counts[0]+counts[1]))
+	s.summary("问题", strconv.Itoa(findings))
+
+	var values []barValue
+	for i, label := range strengthLabels {
+		values = append(values, barValue{label, counts[i], strengthColors[i]})
+	}
+	s.addBar(newBarChart("密钥强度分布", values))
+}
+
+// addHygieneCharts 添加卫生检查的摘要和各类问题的数量
+func (s *htmlSection) addHygieneCharts(report *HygieneReport) {
+	counts := make(map[HygieneKind]int)
+	for _, finding := range report.Findings {
+		counts[finding.Kind]++
+	}
+	s.summary("数据来源", report.Source)
+	s.summary("已保存网络", strconv.Itoa(report.Total))
+	s.summary("问题", strconv.Itoa(len(report.Findings)))
+
+	var values []barValue
+	for i, kind := range hygieneKindOrder {
+		values = append(values, barValue{hygieneKindLabels[kind], counts[kind], chartColors[i%len(chartColors)]})
+	}
+	if len(report.Findings) > 0 {
+		s.addBar(newBarChart("各类问题数量", values))
+	}
+}
+
+// addDiffSummary 添加快照比较的摘要
+func (s *htmlSection) addDiffSummary(diff *SnapshotDiff) {
+	s.summary("新增", strconv.Itoa(len(diff.Added)))
+	s.summary("删除", strconv.Itoa(len(diff.Removed)))
+	s.summary("变化", strconv.Itoa(len(diff.Changed)))
+	s.summary("未变化", strconv.Itoa(diff.Unchanged))
+}
+
+// addBruteSummary 添加爆破结果的摘要和尝试过的密码
+func (s *htmlSection) addBruteSummary(record BruteRecord) {
+	s.summary("SSID", record.SSID)
+	if record.Success {
+		s.summary("结果", "成功")
+		s.summary("密码", displaySecret(record.Password, record.PasswordMask, true))
+	} else {
+		s.summary("结果", "失败")
+	}
+	s.summary("尝试次数", strconv.Itoa(record.TestedCount))
+	s.summary("耗时", (time.Duration(record.ElapsedSeconds * float64(time.Second))).Round(time.Millisecond).String())
+	if record.ElapsedSeconds > 0 {
+		s.summary("平均速度", fmt.Sprintf("%.2f 次/秒", float64(record.TestedCount)/record.ElapsedSeconds))
+	}
+
+	s.Table = nil
+	if len(record.FailedAttempts) > 0 {
+		s.Table = &htmlTable{Header: []string{"序号", "失败的密码"}}
+		for i, password := range record.FailedAttempts {
+			s.Table.Rows = append(s.Table.Rows, []string{strconv.Itoa(i + 1), password})
+		}
+	}
+}
+
+// newHTMLSection 由结果文件生成报告中的一节
+func newHTMLSection(index int, envelope *utils.Envelope, reveal bool) (*htmlSection, error) {
+	content, err := loadReportContent(envelope, reveal && !envelope.Redacted)
+	if err != nil {
+		return nil, err
+	}
+	section := &htmlSection{
+		ID:       fmt.Sprintf("run-%d", index+1),
+		Title:    content.title,
+		Started:  envelope.Started.Local().Format("2006-01-02 15:04:05"),
+		Meta:     envelopeFields(envelope),
+		Warnings: envelope.Warnings,
+		Table:    newHTMLTable(content.records),
+	}
+
+	switch payload := content.payload.(type) {
+	case []ScanRecord:
+		section.addScanCharts(payload)
+	case []SavedRecord:
+		section.addSavedCharts(payload)
+	case AuditPayload:
+		section.addAuditCharts(payload)
+	case *HygieneReport:
+		section.addHygieneCharts(payload)
+	case *SnapshotDiff:
+		section.addDiffSummary(payload)
+	case BruteRecord:
+		section.addBruteSummary(payload)
+	}
+	return section, nil
+}
+
+// htmlReportTemplate 自包含的HTML报告模板，CSS、排序脚本和SVG图表都内嵌在页面中，不引用外部资源
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h2 { border-bottom: 2px solid #ddd; padding-bottom: 4px; margin-top: 2em; }
+table { border-collapse: collapse; margin: 0.5em 0 1.5em 0; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+table.sortable th { cursor: pointer; user-select: none; }
+table.sortable th[data-order="asc"]::after { content: " ▲"; }
+table.sortable th[data-order="desc"]::after { content: " ▼"; }
+tbody tr:nth-child(even) { background: #fafafa; }
+.meta th { width: 8em; }
+.muted { color: #777; }
+.warning { color: #c0392b; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; margin: 1em 0; }
+.card { border: 1px solid #ddd; border-radius: 6px; padding: 8px 16px; min-width: 6em; }
+.card .label { color: #777; font-size: 0.85em; }
+.card .value { font-size: 1.4em; font-weight: bold; }
+.charts { display: flex; flex-wrap: wrap; gap: 24px; align-items: flex-start; }
+figure { margin: 0; }
+figcaption { font-weight: bold; margin-bottom: 6px; }
+.legend { list-style: none; padding: 0; margin: 6px 0 0 0; }
+.legend span { display: inline-block; width: 10px; height: 10px; margin-right: 6px; }
+svg text { font-size: 11px; fill: #333; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="muted">生成时间: {{.Generated}}，共 {{len .Sections}} 次运行</p>
+
+{{if gt (len .Sections) 1}}<h2>概览</h2>
+<table class="sortable">
+<thead><tr><th>#</th><th>结果</th><th>开始时间</th><th>警告</th></tr></thead>
+<tbody>
+{{range $i, $s := .Sections}}<tr><td>{{$s.ID}}</td><td><a href="#{{$s.ID}}">{{$s.Title}}</a></td><td>{{$s.Started}}</td><td>{{len $s.Warnings}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+
+{{range .Sections}}
+<section id="{{.ID}}">
+<h2>{{.Title}}</h2>
+<details open>
+<summary>运行信息</summary>
+<table class="meta">
+{{range .Meta}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+</details>
+{{if .Warnings}}<ul>
+{{range .Warnings}}<li class="warning">警告: {{.}}</li>
+{{end}}</ul>{{end}}
+
+{{if .Summary}}<div class="cards">
+{{range .Summary}}<div class="card"><div class="label">{{.Label}}</div><div class="value">{{.Value}}</div></div>
+{{end}}</div>{{end}}
+
+{{if or .Pies .Bars}}<div class="charts">
+{{range .Pies}}<figure>
+<figcaption>{{.Title}}</figcaption>
+<svg width="200" height="200" viewBox="0 0 200 200" role="img">
+{{if .Full}}{{range .Slices}}<circle cx="100" cy="100" r="90" fill="{{.Color}}"><title>{{.Label}}: {{.Count}}</title></circle>{{end}}
+{{else}}{{range .Slices}}<path d="{{.Path}}" fill="{{.Color}}" stroke="#fff"><title>{{.Label}}: {{.Count}} ({{.Percent}})</title></path>
+{{end}}{{end}}</svg>
+<ul class="legend">
+{{range .Slices}}<li><span style="background: {{.Color}}"></span>{{.Label}}: {{.Count}} ({{.Percent}})</li>
+{{end}}</ul>
+</figure>
+{{end}}
+{{range .Bars}}<figure>
+<figcaption>{{.Title}}</figcaption>
+<svg width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img">
+{{$chart := .}}{{range .Bars}}<rect x="{{.X}}" y="{{.Y}}" width="{{$chart.BarWidth}}" height="{{.Height}}" fill="{{.Color}}"><title>{{.Label}}: {{.Value}}</title></rect>
+<text x="{{.CenterX}}" y="{{.Y}}" dy="-4" text-anchor="middle">{{.Value}}</text>
+<text x="{{.CenterX}}" y="{{$chart.Baseline}}" dy="14" text-anchor="middle">{{.Label}}</text>
+{{end}}<line x1="0" y1="{{.Baseline}}" x2="{{.Width}}" y2="{{.Baseline}}" stroke="#999"/>
+</svg>
+</figure>
+{{end}}</div>{{end}}
+
+{{with .Table}}<table class="sortable">
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>{{else}}<p class="muted">没有记录</p>{{end}}
+</section>
+{{end}}
+
+<script>
+// 点击表头按该列排序，数值列按数值比较，再次点击反向排序
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  var headers = table.querySelectorAll("th");
+  headers.forEach(function (th, column) {
+    th.addEventListener("click", function () {
+      var ascending = th.getAttribute("data-order") !== "asc";
+      headers.forEach(function (other) { other.removeAttribute("data-order"); });
+      th.setAttribute("data-order", ascending ? "asc" : "desc");
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].textContent.trim(), y = b.cells[column].textContent.trim();
+        var nx = parseFloat(x), ny = parseFloat(y);
+        var order = (!isNaN(nx) && !isNaN(ny)) ? nx - ny : x.localeCompare(y, "zh-CN");
+        return ascending ? order : -order;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// RenderHTMLReport 将一个或多个结果文件生成为一个自包含的HTML报告，包含可排序的表格、安全类型分布、信道占用、
+// 密钥强度等图表，不引用外部资源，可以直接发送或离线打开
+func RenderHTMLReport(envelopes []*utils.Envelope, reveal bool) (string, error) {
+	var sections []*htmlSection
+	for i, envelope := range envelopes {
+		section, err := newHTMLSection(i, envelope, reveal)
+		if err != nil {
+			return "", err
+		}
+		sections = append(sections, section)
+	}
+
+	title := "WifiSOS 评估报告"
+	if len(sections) == 1 {
+		title = fmt.Sprintf("%s - %s", sections[0].Title, envelopes[0].Finished.Local().Format("2006-01-02 15:04:05"))
+	}
+	var output strings.Builder
+	err := htmlReportTemplate.Execute(&output, map[string]interface{}{
+		"Title":     title,
+		"Sections":  sections,
+		"Generated": time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("生成HTML报告失败: %v", err)
+	}
+	return output.String(), nil
+}
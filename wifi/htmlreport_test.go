@@ -0,0 +1,54 @@
+package wifi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"WifiSOS/utils"
+)
+
+func TestHTMLReportScanCharts(t *testing.T) {
+	networks := append(parseNetshOutput(netshNetworksSample), parseNetshOutput(netshNetworksSampleZH)...)
+	envelope := utils.NewEnvelope([]string{"wifisos", "scan"})
+	if err := envelope.SetPayload(ScanSchema, ScanPayload(networks)); err != nil {
+		t.Fatal(err)
+	}
+
+	section, err := newHTMLSection(0, envelope, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := make(map[string]string)
+	for _, field := range section.Summary {
+		summary[field.Label] = field.Value
+	}
+	if summary["BSSID"] != "5" || summary["SSID"] != "4" || summary["开放网络"] != "1" {
+		t.Errorf("摘要 = %v，期望 5 个BSSID、4 个SSID、1 个开放网络", summary)
+	}
+
+	if len(section.Pies) != 1 {
+		t.Fatalf("饼图数量 = %d，期望 1", len(section.Pies))
+	}
+	slices := make(map[string]int)
+	for _, slice := range section.Pies[0].Slices {
+		slices[slice.Label] = slice.Count
+	}
+	want := map[string]int{
+		AuthWPA2PSK.String(): 2,
+		AuthOpen.String():    1,
+		AuthWEP.String():     1,
+		AuthWPA3SAE.String(): 1,
+	}
+	if !reflect.DeepEqual(slices, want) {
+		t.Errorf("安全类型分布 = %v，期望 %v", slices, want)
+	}
+
+	html, err := RenderHTMLReport([]*utils.Envelope{envelope}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "未知") {
+		t.Error("HTML报告中出现未知的安全类型")
+	}
+}
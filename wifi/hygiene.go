@@ -0,0 +1,517 @@
+package wifi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// HygieneKind 表示已保存网络卫生检查的问题类型
+type HygieneKind string
+
+// 卫生检查问题类型，按严重程度排列
+const (
+	HygieneOpenAutoConnect HygieneKind = "open-autoconnect"
+	HygieneWeakSecurity    HygieneKind = "weak-security"
+	HygieneDuplicateSSID   HygieneKind = "duplicate-ssid"
+	HygieneStaleProfile    HygieneKind = "stale-profile"
+)
+
+// hygieneKindOrder 报告中问题类型的排列顺序
+var hygieneKindOrder = []HygieneKind{HygieneOpenAutoConnect, HygieneWeakSecurity, HygieneDuplicateSSID, HygieneStaleProfile}
+
+// hygieneKindLabels 问题类型的显示名称
+var hygieneKindLabels = map[HygieneKind]string{
+	HygieneOpenAutoConnect: "开放网络自动连接",
+	HygieneWeakSecurity:    "WEP/TKIP弱加密",
+	HygieneDuplicateSSID:   "同名网络密钥不同",
+	HygieneStaleProfile:    "扫描历史中未出现",
+}
+
+// 修复命令中需要用户替换的占位符
+const (
+	placeholderNewPassword = "<新密码>"
+	placeholderPassword    = "<密码>"
+	placeholderNetworkID   = "<Network Id>"
+	placeholderProfile     = "<要删除的配置文件>"
+	placeholderIndex       = "<编号>"
+)
+
+// HygieneFinding 表示一条卫生检查问题及其修复命令
+type HygieneFinding struct {
+	Kind     HygieneKind `json:"kind"`
+	SSID     string      `json:"ssid"`
+	Profiles []string    `json:"profiles"` // 涉及的配置文件
+	Detail   string      `json:"detail"`
+	Commands []string    `json:"commands"`           // 修复命令，按顺序执行
+	Warnings []string    `json:"warnings,omitempty"` // 无法生成安全命令的配置文件，需手动处理
+}
+
+// Label 返回问题类型的显示名称
+func (f HygieneFinding) Label() string {
+	if label, ok := hygieneKindLabels[f.Kind]; ok {
+		return label
+	}
+	return string(f.Kind)
+}
+
+// HygieneReport 表示已保存网络的卫生检查结果
+type HygieneReport struct {
+	Source   string           `json:"source"`
+	Total    int              `json:"total"`
+	History  *ScanHistory     `json:"history"` // 为nil时未检查长期未出现的配置文件
+	Since    time.Time        `json:"since"`   // 扫描历史的统计起始时间
+	Findings []HygieneFinding `json:"findings"`
+}
+
+// hygieneProfile 表示卫生检查中的一个配置文件，index为在来源中的顺序
+type hygieneProfile struct {
+	network SavedWiFi
+	index   int
+	source  string
+}
+
+// isClient 判断是否为客户端配置，OpenWrt的ap模式是路由器自身广播的网络
+func (p hygieneProfile) isClient() bool {
+	return p.network.NetworkType != "ap"
+}
+
+// name 返回配置文件名称，没有名称时使用SSID
+func (p hygieneProfile) name() string {
+	if p.network.ProfileName != "" {
+		return p.network.ProfileName
+	}
+	return p.network.SSID
+}
+
+// uciSection 返回OpenWrt中的段地址，按wifi-iface出现顺序编号
+func (p hygieneProfile) uciSection() string {
+	return fmt.Sprintf("wireless.@wifi-iface[%d]", p.index)
+}
+
+// label 返回报告中显示的配置文件标识
+func (p hygieneProfile) label() string {
+	switch p.source {
+	case SourceWpaSupplicant:
+		if p.network.ProfileName != "" {
+			return fmt.Sprintf("网络编号 %d (%s)", p.index, p.network.ProfileName)
+		}
+		return fmt.Sprintf("网络编号 %d", p.index)
+	case SourceOpenWrt:
+		return fmt.Sprintf("%s (%s)", p.uciSection(), p.name())
+	}
+	return p.name()
+}
+
+// shellQuote 按POSIX shell规则用单引号转义参数
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// netshQuote 用双引号引用netsh参数，cmd.exe在双引号内没有转义双引号的方法，
+// 且会展开%VAR%形式的环境变量，包含双引号、百分号或控制字符的名称无法安全引用
+func netshQuote(value string) (string, bool) {
+	if strings.ContainsAny(value, `"%`) || strings.ContainsFunc(value, unicode.IsControl) {
+		return "", false
+	}
+	return `"` + value + `"`, true
+}
+
+// commandWarning 返回无法为该配置文件生成修复命令的原因，可以生成时返回空字符串
+func (p hygieneProfile) commandWarning() string {
+	if p.source != SourceNetsh && p.source != SourceWindowsXML {
+		return ""
+	}
+	if _, ok := netshQuote(p.name()); ok {
+		return ""
+	}
+	return fmt.Sprintf("配置文件名称 %q 包含双引号、百分号或控制字符，无法生成安全的netsh命令，请在Windows设置中手动处理", p.name())
+}
+
+// findingWarnings 收集一组配置文件中无法生成修复命令的警告
+func findingWarnings(profiles ...hygieneProfile) []string {
+	var warnings []string
+	for _, profile := range profiles {
+		if warning := profile.commandWarning(); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// adbShell 返回通过adb在设备上执行的命令，设备端shell会再解析一次引号
+func adbShell(command string) string {
+	return "adb shell " + shellQuote(command)
+}
+
+// androidForgetCommands 返回删除Android网络的命令，networkId需先通过list-networks查询
+func (p hygieneProfile) androidForgetCommands() []string {
+	return []string{
+		adbShell("cmd wifi list-networks") + " | grep -F " + shellQuote(p.network.SSID),
+		adbShell("cmd wifi forget-network " + placeholderNetworkID),
+	}
+}
+
+// uciCommands 在修改命令后追加提交和重新加载
+func uciCommands(commands ...string) []string {
+	return append(commands, "uci commit wireless", "wifi reload")
+}
+
+// disableAutoConnectCommands 返回取消自动连接的命令
+func (p hygieneProfile) disableAutoConnectCommands() []string {
+	switch p.source {
+	case SourceNetsh, SourceWindowsXML:
+		name, ok := netshQuote(p.name())
+		if !ok {
+			return nil
+		}
+		return []string{fmt.Sprintf("netsh wlan set profileparameter name=%s connectionmode=manual", name)}
+	case SourceNetworkManager:
+		return []string{fmt.Sprintf("nmcli connection modify %s connection.autoconnect no", shellQuote(p.name()))}
+	case SourceWpaSupplicant:
+		return []string{fmt.Sprintf("wpa_cli disable_network %d", p.index), "wpa_cli save_config"}
+	case SourceAndroid:
+		// Android没有命令行方式关闭单个网络的自动连接，只能删除
+		return p.androidForgetCommands()
+	case SourceOpenWrt:
+		return uciCommands(fmt.Sprintf("uci set %s.disabled='1'", p.uciSection()))
+	}
+	return nil
+}
+
+// deleteCommands 返回删除配置文件的命令
+func (p hygieneProfile) deleteCommands() []string {
+	switch p.source {
+	case SourceNetsh, SourceWindowsXML:
+		name, ok := netshQuote(p.name())
+		if !ok {
+			return nil
+		}
+		return []string{fmt.Sprintf("netsh wlan delete profile name=%s", name)}
+	case SourceNetworkManager:
+		return []string{fmt.Sprintf("nmcli connection delete %s", shellQuote(p.name()))}
+	case SourceWpaSupplicant:
+		return []string{fmt.Sprintf("wpa_cli remove_network %d", p.index), "wpa_cli save_config"}
+	case SourceAndroid:
+		return p.androidForgetCommands()
+	case SourceOpenWrt:
+		return uciCommands(fmt.Sprintf("uci delete %s", p.uciSection()))
+	}
+	return nil
+}
+
+// placeholderDeleteCommands 返回删除配置文件的命令模板，无法确定要删除哪个配置文件时使用，占位符需要用户替换
+func placeholderDeleteCommands(source string) []string {
+	switch source {
+	case SourceNetsh, SourceWindowsXML:
+		return []string{fmt.Sprintf(`netsh wlan delete profile name="%s"`, placeholderProfile)}
+	case SourceNetworkManager:
+		return []string{"nmcli connection delete " + shellQuote(placeholderProfile)}
+	case SourceWpaSupplicant:
+		return []string{"wpa_cli remove_network " + placeholderIndex, "wpa_cli save_config"}
+	case SourceOpenWrt:
+		return uciCommands(fmt.Sprintf("uci delete wireless.@wifi-iface[%s]", placeholderIndex))
+	}
+	return nil
+}
+
+// keepProfile 选择同名配置文件中应保留的一个：只有一个配置文件有密钥、其余均为开放网络时保留有密钥的，
+// 否则无法判断哪个密钥正确，返回-1
+func keepProfile(group []hygieneProfile) int {
+	keep := -1
+	for i, profile := range group {
+		if profile.network.KeyStatus == KeyAbsent {
+			continue
+		}
+		if keep >= 0 {
+			return -1
+		}
+		keep = i
+	}
+	return keep
+}
+
+// upgradeSecurityCommands 返回改用WPA2-AES的命令
+// WEP密钥不能作为WPA2密码使用，需要设置新密码
+func (p hygieneProfile) upgradeSecurityCommands(wep bool) []string {
+	enterprise := p.network.IsEnterprise()
+	switch p.source {
+	case SourceNetsh, SourceWindowsXML:
+		name, ok := netshQuote(p.name())
+		if !ok {
+			return nil
+		}
+		command := "netsh wlan set profileparameter name=" + name
+		switch {
+		case enterprise:
+			command += " authentication=WPA2 encryption=AES"
+		case wep:
+			command += fmt.Sprintf(` authentication=WPA2PSK encryption=AES keyType=passphrase keyMaterial="%s"`, placeholderNewPassword)
+		default:
+			command += " authentication=WPA2PSK encryption=AES"
+		}
+		return []string{command}
+	case SourceNetworkManager:
+		command := "nmcli connection modify " + shellQuote(p.name())
+		if wep {
+			command += " wifi-sec.key-mgmt wpa-psk wifi-sec.wep-key0 '' wifi-sec.psk " + shellQuote(placeholderNewPassword)
+		}
+		return []string{command + " wifi-sec.proto rsn wifi-sec.pairwise ccmp wifi-sec.group ccmp"}
+	case SourceWpaSupplicant:
+		var commands []string
+		if wep {
+			commands = append(commands,
+				fmt.Sprintf("wpa_cli set_network %d key_mgmt WPA-PSK", p.index),
+				fmt.Sprintf("wpa_cli set_network %d psk %s", p.index, shellQuote(`"`+placeholderNewPassword+`"`)))
+		}
+		return append(commands,
+			fmt.Sprintf("wpa_cli set_network %d proto RSN", p.index),
+			fmt.Sprintf("wpa_cli set_network %d pairwise CCMP", p.index),
+			fmt.Sprintf("wpa_cli set_network %d group CCMP", p.index),
+			"wpa_cli save_config")
+	case SourceAndroid:
+		password := placeholderPassword
+		if wep {
+			password = placeholderNewPassword
+		}
+		return append(p.androidForgetCommands(),
+			adbShell(fmt.Sprintf("cmd wifi add-network %s wpa2 %s", shellQuote(p.network.SSID), shellQuote(password))))
+	case SourceOpenWrt:
+		switch {
+		case enterprise:
+			return uciCommands(fmt.Sprintf("uci set %s.encryption='wpa2+ccmp'", p.uciSection()))
+		case wep:
+			return uciCommands(
+				fmt.Sprintf("uci set %s.encryption='psk2+ccmp'", p.uciSection()),
+				fmt.Sprintf("uci set %s.key=%s", p.uciSection(), shellQuote(placeholderNewPassword)))
+		}
+		return uciCommands(fmt.Sprintf("uci set %s.encryption='psk2+ccmp'", p.uciSection()))
+	}
+	return nil
+}
+
+// weakSecurityDetail 判断配置是否使用WEP、TKIP或第一代WPA，返回问题说明、是否为WEP以及是否存在问题
+// NetworkManager、wpa_supplicant和Android的WPA-PSK是密钥管理方式，同时涵盖WPA和WPA2，只按加密方式判断
+func weakSecurityDetail(network SavedWiFi, source string) (string, bool, bool) {
+	wpaIsFirstGeneration := source == SourceNetsh || source == SourceWindowsXML || source == SourceOpenWrt
+	switch auth := network.AuthType(); {
+	case auth == AuthWEP:
+		return "使用WEP加密，可在数分钟内被破解", true, true
+	case wpaIsFirstGeneration && (auth == AuthWPAPSK || auth == AuthWPAEAP):
+		return fmt.Sprintf("使用第一代WPA（%s），通常只能使用TKIP加密", auth), false, true
+	case strings.Contains(strings.ToUpper(network.Cipher), "TKIP"):
+		return fmt.Sprintf("加密方式包含TKIP（%s），存在已知攻击且速率限制为54Mbps", network.Cipher), false, true
+	}
+	return "", false, false
+}
+
+// CheckSavedHygiene 检查已保存网络的配置卫生问题
+// history为nil时不检查长期未出现的配置文件，since为扫描历史的统计起始时间
+func CheckSavedHygiene(networks []SavedWiFi, source string, history *ScanHistory, since time.Time) *HygieneReport {
+	if source == "" {
+		source = DefaultSavedSource()
+	}
+	report := &HygieneReport{Source: source, Total: len(networks), History: history, Since: since}
+
+	profiles := make([]hygieneProfile, len(networks))
+	for i, network := range networks {
+		profiles[i] = hygieneProfile{network: network, index: i, source: source}
+	}
+
+	for _, profile := range profiles {
+		network := profile.network
+		if profile.isClient() && network.AuthType() == AuthOpen && network.ConnectionMode == ConnectionAuto {
+			detail := "开放网络且自动连接，附近任何同名热点都会被自动连接"
+			if network.NonBroadcast {
+				detail += "；同时设置为即使未广播也连接，设备会主动广播该SSID的探测请求"
+			}
+			report.Findings = append(report.Findings, HygieneFinding{
+				Kind:     HygieneOpenAutoConnect,
+				SSID:     network.SSID,
+				Profiles: []string{profile.label()},
+				Detail:   detail,
+				Commands: profile.disableAutoConnectCommands(),
+				Warnings: findingWarnings(profile),
+			})
+		}
+
+		if detail, wep, weak := weakSecurityDetail(network, source); weak {
+			if wep {
+				detail += "，需先在路由器上改用WPA2/WPA3并设置新密码"
+			} else {
+				detail += "，需先确认路由器已支持WPA2-AES"
+			}
+			report.Findings = append(report.Findings, HygieneFinding{
+				Kind:     HygieneWeakSecurity,
+				SSID:     network.SSID,
+				Profiles: []string{profile.label()},
+				Detail:   detail,
+				Commands: profile.upgradeSecurityCommands(wep),
+				Warnings: findingWarnings(profile),
+			})
+		}
+
+		if history != nil && profile.isClient() && network.SSID != "" && !history.Seen(network.SSID, since) {
+			detail := fmt.Sprintf("自 %s 以来的扫描历史中未出现，可能已不再使用", since.Format("2006-01-02"))
+			if last, ok := history.LastSeen[network.SSID]; ok {
+				detail = fmt.Sprintf("最近一次出现在 %s，可能已不再使用", last.Format("2006-01-02 15:04"))
+			}
+			report.Findings = append(report.Findings, HygieneFinding{
+				Kind:     HygieneStaleProfile,
+				SSID:     network.SSID,
+				Profiles: []string{profile.label()},
+				Detail:   detail,
+				Commands: profile.deleteCommands(),
+				Warnings: findingWarnings(profile),
+			})
+		}
+	}
+
+	report.Findings = append(report.Findings, duplicateSSIDFindings(profiles)...)
+
+	order := make(map[HygieneKind]int)
+	for i, kind := range hygieneKindOrder {
+		order[kind] = i
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if order[a.Kind] != order[b.Kind] {
+			return order[a.Kind] < order[b.Kind]
+		}
+		return a.SSID < b.SSID
+	})
+	return report
+}
+
+// duplicateSSIDFindings 查找同一SSID保存了多个密钥不同的配置文件
+// 只比较已获取密钥和无密钥的配置，无法读取密钥的配置不参与比较
+func duplicateSSIDFindings(profiles []hygieneProfile) []HygieneFinding {
+	groups := make(map[string][]hygieneProfile)
+	var order []string
+	for _, profile := range profiles {
+		network := profile.network
+		if !profile.isClient() || network.SSID == "" {
+			continue
+		}
+		if !network.HasPassword() && network.KeyStatus != KeyAbsent {
+			continue
+		}
+		if _, ok := groups[network.SSID]; !ok {
+			order = append(order, network.SSID)
+		}
+		groups[network.SSID] = append(groups[network.SSID], profile)
+	}
+
+	var findings []HygieneFinding
+	for _, ssid := range order {
+		group := groups[ssid]
+		keys := make(map[string]bool)
+		hasOpen := false
+		for _, profile := range group {
+			keys[profile.network.Password] = true
+			if profile.network.KeyStatus == KeyAbsent {
+				hasOpen = true
+			}
+		}
+		if len(keys) < 2 {
+			continue
+		}
+
+		finding := HygieneFinding{
+			Kind:   HygieneDuplicateSSID,
+			SSID:   ssid,
+			Detail: fmt.Sprintf("%d 个配置文件使用 %d 种不同的密钥", len(group), len(keys)),
+		}
+		if hasOpen {
+			finding.Detail += "；其中包含开放网络配置，同名的开放热点可以冒充该网络"
+		}
+		for _, profile := range group {
+			finding.Profiles = append(finding.Profiles, profile.label())
+		}
+
+		keep := keepProfile(group)
+		if keep < 0 {
+			// 无法判断哪个密钥正确，只给出需要替换占位符的命令，避免照原样执行时删除全部配置
+			finding.Detail += "；无法自动判断应保留哪个配置文件，确认正确的配置后将命令中的占位符替换为要删除的配置文件，逐个执行"
+			if group[0].source == SourceAndroid {
+				finding.Commands = group[0].androidForgetCommands()
+			} else {
+				finding.Commands = placeholderDeleteCommands(group[0].source)
+			}
+			findings = append(findings, finding)
+			continue
+		}
+
+		finding.Detail += fmt.Sprintf("；保留设置了密钥的 %s，删除其余的开放网络配置", group[keep].label())
+		var remove []hygieneProfile
+		for i, profile := range group {
+			if i != keep {
+				remove = append(remove, profile)
+			}
+		}
+		// 从后往前删除，避免按序号定位的配置在删除后编号变化
+		for i := len(remove) - 1; i >= 0; i-- {
+			finding.Commands = append(finding.Commands, remove[i].deleteCommands()...)
+		}
+		finding.Warnings = findingWarnings(remove...)
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// FormatHygieneReport 格式化已保存网络的卫生检查结果
+func FormatHygieneReport(report *HygieneReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("=== 已保存网络卫生检查 - %s ===\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	output.WriteString(fmt.Sprintf("数据来源: %s\n", report.Source))
+	output.WriteString(fmt.Sprintf("已保存网络: %d 个\n", report.Total))
+	if report.History != nil {
+		output.WriteString(fmt.Sprintf("扫描历史: %d 个文件，统计 %s 之后的扫描记录\n",
+			len(report.History.Files), report.Since.Format("2006-01-02")))
+	} else {
+		output.WriteString("扫描历史: 未找到扫描结果文件，跳过长期未出现检查\n")
+	}
+	switch report.Source {
+	case SourceWpaSupplicant:
+		output.WriteString("注意: wpa_cli命令中的网络编号按配置文件中network块的顺序计算，仅适用于正在使用该配置文件的wpa_supplicant\n")
+	case SourceOpenWrt:
+		output.WriteString("注意: uci命令按wifi-iface段出现的顺序定位，执行删除后后续段的编号会变化\n")
+	case SourceAndroid:
+		output.WriteString("注意: Android命令需要通过list-networks查询Network Id后替换命令中的占位符\n")
+	}
+	output.WriteString("\n")
+
+	if len(report.Findings) == 0 {
+		output.WriteString("未发现问题\n")
+		return output.String()
+	}
+
+	counts := make(map[HygieneKind]int)
+	for _, finding := range report.Findings {
+		counts[finding.Kind]++
+	}
+	output.WriteString(fmt.Sprintf("发现 %d 个问题:\n", len(report.Findings)))
+	for _, kind := range hygieneKindOrder {
+		if counts[kind] > 0 {
+			output.WriteString(fmt.Sprintf("  %s: %d\n", hygieneKindLabels[kind], counts[kind]))
+		}
+	}
+	output.WriteString("\n")
+
+	for i, finding := range report.Findings {
+		output.WriteString(fmt.Sprintf("#%d [%s] %s\n", i+1, finding.Label(), finding.SSID))
+		output.WriteString(fmt.Sprintf("  配置文件: %s\n", strings.Join(finding.Profiles, ", ")))
+		output.WriteString(fmt.Sprintf("  说明: %s\n", finding.Detail))
+		output.WriteString("  修复命令:\n")
+		for _, command := range finding.Commands {
+			output.WriteString(fmt.Sprintf("    %s\n", command))
+		}
+		for _, warning := range finding.Warnings {
+			output.WriteString(fmt.Sprintf("  警告: %s\n", warning))
+		}
+		output.WriteString("\n")
+	}
+	return output.String()
+}
@@ -0,0 +1,76 @@
+package wifi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHygieneNetshCommandQuoting(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []string
+		warned   bool
+	}{
+		{"Cafe WiFi", []string{`netsh wlan set profileparameter name="Cafe WiFi" connectionmode=manual`}, false},
+		{"Joe's & <Co>", []string{`netsh wlan set profileparameter name="Joe's & <Co>" connectionmode=manual`}, false},
+		{`Free" & del /q *`, nil, true},
+		{"100%PATH%", nil, true},
+		{"Line\r\nBreak", nil, true},
+	}
+	for _, test := range tests {
+		network := SavedWiFi{ProfileName: test.name, SSID: test.name, Authentication: "Open", KeyStatus: KeyAbsent, ConnectionMode: ConnectionAuto}
+		report := CheckSavedHygiene([]SavedWiFi{network}, SourceNetsh, nil, time.Time{})
+		if len(report.Findings) != 1 {
+			t.Fatalf("%q: 发现 %d 个问题，期望 1", test.name, len(report.Findings))
+		}
+		finding := report.Findings[0]
+		if !reflect.DeepEqual(finding.Commands, test.commands) {
+			t.Errorf("%q: 修复命令 = %q，期望 %q", test.name, finding.Commands, test.commands)
+		}
+		if (len(finding.Warnings) > 0) != test.warned {
+			t.Errorf("%q: 警告 = %q，期望有警告=%v", test.name, finding.Warnings, test.warned)
+		}
+	}
+}
+
+func TestHygieneShellCommandQuoting(t *testing.T) {
+	network := SavedWiFi{ProfileName: `it's "$(reboot)"`, SSID: "x", Authentication: "Open", KeyStatus: KeyAbsent, ConnectionMode: ConnectionAuto}
+	report := CheckSavedHygiene([]SavedWiFi{network}, SourceNetworkManager, nil, time.Time{})
+	want := []string{`nmcli connection modify 'it'\''s "$(reboot)"' connection.autoconnect no`}
+	if len(report.Findings) != 1 || !reflect.DeepEqual(report.Findings[0].Commands, want) || len(report.Findings[0].Warnings) != 0 {
+		t.Errorf("修复命令 = %+v，期望 %q", report.Findings, want)
+	}
+}
+
+func TestHygieneDuplicateSSIDCommands(t *testing.T) {
+	secured := SavedWiFi{ProfileName: "Cafe", SSID: "Cafe", Password: "12345678", KeyStatus: KeyPresent, Authentication: "WPA2-Personal", ConnectionMode: "manual"}
+	other := SavedWiFi{ProfileName: "Cafe 2", SSID: "Cafe", Password: "87654321", KeyStatus: KeyPresent, Authentication: "WPA2-Personal", ConnectionMode: "manual"}
+	open := SavedWiFi{ProfileName: "Cafe 3", SSID: "Cafe", KeyStatus: KeyAbsent, Authentication: "Open", ConnectionMode: "manual"}
+	tests := []struct {
+		source   string
+		networks []SavedWiFi
+		want     []string
+	}{
+		// 只有一个配置文件有密钥时保留它，删除开放网络配置
+		{SourceNetsh, []SavedWiFi{open, secured}, []string{`netsh wlan delete profile name="Cafe 3"`}},
+		{SourceWpaSupplicant, []SavedWiFi{open, secured, open}, []string{
+			"wpa_cli remove_network 2", "wpa_cli save_config", "wpa_cli remove_network 0", "wpa_cli save_config"}},
+		// 多个不同的密钥无法判断，只给出占位符命令
+		{SourceNetsh, []SavedWiFi{secured, other}, []string{`netsh wlan delete profile name="<要删除的配置文件>"`}},
+		{SourceNetworkManager, []SavedWiFi{secured, other, open}, []string{`nmcli connection delete '<要删除的配置文件>'`}},
+		{SourceOpenWrt, []SavedWiFi{secured, other}, []string{"uci delete wireless.@wifi-iface[<编号>]", "uci commit wireless", "wifi reload"}},
+	}
+	for _, test := range tests {
+		report := CheckSavedHygiene(test.networks, test.source, nil, time.Time{})
+		var commands []string
+		for _, finding := range report.Findings {
+			if finding.Kind == HygieneDuplicateSSID {
+				commands = finding.Commands
+			}
+		}
+		if !reflect.DeepEqual(commands, test.want) {
+			t.Errorf("%s %d 个配置文件: 修复命令 = %q，期望 %q", test.source, len(test.networks), commands, test.want)
+		}
+	}
+}
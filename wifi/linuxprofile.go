@@ -0,0 +1,474 @@
+package wifi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Linux上已保存网络配置的默认位置
+const (
+	DefaultNetworkManagerDir = "/etc/NetworkManager/system-connections"
+	DefaultWpaSupplicantConf = "/etc/wpa_supplicant/wpa_supplicant.conf"
+)
+
+// eapMethodAliases NetworkManager和wpa_supplicant中EAP方法名称对应的显示名称
+var eapMethodAliases = map[string]string{
+	"peap":     "PEAP",
+	"tls":      "EAP-TLS",
+	"ttls":     "EAP-TTLS",
+	"pwd":      "EAP-PWD",
+	"fast":     "EAP-FAST",
+	"leap":     "LEAP",
+	"sim":      "EAP-SIM",
+	"aka":      "EAP-AKA",
+	"aka'":     "EAP-AKA'",
+	"md5":      "EAP-MD5",
+	"gtc":      "EAP-GTC",
+	"mschapv2": "EAP-MSCHAPv2",
+}
+
+// keyMgmtNames 密钥管理方式对应的身份验证名称
+var keyMgmtNames = map[string]string{
+	"none":                "Open",
+	"wpa-psk":             "WPA-PSK",
+	"wpa-psk-sha256":      "WPA-PSK",
+	"sae":                 "WPA3-SAE",
+	"ft-psk":              "WPA-PSK",
+	"ft-sae":              "WPA3-SAE",
+	"wpa-eap":             "WPA-EAP",
+	"wpa-eap-sha256":      "WPA-EAP",
+	"wpa-eap-suite-b-192": "WPA3-EAP-192",
+	"ft-eap":              "WPA-EAP",
+	"ieee8021x":           "IEEE8021X",
+	"owe":                 "OWE",
+}
+
+// eapMethodDisplay 返回EAP方法的显示名称
+func eapMethodDisplay(method string) string {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if name, ok := eapMethodAliases[method]; ok {
+		return name
+	}
+	return strings.ToUpper(method)
+}
+
+// authenticationFromKeyMgmt 将密钥管理方式转换为身份验证名称，多个值用"/"分隔
+func authenticationFromKeyMgmt(values []string, wep bool) string {
+	var names []string
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		name, ok := keyMgmtNames[value]
+		if !ok {
+			name = strings.ToUpper(value)
+		}
+		if name == "Open" && wep {
+			name = "WEP"
+		}
+		names = appendUnique(names, name)
+	}
+	return strings.Join(names, "/")
+}
+
+// isEAPKeyMgmt 判断密钥管理方式是否为802.1X认证
+func isEAPKeyMgmt(authentication string) bool {
+	lower := strings.ToLower(authentication)
+	return strings.Contains(lower, "eap") || strings.Contains(lower, "8021x")
+}
+
+// parseKeyfile 解析NetworkManager使用的INI格式keyfile
+func parseKeyfile(data []byte) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if sections[current] == nil {
+				sections[current] = make(map[string]string)
+			}
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) < 2 || current == "" {
+			continue
+		}
+		sections[current][strings.TrimSpace(parts[0])] = unescapeKeyfileValue(strings.TrimSpace(parts[1]))
+	}
+	return sections
+}
+
+// unescapeKeyfileValue 处理keyfile中的转义字符
+func unescapeKeyfileValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var result strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 >= len(value) {
+			result.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 's':
+			result.WriteByte(' ')
+		case 'n':
+			result.WriteByte('\n')
+		case 't':
+			result.WriteByte('\t')
+		case 'r':
+			result.WriteByte('\r')
+		default:
+			result.WriteByte(value[i])
+		}
+	}
+	return result.String()
+}
+
+// keyfileSection 按多个可能的名称查找keyfile中的节，如 wifi 与 802-11-wireless
+func keyfileSection(sections map[string]map[string]string, names ...string) map[string]string {
+	for _, name := range names {
+		if section, ok := sections[name]; ok {
+			return section
+		}
+	}
+	return map[string]string{}
+}
+
+// decodeKeyfileSSID 解析keyfile中的SSID，旧版本可能以 "72;111;109;101;" 形式保存字节
+func decodeKeyfileSSID(value string) string {
+	if !strings.Contains(value, ";") {
+		return value
+	}
+	var raw []byte
+	for _, part := range strings.Split(strings.TrimSuffix(value, ";"), ";") {
+		b, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || b < 0 || b > 255 {
+			return value
+		}
+		raw = append(raw, byte(b))
+	}
+	return string(raw)
+}
+
+// splitList 拆分以分号、逗号或空格分隔的列表
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == ',' || r == ' '
+	})
+}
+
+// ParseNetworkManagerKeyfile 解析NetworkManager的.nmconnection文件
+// 非WiFi连接返回false
+func ParseNetworkManagerKeyfile(data []byte) (SavedWiFi, bool) {
+	sections := parseKeyfile(data)
+	connection := keyfileSection(sections, "connection")
+	connType := connection["type"]
+	if connType != "wifi" && connType != "802-11-wireless" {
+		return SavedWiFi{}, false
+	}
+
+	wireless := keyfileSection(sections, "wifi", "802-11-wireless")
+	security := keyfileSection(sections, "wifi-security", "802-11-wireless-security")
+	_, hasSecurity := sections["wifi-security"]
+	if _, ok := sections["802-11-wireless-security"]; ok {
+		hasSecurity = true
+	}
+
+	network := SavedWiFi{
+		SSID:           decodeKeyfileSSID(wireless["ssid"]),
+		ProfileName:    connection["id"],
+		NetworkType:    wireless["mode"],
+		ConnectionMode: ConnectionAuto,
+		NonBroadcast:   strings.EqualFold(wireless["hidden"], "true"),
+		Scope:          ScopeAllUsers,
+	}
+	if network.NetworkType == "" {
+		network.NetworkType = "infrastructure"
+	}
+	if strings.EqualFold(connection["autoconnect"], "false") {
+		network.ConnectionMode = ConnectionManual
+	}
+	// permissions为空表示所有用户可用，否则只对指定用户可用
+	if connection["permissions"] != "" {
+		network.Scope = ScopeCurrentUser
+	}
+	if mac := wireless["cloned-mac-address"]; mac != "" {
+		network.MACRandomization = mac
+	} else if mac := wireless["mac-address-randomization"]; mac != "" {
+		network.MACRandomization = mac
+	}
+
+	// 身份验证与密钥
+	wepKey := security["wep-key0"]
+	keyMgmt := security["key-mgmt"]
+	if !hasSecurity {
+		keyMgmt = "none"
+	}
+	network.Authentication = authenticationFromKeyMgmt([]string{keyMgmt}, wepKey != "")
+	network.Cipher = strings.ToUpper(strings.Join(splitList(security["pairwise"]), "/"))
+
+	switch {
+	case isEAPKeyMgmt(network.Authentication):
+		network.KeyStatus = KeyEnterprise
+		network.Enterprise = parseKeyfile8021X(keyfileSection(sections, "802-1x"))
+	case security["psk"] != "":
+		network.KeyStatus = KeyPresent
+		network.Password = security["psk"]
+		network.KeyType = guessKeyType(network.Password)
+	case wepKey != "":
+		network.KeyStatus = KeyPresent
+		network.Password = wepKey
+		network.KeyType = "networkKey"
+	case security["psk-flags"] != "" && security["psk-flags"] != "0":
+		// 密钥由用户密钥环保存，不在配置文件中
+		network.KeyStatus = KeyHidden
+	case strings.EqualFold(keyMgmt, "none") || strings.EqualFold(keyMgmt, "owe"):
+		network.KeyStatus = KeyAbsent
+	default:
+		network.KeyStatus = KeyUnknown
+	}
+
+	return network, true
+}
+
+// parseKeyfile8021X 解析keyfile中的802-1x节
+func parseKeyfile8021X(section map[string]string) *EnterpriseConfig {
+	config := &EnterpriseConfig{ValidationKnown: true}
+	if methods := splitList(section["eap"]); len(methods) > 0 {
+		config.EAPMethod = eapMethodDisplay(methods[0])
+	}
+	if inner := section["phase2-auth"]; inner != "" {
+		config.InnerMethod = eapMethodDisplay(inner)
+	} else if inner := section["phase2-autheap"]; inner != "" {
+		config.InnerMethod = eapMethodDisplay(inner)
+	}
+
+	// 未指定CA证书时NetworkManager不验证服务器证书
+	if ca := section["ca-cert"]; ca != "" {
+		config.TrustedRootCAs = append(config.TrustedRootCAs, ca)
+	}
+	config.ServerValidation = len(config.TrustedRootCAs) > 0 || strings.EqualFold(section["system-ca-certs"], "true")
+	for _, key := range []string{"domain-suffix-match", "domain-match", "subject-match", "altsubject-matches"} {
+		if value := section[key]; value != "" {
+			config.ServerNames = value
+			break
+		}
+	}
+	if section["client-cert"] != "" {
+		config.CredentialSource = "certificate"
+	} else if section["identity"] != "" {
+		config.CredentialSource = "user"
+	}
+	return config
+}
+
+// LoadNetworkManagerProfiles 从目录加载NetworkManager的WiFi连接配置
+func LoadNetworkManagerProfiles(dir string) ([]SavedWiFi, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("读取目录失败: %v", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %v", err)
+	}
+	sort.Strings(files)
+
+	var networks []SavedWiFi
+	for _, file := range files {
+		if info, err := os.Stat(file); err != nil || info.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			warnf("读取 %s 失败: %v", file, err)
+			continue
+		}
+		if network, ok := ParseNetworkManagerKeyfile(data); ok {
+			networks = append(networks, network)
+		}
+	}
+	return networks, nil
+}
+
+// ParseWpaSupplicantConf 解析wpa_supplicant.conf中的network块
+func ParseWpaSupplicantConf(data []byte) ([]SavedWiFi, error) {
+	var networks []SavedWiFi
+	var block map[string]string
+	lineNumber := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case block == nil && strings.HasPrefix(line, "network=") && strings.HasSuffix(line, "{"):
+			block = make(map[string]string)
+		case block != nil && line == "}":
+			networks = append(networks, wpaBlockToSavedWiFi(block))
+			block = nil
+		case block != nil:
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) == 2 {
+				block[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if block != nil {
+		return networks, fmt.Errorf("第 %d 行: network块未结束", lineNumber)
+	}
+	return networks, nil
+}
+
+// parseWpaString 解析wpa_supplicant中的字符串值
+// 支持 "文本"、P"转义文本" 以及不带引号的十六进制形式，返回值和是否为十六进制
+func parseWpaString(value string) (string, bool) {
+	switch {
+	case strings.HasPrefix(value, `P"`) && strings.HasSuffix(value, `"`) && len(value) >= 3:
+		if unquoted, err := strconv.Unquote(value[1:]); err == nil {
+			return unquoted, false
+		}
+		return value[2 : len(value)-1], false
+	case strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) && len(value) >= 2:
+		return value[1 : len(value)-1], false
+	}
+	return value, true
+}
+
+// wpaBlockToSavedWiFi 将wpa_supplicant的network块转换为SavedWiFi记录
+func wpaBlockToSavedWiFi(block map[string]string) SavedWiFi {
+	network := SavedWiFi{
+		ConnectionMode: ConnectionAuto,
+		NonBroadcast:   block["scan_ssid"] == "1",
+		NetworkType:    "infrastructure",
+		Scope:          ScopeAllUsers,
+	}
+
+	ssid, isHex := parseWpaString(block["ssid"])
+	if isHex {
+		if decoded, err := hex.DecodeString(ssid); err == nil {
+			ssid = string(decoded)
+		}
+	}
+	network.SSID = ssid
+	network.ProfileName = strings.Trim(block["id_str"], `"`)
+	if block["disabled"] == "1" {
+		network.ConnectionMode = ConnectionManual
+	}
+	switch block["mode"] {
+	case "1":
+		network.NetworkType = "adhoc"
+	case "2":
+		network.NetworkType = "ap"
+	}
+	if mac := block["mac_addr"]; mac != "" && mac != "0" {
+		network.MACRandomization = "random"
+	}
+
+	// key_mgmt默认为 WPA-PSK WPA-EAP
+	keyMgmt := block["key_mgmt"]
+	if keyMgmt == "" {
+		keyMgmt = "WPA-PSK WPA-EAP"
+	}
+	wepKey := block["wep_key0"]
+	network.Authentication = authenticationFromKeyMgmt(strings.Fields(keyMgmt), wepKey != "")
+	network.Cipher = strings.Join(strings.Fields(block["pairwise"]), "/")
+
+	psk := block["psk"]
+	if psk == "" {
+		psk = block["sae_password"]
+	}
+	switch {
+	case psk != "":
+		key, isHex := parseWpaString(psk)
+		network.KeyStatus = KeyPresent
+		network.Password = key
+		network.KeyType = "passPhrase"
+		if isHex {
+			network.KeyType = "networkKey"
+		}
+	case wepKey != "":
+		key, _ := parseWpaString(wepKey)
+		network.KeyStatus = KeyPresent
+		network.Password = key
+		network.KeyType = "networkKey"
+	case isEAPKeyMgmt(network.Authentication) && (block["eap"] != "" || block["identity"] != ""):
+		network.KeyStatus = KeyEnterprise
+		network.Enterprise = parseWpaEnterprise(block)
+	case strings.EqualFold(keyMgmt, "NONE") || strings.EqualFold(keyMgmt, "OWE"):
+		network.KeyStatus = KeyAbsent
+	default:
+		network.KeyStatus = KeyUnknown
+	}
+
+	return network
+}
+
+// parseWpaEnterprise 解析wpa_supplicant network块中的802.1X设置
+func parseWpaEnterprise(block map[string]string) *EnterpriseConfig {
+	config := &EnterpriseConfig{ValidationKnown: true}
+	if methods := strings.Fields(block["eap"]); len(methods) > 0 {
+		config.EAPMethod = eapMethodDisplay(methods[0])
+	}
+	// phase2 形如 "auth=MSCHAPV2" 或 "autheap=MSCHAPV2"
+	phase2, _ := parseWpaString(block["phase2"])
+	for _, part := range strings.Fields(phase2) {
+		if kv := strings.SplitN(part, "=", 2); len(kv) == 2 {
+			config.InnerMethod = eapMethodDisplay(kv[1])
+			break
+		}
+	}
+
+	if ca, _ := parseWpaString(block["ca_cert"]); ca != "" {
+		config.TrustedRootCAs = append(config.TrustedRootCAs, ca)
+	} else if ca, _ := parseWpaString(block["ca_path"]); ca != "" {
+		config.TrustedRootCAs = append(config.TrustedRootCAs, ca)
+	}
+	config.ServerValidation = len(config.TrustedRootCAs) > 0
+	for _, key := range []string{"domain_suffix_match", "domain_match", "subject_match", "altsubject_match"} {
+		if value, _ := parseWpaString(block[key]); value != "" {
+			config.ServerNames = value
+			break
+		}
+	}
+	if block["client_cert"] != "" {
+		config.CredentialSource = "certificate"
+	} else if block["identity"] != "" {
+		config.CredentialSource = "user"
+	}
+	return config
+}
+
+// LoadWpaSupplicantConf 从文件加载wpa_supplicant的已保存网络
+func LoadWpaSupplicantConf(path string) ([]SavedWiFi, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	networks, err := ParseWpaSupplicantConf(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %v", path, err)
+	}
+	return networks, nil
+}
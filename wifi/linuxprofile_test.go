@@ -0,0 +1,193 @@
+package wifi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 64位十六进制的原始网络密钥
+const rawNetworkKey = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParseNetworkManagerKeyfile(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		want   SavedWiFi
+		isWiFi bool
+	}{
+		{"WPA2个人", `
+[connection]
+id=Home WiFi
+uuid=3b1b7b6e-8a0c-4c1e-9d55-0c1c3a0a9e11
+type=wifi
+interface-name=wlp2s0
+permissions=
+
+[wifi]
+mac-address-randomization=1
+mode=infrastructure
+ssid=Home WiFi
+
+[wifi-security]
+auth-alg=open
+key-mgmt=wpa-psk
+psk=correct horse;battery\s
+
+[ipv4]
+method=auto
+`, SavedWiFi{
+			SSID: "Home WiFi", Password: "correct horse;battery ", KeyStatus: KeyPresent, ProfileName: "Home WiFi",
+			Scope: ScopeAllUsers, Authentication: "WPA-PSK", KeyType: "passPhrase", ConnectionMode: ConnectionAuto,
+			MACRandomization: "1", NetworkType: "infrastructure",
+		}, true},
+		{"802.1X旧格式", `
+[connection]
+id=eduroam
+type=802-11-wireless
+autoconnect=false
+permissions=user:alice;
+
+[802-11-wireless]
+ssid=101;100;117;114;111;97;109;
+hidden=true
+
+[802-11-wireless-security]
+key-mgmt=wpa-eap
+pairwise=ccmp;
+
+[802-1x]
+eap=peap;
+identity=alice@example.edu
+phase2-auth=mschapv2
+domain-suffix-match=example.edu
+`, SavedWiFi{
+			SSID: "eduroam", KeyStatus: KeyEnterprise, ProfileName: "eduroam", Scope: ScopeCurrentUser,
+			Authentication: "WPA-EAP", Cipher: "CCMP", ConnectionMode: ConnectionManual, NonBroadcast: true,
+			NetworkType: "infrastructure",
+			Enterprise: &EnterpriseConfig{
+				EAPMethod: "PEAP", InnerMethod: "EAP-MSCHAPv2", ValidationKnown: true,
+				ServerNames: "example.edu", CredentialSource: "user",
+			},
+		}, true},
+		{"开放网络", `
+[connection]
+id=Airport
+type=wifi
+
+[wifi]
+ssid=Airport
+`, SavedWiFi{
+			SSID: "Airport", KeyStatus: KeyAbsent, ProfileName: "Airport", Scope: ScopeAllUsers,
+			Authentication: "Open", ConnectionMode: ConnectionAuto, NetworkType: "infrastructure",
+		}, true},
+		{"密钥保存在密钥环", `
+[connection]
+id=Cafe
+type=wifi
+
+[wifi]
+ssid=Cafe
+
+[wifi-security]
+key-mgmt=sae
+psk-flags=1
+`, SavedWiFi{
+			SSID: "Cafe", KeyStatus: KeyHidden, ProfileName: "Cafe", Scope: ScopeAllUsers,
+			Authentication: "WPA3-SAE", ConnectionMode: ConnectionAuto, NetworkType: "infrastructure",
+		}, true},
+		{"有线连接", `
+[connection]
+id=Wired
+type=ethernet
+`, SavedWiFi{}, false},
+	}
+	for _, test := range tests {
+		got, isWiFi := ParseNetworkManagerKeyfile([]byte(test.data))
+		if isWiFi != test.isWiFi || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: 解析结果 %+v (%v)，期望 %+v (%v)", test.name, got, isWiFi, test.want, test.isWiFi)
+		}
+	}
+}
+
+// wpaSupplicantSample 为树莓派等设备上常见的wpa_supplicant.conf
+const wpaSupplicantSample = `ctrl_interface=DIR=/var/run/wpa_supplicant GROUP=netdev
+update_config=1
+country=CN
+
+network={
+	ssid="HomeNet"
+	psk="secret # passphrase"
+	key_mgmt=WPA-PSK
+	id_str="home"
+}
+
+# 十六进制SSID和原始密钥
+network={
+	ssid=e58a9ee585ace5aea4
+	psk=` + rawNetworkKey + `
+	scan_ssid=1
+	disabled=1
+}
+
+network={
+	ssid="eduroam"
+	key_mgmt=WPA-EAP
+	eap=PEAP
+	identity="alice@example.edu"
+	password="hunter2"
+	ca_cert="/etc/ssl/certs/campus-ca.pem"
+	phase2="auth=MSCHAPV2"
+	domain_suffix_match="radius.example.edu"
+}
+
+network={
+	ssid="Guest"
+	key_mgmt=NONE
+}
+
+network={
+	ssid="OldPrinter"
+	key_mgmt=NONE
+	wep_key0="abcde"
+}
+`
+
+func TestParseWpaSupplicantConf(t *testing.T) {
+	want := []SavedWiFi{
+		{SSID: "HomeNet", Password: "secret # passphrase", KeyStatus: KeyPresent, ProfileName: "home", Scope: ScopeAllUsers,
+			Authentication: "WPA-PSK", KeyType: "passPhrase", ConnectionMode: ConnectionAuto, NetworkType: "infrastructure"},
+		{SSID: "办公室", Password: rawNetworkKey, KeyStatus: KeyPresent, Scope: ScopeAllUsers, Authentication: "WPA-PSK/WPA-EAP",
+			KeyType: "networkKey", ConnectionMode: ConnectionManual, NonBroadcast: true, NetworkType: "infrastructure"},
+		{SSID: "eduroam", KeyStatus: KeyEnterprise, Scope: ScopeAllUsers, Authentication: "WPA-EAP",
+			ConnectionMode: ConnectionAuto, NetworkType: "infrastructure",
+			Enterprise: &EnterpriseConfig{
+				EAPMethod: "PEAP", InnerMethod: "EAP-MSCHAPv2", ServerValidation: true, ValidationKnown: true,
+				ServerNames: "radius.example.edu", TrustedRootCAs: []string{"/etc/ssl/certs/campus-ca.pem"}, CredentialSource: "user",
+			}},
+		{SSID: "Guest", KeyStatus: KeyAbsent, Scope: ScopeAllUsers, Authentication: "Open",
+			ConnectionMode: ConnectionAuto, NetworkType: "infrastructure"},
+		{SSID: "OldPrinter", Password: "abcde", KeyStatus: KeyPresent, Scope: ScopeAllUsers, Authentication: "WEP",
+			KeyType: "networkKey", ConnectionMode: ConnectionAuto, NetworkType: "infrastructure"},
+	}
+
+	got, err := ParseWpaSupplicantConf([]byte(wpaSupplicantSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("解析到 %d 个网络，期望 %d 个", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("网络 #%d = %+v，期望 %+v", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestParseWpaSupplicantConfUnterminated(t *testing.T) {
+	_, err := ParseWpaSupplicantConf([]byte("network={\n\tssid=\"HomeNet\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "network块未结束") {
+		t.Errorf("未结束的network块应返回错误，实际为 %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package wifi
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// OpenWrt无线配置的默认位置
+const DefaultOpenWrtWireless = "/etc/config/wireless"
+
+// openwrtEncryptions OpenWrt encryption选项的基本类型对应的身份验证名称
+var openwrtEncryptions = map[string]string{
+	"none":       "Open",
+	"owe":        "OWE",
+	"wep":        "WEP",
+	"wep-open":   "WEP",
+	"wep-shared": "WEP",
+	"psk":        "WPA-PSK",
+	"psk2":       "WPA2-PSK",
+	"psk-mixed":  "WPA-PSK/WPA2-PSK",
+	"sae":        "WPA3-SAE",
+	"sae-mixed":  "WPA2-PSK/WPA3-SAE",
+	"wpa":        "WPA-EAP",
+	"wpa2":       "WPA2-EAP",
+	"wpa3":       "WPA3-EAP",
+	"wpa-mixed":  "WPA-EAP/WPA2-EAP",
+	"wpa3-mixed": "WPA2-EAP/WPA3-EAP",
+	"wpa3-192":   "WPA3-EAP-192",
+}
+
+// UCISection 表示UCI配置文件中的一个config段
+type UCISection struct {
+	Type    string
+	Name    string
+	Options map[string]string
+	Lists   map[string][]string
+}
+
+// ParseUCI 解析OpenWrt的UCI配置文件
+func ParseUCI(data []byte) []*UCISection {
+	var sections []*UCISection
+	var current *UCISection
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := splitUCILine(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "config":
+			current = &UCISection{
+				Options: make(map[string]string),
+				Lists:   make(map[string][]string),
+			}
+			if len(fields) > 1 {
+				current.Type = fields[1]
+			}
+			if len(fields) > 2 {
+				current.Name = fields[2]
+			}
+			sections = append(sections, current)
+		case "option":
+			if current != nil && len(fields) > 2 {
+				current.Options[fields[1]] = fields[2]
+			}
+		case "list":
+			if current != nil && len(fields) > 2 {
+				current.Lists[fields[1]] = append(current.Lists[fields[1]], fields[2])
+			}
+		}
+	}
+	return sections
+}
+
+// splitUCILine 将UCI配置行拆分为字段，支持单引号、双引号和反斜杠转义，忽略注释
+func splitUCILine(line string) []string {
+	var fields []string
+	var field strings.Builder
+	inField := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				field.WriteRune(runes[i])
+			} else {
+				field.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inField = true
+		case r == '#':
+			if inField {
+				fields = append(fields, field.String())
+			}
+			return fields
+		case r == ' ' || r == '\t':
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		case r == '\\' && i+1 < len(runes):
+			i++
+			field.WriteRune(runes[i])
+			inField = true
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// parseOpenWrtEncryption 解析encryption选项，如 psk2+ccmp、psk-mixed+tkip+ccmp
+// 返回身份验证名称和加密方式
+func parseOpenWrtEncryption(value string) (string, string) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(value)), "+")
+	base := parts[0]
+	if base == "" {
+		base = "none"
+	}
+
+	authentication, ok := openwrtEncryptions[base]
+	if !ok {
+		authentication = strings.ToUpper(base)
+	}
+
+	var ciphers []string
+	for _, part := range parts[1:] {
+		switch part {
+		case "ccmp", "aes":
+			ciphers = appendUnique(ciphers, "CCMP")
+		case "tkip":
+			ciphers = appendUnique(ciphers, "TKIP")
+		case "gcmp":
+			ciphers = appendUnique(ciphers, "GCMP")
+		case "ccmp256":
+			ciphers = appendUnique(ciphers, "CCMP-256")
+		case "gcmp256":
+			ciphers = appendUnique(ciphers, "GCMP-256")
+		}
+	}
+	if strings.HasPrefix(base, "wep") {
+		ciphers = []string{"WEP"}
+	}
+	return authentication, strings.Join(ciphers, "/")
+}
+
+// uciSectionToSavedWiFi 将wifi-iface段转换为SavedWiFi记录
+// mode为ap时是路由器自身广播的网络，为sta时是路由器作为客户端保存的上游网络
+func uciSectionToSavedWiFi(section *UCISection) SavedWiFi {
+	options := section.Options
+	network := SavedWiFi{
+		SSID:           options["ssid"],
+		ProfileName:    section.Name,
+		ConnectionMode: ConnectionAuto,
+		NonBroadcast:   options["hidden"] == "1",
+		NetworkType:    options["mode"],
+		Scope:          ScopeAllUsers,
+	}
+	if network.NetworkType == "" {
+		network.NetworkType = "ap"
+	}
+	if network.ProfileName == "" {
+		network.ProfileName = options["device"]
+	}
+	if options["disabled"] == "1" {
+		network.ConnectionMode = ConnectionManual
+	}
+	if mac := options["macaddr"]; mac == "random" {
+		network.MACRandomization = "random"
+	}
+
+	network.Authentication, network.Cipher = parseOpenWrtEncryption(options["encryption"])
+	key := options["key"]
+
+	switch {
+	case isEAPKeyMgmt(network.Authentication):
+		network.KeyStatus = KeyEnterprise
+		network.Enterprise = parseOpenWrtEnterprise(section)
+	case network.Cipher == "WEP":
+		// WEP的key选项为密钥序号（1-4），实际密钥保存在key1..key4中
+		if len(key) == 1 && key >= "1" && key <= "4" {
+			key = options["key"+key]
+		}
+		key = strings.TrimPrefix(key, "s:")
+		if key == "" {
+			network.KeyStatus = KeyUnknown
+			break
+		}
+		network.KeyStatus = KeyPresent
+		network.Password = key
+		network.KeyType = "networkKey"
+	case key != "":
+		network.KeyStatus = KeyPresent
+		network.Password = key
+		network.KeyType = "passPhrase"
+		if len(key) == 64 && isHexString(key) {
+			network.KeyType = "networkKey"
+		}
+	case network.Authentication == "Open" || network.Authentication == "OWE":
+		network.KeyStatus = KeyAbsent
+	default:
+		network.KeyStatus = KeyUnknown
+	}
+
+	return network
+}
+
+// parseOpenWrtEnterprise 解析wifi-iface中的802.1X设置
+// 客户端模式使用eap_type/identity等选项，AP模式使用RADIUS服务器选项
+func parseOpenWrtEnterprise(section *UCISection) *EnterpriseConfig {
+	options := section.Options
+	config := &EnterpriseConfig{}
+
+	if options["mode"] == "sta" {
+		config.ValidationKnown = true
+		if method := options["eap_type"]; method != "" {
+			config.EAPMethod = eapMethodDisplay(method)
+		}
+		if inner := options["auth"]; inner != "" {
+			config.InnerMethod = eapMethodDisplay(strings.TrimPrefix(strings.ToLower(inner), "eap-"))
+		}
+		if ca := options["ca_cert"]; ca != "" {
+			config.TrustedRootCAs = append(config.TrustedRootCAs, ca)
+		}
+		config.ServerValidation = len(config.TrustedRootCAs) > 0
+		for _, key := range []string{"domain_suffix_match", "domain_match", "subject_match"} {
+			if value := options[key]; value != "" {
+				config.ServerNames = value
+				break
+			}
+		}
+		if options["client_cert"] != "" {
+			config.CredentialSource = "certificate"
+		} else if options["identity"] != "" {
+			config.CredentialSource = "user"
+		}
+		return config
+	}
+
+	server := options["auth_server"]
+	if server == "" {
+		server = options["server"]
+	}
+	if server != "" {
+		if port := options["auth_port"]; port != "" {
+			server += ":" + port
+		}
+		config.CredentialSource = "RADIUS " + server
+	}
+	return config
+}
+
+// ParseOpenWrtWireless 解析OpenWrt的/etc/config/wireless，返回所有wifi-iface段对应的网络
+func ParseOpenWrtWireless(data []byte) []SavedWiFi {
+	var networks []SavedWiFi
+	for _, section := range ParseUCI(data) {
+		if section.Type != "wifi-iface" {
+			continue
+		}
+		networks = append(networks, uciSectionToSavedWiFi(section))
+	}
+	return networks
+}
+
+// LoadOpenWrtWireless 从文件加载OpenWrt无线配置
+func LoadOpenWrtWireless(path string) ([]SavedWiFi, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	return ParseOpenWrtWireless(data), nil
+}
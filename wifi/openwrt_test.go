@@ -0,0 +1,109 @@
+package wifi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// openwrtWirelessSample 为OpenWrt的/etc/config/wireless
+const openwrtWirelessSample = `
+config wifi-device 'radio0'
+	option type 'mac80211'
+	option channel '36'
+	option band '5g'
+
+config wifi-iface 'default_radio0'
+	option device 'radio0'
+	option network 'lan'
+	option mode 'ap'
+	option ssid 'OpenWrt "Lab"'
+	option encryption 'psk2+ccmp'
+	option key 'it'\''s a secret'
+
+config wifi-iface 'wwan'
+	option device 'radio1'
+	option network 'wwan'
+	option mode 'sta'
+	option ssid 'eduroam'
+	option encryption 'wpa2+ccmp'
+	option eap_type 'peap'
+	option auth 'EAP-MSCHAPV2'
+	option identity 'alice'
+	option password 'hunter2'
+	option ca_cert '/etc/ssl/certs/campus-ca.pem'
+	option domain_suffix_match 'radius.example.edu'
+	option disabled '1'
+
+config wifi-iface
+	option device 'radio0'
+	option mode 'ap'
+	option ssid 'Legacy'
+	option encryption 'wep-open'
+	option key '1'
+	option key1 's:abcde'
+	option hidden '1'
+
+config wifi-iface 'guest'
+	option device 'radio0'
+	option mode 'ap'
+	option ssid Guest
+	option encryption 'none'  # 访客网络
+	option macaddr 'random'
+
+config wifi-iface 'corp'
+	option device 'radio1'
+	option mode 'ap'
+	option ssid 'Corp'
+	option encryption 'wpa2+ccmp'
+	option auth_server '10.0.0.2'
+	option auth_port '1812'
+	option auth_secret 'radius-secret'
+`
+
+func TestParseOpenWrtWireless(t *testing.T) {
+	want := []SavedWiFi{
+		{SSID: `OpenWrt "Lab"`, Password: "it's a secret", KeyStatus: KeyPresent, ProfileName: "default_radio0", Scope: ScopeAllUsers,
+			Authentication: "WPA2-PSK", Cipher: "CCMP", KeyType: "passPhrase", ConnectionMode: ConnectionAuto, NetworkType: "ap"},
+		{SSID: "eduroam", KeyStatus: KeyEnterprise, ProfileName: "wwan", Scope: ScopeAllUsers, Authentication: "WPA2-EAP",
+			Cipher: "CCMP", ConnectionMode: ConnectionManual, NetworkType: "sta",
+			Enterprise: &EnterpriseConfig{
+				EAPMethod: "PEAP", InnerMethod: "EAP-MSCHAPv2", ServerValidation: true, ValidationKnown: true,
+				ServerNames: "radius.example.edu", TrustedRootCAs: []string{"/etc/ssl/certs/campus-ca.pem"}, CredentialSource: "user",
+			}},
+		{SSID: "Legacy", Password: "abcde", KeyStatus: KeyPresent, ProfileName: "radio0", Scope: ScopeAllUsers,
+			Authentication: "WEP", Cipher: "WEP", KeyType: "networkKey", ConnectionMode: ConnectionAuto, NonBroadcast: true, NetworkType: "ap"},
+		{SSID: "Guest", KeyStatus: KeyAbsent, ProfileName: "guest", Scope: ScopeAllUsers, Authentication: "Open",
+			ConnectionMode: ConnectionAuto, MACRandomization: "random", NetworkType: "ap"},
+		{SSID: "Corp", KeyStatus: KeyEnterprise, ProfileName: "corp", Scope: ScopeAllUsers, Authentication: "WPA2-EAP",
+			Cipher: "CCMP", ConnectionMode: ConnectionAuto, NetworkType: "ap",
+			Enterprise: &EnterpriseConfig{CredentialSource: "RADIUS 10.0.0.2:1812"}},
+	}
+
+	got := ParseOpenWrtWireless([]byte(openwrtWirelessSample))
+	if len(got) != len(want) {
+		t.Fatalf("解析到 %d 个网络，期望 %d 个", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("网络 #%d = %+v，期望 %+v", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestSplitUCILine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`	option ssid 'My WiFi'`, []string{"option", "ssid", "My WiFi"}},
+		{`option key "a\"b"`, []string{"option", "key", `a"b`}},
+		{`option key 'it'\''s'`, []string{"option", "key", "it's"}},
+		{`option ssid 'a#b' # 注释`, []string{"option", "ssid", "a#b"}},
+		{`# config wifi-iface`, nil},
+	}
+	for _, test := range tests {
+		if got := splitUCILine(test.line); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("splitUCILine(%q) = %q，期望 %q", test.line, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package wifi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 结果的输出格式，text为原有的中文文本
+const (
+	OutputText     = "text"
+	OutputJSON     = "json"
+	OutputCSV      = "csv"
+	OutputYAML     = "yaml"
+	OutputMarkdown = "markdown"
+	OutputHTML     = "html" // 带图表的自包含HTML报告，由结果文件的内容生成
+)
+
+// OutputFormats 支持的输出格式
+var OutputFormats = []string{OutputText, OutputJSON, OutputCSV, OutputYAML, OutputMarkdown, OutputHTML}
+
+// 结构化输出的架构名称，同一版本内只会新增字段，删除或修改字段时提升版本号
+const (
+	ScanSchema  = "wifisos.scan/v1"
+	SavedSchema = "wifisos.saved/v1"
+	BruteSchema = "wifisos.brute/v1"
+)
+
+// outputField 表示记录中的一个字段，CSV使用Key作为列名，Markdown使用Label作为表头
+type outputField struct {
+	Key   string
+	Label string
+	Value string
+}
+
+// outputRecord 可以按字段输出为CSV和Markdown表格的记录
+type outputRecord interface {
+	outputFields() []outputField
+}
+
+// outputDocument JSON和YAML输出的顶层对象
+type outputDocument struct {
+	Schema    string         `json:"schema" yaml:"schema"`
+	Generated string         `json:"generated" yaml:"generated"` // RFC 3339格式的生成时间
+	Items     []outputRecord `json:"items" yaml:"items"`
+}
+
+// ScanRecord 扫描结果中的一个BSSID，架构为wifisos.scan/v1
+type ScanRecord struct {
+	SSID               string `json:"ssid" yaml:"ssid"`
+	BSSID              string `json:"bssid" yaml:"bssid"`
+	SignalPercent      *int   `json:"signal_percent" yaml:"signal_percent"` // 无法解析时为null
+	Security           string `json:"security" yaml:"security"`
+	Band               string `json:"band" yaml:"band"`
+	Channel            int    `json:"channel" yaml:"channel"`
+	CenterChannel      int    `json:"center_channel" yaml:"center_channel"`
+	FrequencyMHz       int    `json:"frequency_mhz" yaml:"frequency_mhz"`
+	CenterFrequencyMHz int    `json:"center_frequency_mhz" yaml:"center_frequency_mhz"`
+	ChannelWidthMHz    int    `json:"channel_width_mhz" yaml:"channel_width_mhz"` // 0表示未知
+	DFS                bool   `json:"dfs" yaml:"dfs"`
+	Class              string `json:"class" yaml:"class"`
+}
+
+// NewScanRecord 将扫描到的网络转换为结构化记录
+func NewScanRecord(network WiFiNetwork) ScanRecord {
+	info := network.ChannelInfo()
+	record := ScanRecord{
+		SSID:               network.SSID,
+		BSSID:              network.BSSID,
+		Security:           network.Security,
+		Band:               info.Band,
+		Channel:            info.Primary,
+		CenterChannel:      info.Center,
+		FrequencyMHz:       info.Frequency,
+		CenterFrequencyMHz: info.CenterFrequency,
+		ChannelWidthMHz:    info.Width,
+		DFS:                info.DFS,
+		Class:              network.Class,
+	}
+	if signal, ok := network.SignalValue(); ok {
+		record.SignalPercent = &signal
+	}
+	return record
+}
+
+func (r ScanRecord) outputFields() []outputField {
+	signal := ""
+	if r.SignalPercent != nil {
+		signal = strconv.Itoa(*r.SignalPercent)
+	}
+	return []outputField{
+		{"ssid", "SSID", r.SSID},
+		{"bssid", "BSSID", r.BSSID},
+		{"signal_percent", "信号强度(%)", signal},
+		{"security", "安全类型", r.Security},
+		{"band", "频段", r.Band},
+		{"channel", "信道", formatOutputInt(r.Channel)},
+		{"center_channel", "中心信道", formatOutputInt(r.CenterChannel)},
+		{"frequency_mhz", "频率(MHz)", formatOutputInt(r.FrequencyMHz)},
+		{"center_frequency_mhz", "中心频率(MHz)", formatOutputInt(r.CenterFrequencyMHz)},
+		{"channel_width_mhz", "信道宽度(MHz)", formatOutputInt(r.ChannelWidthMHz)},
+		{"dfs", "DFS", strconv.FormatBool(r.DFS)},
+		{"class", "分类", r.Class},
+	}
+}
+
+// EnterpriseRecord 802.1X企业认证配置
+type EnterpriseRecord struct {
+	EAPMethod        string   `json:"eap_method" yaml:"eap_method"`
+	InnerMethod      string   `json:"inner_method" yaml:"inner_method"`
+	ServerValidation *bool    `json:"server_validation" yaml:"server_validation"` // 未获取到设置时为null
+	PromptDisabled   bool     `json:"prompt_disabled" yaml:"prompt_disabled"`
+	ServerNames      string   `json:"server_names" yaml:"server_names"`
+	TrustedRootCAs   []string `json:"trusted_root_cas" yaml:"trusted_root_cas"`
+	CredentialSource string   `json:"credential_source" yaml:"credential_source"`
+	Warnings         []string `json:"warnings" yaml:"warnings"`
+}
+
+// SavedRecord 一个已保存的网络，架构为wifisos.saved/v1
+// 密钥原文只在reveal时输出，否则只输出掩码
+type SavedRecord struct {
+	ProfileName      string            `json:"profile_name" yaml:"profile_name"`
+	SSID             string            `json:"ssid" yaml:"ssid"`
+	Authentication   string            `json:"authentication" yaml:"authentication"`
+	AuthType         string            `json:"auth_type" yaml:"auth_type"` // 归一化的身份验证类型，无法识别时为空
+	Cipher           string            `json:"cipher" yaml:"cipher"`
+	KeyType          string            `json:"key_type" yaml:"key_type"`
+	KeyStatus        string            `json:"key_status" yaml:"key_status"` // present、absent、hidden、error、enterprise或unknown
+	KeyError         string            `json:"key_error,omitempty" yaml:"key_error,omitempty"`
+	Password         string            `json:"password,omitempty" yaml:"password,omitempty"`
+	PasswordMask     string            `json:"password_mask,omitempty" yaml:"password_mask,omitempty"`
+	ConnectionMode   string            `json:"connection_mode" yaml:"connection_mode"`
+	AutoSwitch       bool              `json:"auto_switch" yaml:"auto_switch"`
+	NonBroadcast     bool              `json:"non_broadcast" yaml:"non_broadcast"`
+	MACRandomization string            `json:"mac_randomization" yaml:"mac_randomization"`
+	Cost             string            `json:"cost" yaml:"cost"`
+	NetworkType      string            `json:"network_type" yaml:"network_type"`
+	RadioType        string            `json:"radio_type" yaml:"radio_type"`
+	Scope            string            `json:"scope" yaml:"scope"`
+	Enterprise       *EnterpriseRecord `json:"enterprise,omitempty" yaml:"enterprise,omitempty"`
+}
+
+// NewSavedRecord 将已保存的网络转换为结构化记录
+func NewSavedRecord(network SavedWiFi, reveal bool) SavedRecord {
+	record := SavedRecord{
+		ProfileName:      network.ProfileName,
+		SSID:             network.SSID,
+		Authentication:   network.Authentication,
+		Cipher:           network.Cipher,
+		KeyType:          network.KeyType,
+		KeyStatus:        keyStatusNames[network.KeyStatus],
+		KeyError:         network.KeyError,
+		ConnectionMode:   network.ConnectionMode,
+		AutoSwitch:       network.AutoSwitch,
+		NonBroadcast:     network.NonBroadcast,
+		MACRandomization: network.MACRandomization,
+		Cost:             network.Cost,
+		NetworkType:      network.NetworkType,
+		RadioType:        network.RadioType,
+		Scope:            network.Scope,
+	}
+	if record.ProfileName == "" {
+		record.ProfileName = network.SSID
+	}
+	if auth := network.AuthType(); auth != AuthUnknown {
+		record.AuthType = auth.String()
+	}
+	if network.HasPassword() {
+		record.PasswordMask = MaskSecret(network.Password)
+		if reveal {
+			record.Password = network.Password
+		}
+	}
+	if e := network.Enterprise; e != nil {
+		record.Enterprise = &EnterpriseRecord{
+			EAPMethod:        e.EAPMethod,
+			InnerMethod:      e.InnerMethod,
+			PromptDisabled:   e.PromptDisabled,
+			ServerNames:      e.ServerNames,
+			TrustedRootCAs:   append([]string{}, e.TrustedRootCAs...),
+			CredentialSource: e.CredentialSource,
+			Warnings:         append([]string{}, e.Warnings()...),
+		}
+		if e.ValidationKnown {
+			validation := e.ServerValidation
+			record.Enterprise.ServerValidation = &validation
+		}
+	}
+	return record
+}
+
+func (r SavedRecord) outputFields() []outputField {
+	enterprise := r.Enterprise
+	if enterprise == nil {
+		enterprise = &EnterpriseRecord{}
+	}
+	validation := ""
+	if enterprise.ServerValidation != nil {
+		validation = strconv.FormatBool(*enterprise.ServerValidation)
+	}
+	prompt := ""
+	if r.Enterprise != nil {
+		prompt = strconv.FormatBool(enterprise.PromptDisabled)
+	}
+	return []outputField{
+		{"profile_name", "配置文件", r.ProfileName},
+		{"ssid", "SSID", r.SSID},
+		{"authentication", "身份验证", r.Authentication},
+		{"auth_type", "身份验证类型", r.AuthType},
+		{"cipher", "加密方式", r.Cipher},
+		{"key_type", "密钥类型", r.KeyType},
+		{"key_status", "密钥状态", r.KeyStatus},
+		{"key_error", "获取失败原因", r.KeyError},
+		{"password", "密码", r.Password},
+		{"password_mask", "密码掩码", r.PasswordMask},
+		{"connection_mode", "连接模式", r.ConnectionMode},
+		{"auto_switch", "自动切换", strconv.FormatBool(r.AutoSwitch)},
+		{"non_broadcast", "隐藏网络", strconv.FormatBool(r.NonBroadcast)},
+		{"mac_randomization", "MAC随机化", r.MACRandomization},
+		{"cost", "费用设置", r.Cost},
+		{"network_type", "网络类型", r.NetworkType},
+		{"radio_type", "无线电类型", r.RadioType},
+		{"scope", "作用范围", r.Scope},
+		{"eap_method", "EAP方法", enterprise.EAPMethod},
+		{"inner_method", "内层认证", enterprise.InnerMethod},
+		{"server_validation", "验证服务器证书", validation},
+		{"prompt_disabled", "禁止提示信任新服务器", prompt},
+		{"server_names", "服务器名称", enterprise.ServerNames},
+		{"trusted_root_cas", "受信任的根CA", strings.Join(enterprise.TrustedRootCAs, ";")},
+		{"credential_source", "凭据来源", enterprise.CredentialSource},
+		{"warnings", "警告", strings.Join(enterprise.Warnings, ";")},
+	}
+}
+
+// BruteRecord 一次爆破的结果，架构为wifisos.brute/v1
+// 成功的密码和尝试过的密码只在reveal时输出原文，否则输出掩码
+type BruteRecord struct {
+	SSID           string   `json:"ssid" yaml:"ssid"`
+	Success        bool     `json:"success" yaml:"success"`
+	TestedCount    int      `json:"tested_count" yaml:"tested_count"`
+	ElapsedSeconds float64  `json:"elapsed_seconds" yaml:"elapsed_seconds"`
+	Password       string   `json:"password,omitempty" yaml:"password,omitempty"`
+	PasswordMask   string   `json:"password_mask,omitempty" yaml:"password_mask,omitempty"`
+	FailedAttempts []string `json:"failed_attempts" yaml:"failed_attempts"`
+}
+
+// NewBruteRecord 将爆破结果转换为结构化记录
+func NewBruteRecord(result *BruteForceResult, reveal bool) BruteRecord {
+	record := BruteRecord{
+		SSID:           result.SSID,
+		Success:        result.Success,
+		TestedCount:    result.TestedCount,
+		ElapsedSeconds: result.ElapsedTime.Seconds(),
+		FailedAttempts: []string{},
+	}
+	if result.Success {
+		record.PasswordMask = MaskSecret(result.Password)
+		if reveal {
+			record.Password = result.Password
+		}
+	}
+	for _, password := range result.FailedAttempts {
+		record.FailedAttempts = append(record.FailedAttempts, ShowSecret(password, reveal))
+	}
+	return record
+}
+
+func (r BruteRecord) outputFields() []outputField {
+	return []outputField{
+		{"ssid", "SSID", r.SSID},
+		{"success", "成功", strconv.FormatBool(r.Success)},
+		{"tested_count", "尝试次数", strconv.Itoa(r.TestedCount)},
+		{"elapsed_seconds", "耗时(秒)", strconv.FormatFloat(r.ElapsedSeconds, 'f', 3, 64)},
+		{"password", "密码", r.Password},
+		{"password_mask", "密码掩码", r.PasswordMask},
+		{"failed_attempts", "尝试过的密码", strings.Join(r.FailedAttempts, ";")},
+	}
+}
+
+// formatOutputInt 返回整数的文本，0表示未知时输出为空
+func formatOutputInt(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+// renderOutput 按格式输出记录，title用于Markdown标题，prototype为该架构的空记录，用于在没有记录时输出CSV表头
+func renderOutput(format string, schema string, title string, prototype outputRecord, records []outputRecord) (string, error) {
+	if records == nil {
+		records = []outputRecord{}
+	}
+	now := time.Now()
+	document := outputDocument{Schema: schema, Generated: now.Format(time.RFC3339), Items: records}
+
+	switch format {
+	case OutputJSON:
+		data, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("生成JSON失败: %v", err)
+		}
+		return string(data) + "\n", nil
+	case OutputYAML:
+		var buffer bytes.Buffer
+		encoder := yaml.NewEncoder(&buffer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return "", fmt.Errorf("生成YAML失败: %v", err)
+		}
+		encoder.Close()
+		return buffer.String(), nil
+	case OutputCSV:
+		return renderOutputCSV(prototype, records)
+	case OutputMarkdown:
+		return renderOutputMarkdown(schema, fmt.Sprintf("%s - %s", title, now.Format("2006-01-02 15:04:05")), records), nil
+	}
+	return "", fmt.Errorf("不支持的输出格式: %s", format)
+}
+
+// renderOutputCSV 输出CSV，第一行为字段名，列的顺序与JSON字段一致，列表字段用分号连接
+func renderOutputCSV(prototype outputRecord, records []outputRecord) (string, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	var header []string
+	for _, field := range prototype.outputFields() {
+		header = append(header, field.Key)
+	}
+	if err := writer.Write(header); err != nil {
+		return "", fmt.Errorf("生成CSV失败: %v", err)
+	}
+	for _, record := range records {
+		fields := record.outputFields()
+		row := make([]string, len(fields))
+		for j, field := range fields {
+			row[j] = field.Value
+		}
+		if err := writer.Write(row); err != nil {
+			return "", fmt.Errorf("生成CSV失败: %v", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("生成CSV失败: %v", err)
+	}
+	return buffer.String(), nil
+}
+
+// escapeMarkdownCell 转义Markdown表格单元格中的竖线和换行
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(strings.ReplaceAll(value, "\n", " ")), " ")
+}
+
+// renderOutputMarkdown 输出带标题的Markdown表格
+func renderOutputMarkdown(schema string, title string, records []outputRecord) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# %s\n\n", title))
+	output.WriteString(fmt.Sprintf("架构: `%s`，共 %d 条记录\n\n", schema, len(records)))
+	writeMarkdownTable(&output, records)
+	return output.String()
+}
+
+// writeMarkdownTable 写入记录的Markdown表格，所有记录都为空的列不显示
+func writeMarkdownTable(output *strings.Builder, records []outputRecord) {
+	if len(records) == 0 {
+		return
+	}
+	columns, rows := outputColumns(records)
+
+	writeRow := func(cells []string) {
+		output.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	var header, separator []string
+	for _, j := range columns {
+		header = append(header, escapeMarkdownCell(rows[0][j].Label))
+		separator = append(separator, "---")
+	}
+	writeRow(header)
+	writeRow(separator)
+	for _, row := range rows {
+		var cells []string
+		for _, j := range columns {
+			cells = append(cells, escapeMarkdownCell(row[j].Value))
+		}
+		writeRow(cells)
+	}
+}
+
+// outputColumns 返回记录的字段和至少一条记录不为空的列
+func outputColumns(records []outputRecord) ([]int, [][]outputField) {
+	rows := make([][]outputField, len(records))
+	for i, record := range records {
+		rows[i] = record.outputFields()
+	}
+	var columns []int
+	for j := range rows[0] {
+		for _, row := range rows {
+			if row[j].Value != "" {
+				columns = append(columns, j)
+				break
+			}
+		}
+	}
+	return columns, rows
+}
+
+// RenderNetworks 按格式输出扫描结果
+func RenderNetworks(networks []WiFiNetwork, format string) (string, error) {
+	if format == OutputText || format == "" {
+		return FormatNetworksResult(networks), nil
+	}
+	var records []outputRecord
+	for _, network := range networks {
+		records = append(records, NewScanRecord(network))
+	}
+	return renderOutput(format, ScanSchema, "WiFi扫描结果", ScanRecord{}, records)
+}
+
+// RenderSavedNetworks 按格式输出已保存的网络，reveal为false时不输出密钥原文
+func RenderSavedNetworks(networks []SavedWiFi, format string, reveal bool) (string, error) {
+	if format == OutputText || format == "" {
+		return FormatSavedNetworksResult(networks, reveal), nil
+	}
+	var records []outputRecord
+	for _, network := range networks {
+		records = append(records, NewSavedRecord(network, reveal))
+	}
+	return renderOutput(format, SavedSchema, "已保存的WiFi网络", SavedRecord{}, records)
+}
+
+// RenderBruteForceResult 按格式输出爆破结果，reveal为false时不输出密码原文
+func RenderBruteForceResult(result *BruteForceResult, format string, reveal bool) (string, error) {
+	if format == OutputText || format == "" {
+		return FormatBruteForceResult(result, reveal), nil
+	}
+	return renderOutput(format, BruteSchema, "WiFi密码爆破结果", BruteRecord{}, []outputRecord{NewBruteRecord(result, reveal)})
+}
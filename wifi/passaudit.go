@@ -0,0 +1,610 @@
+package wifi
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultPBKDF2Rates 默认的离线破解速度（次/秒）
+// WPA/WPA2-PSK使用4096轮PBKDF2-HMAC-SHA1，单张高端GPU约每秒250万次，百卡集群约每秒2.5亿次
+var DefaultPBKDF2Rates = []float64{2.5e6, 2.5e8}
+
+// 密钥强度等级
+const (
+	StrengthVeryWeak = iota
+	StrengthWeak
+	StrengthMedium
+	StrengthStrong
+	StrengthVeryStrong
+)
+
+// strengthLabels 密钥强度等级的显示名称
+var strengthLabels = []string{"极弱", "弱", "中等", "强", "很强"}
+
+// commonWiFiPasswords 内置的常见WiFi密码，按常见程度排序
+var commonWiFiPasswords = []string{
+	"12345678", "123456789", "88888888", "1234567890", "00000000", "11111111", "66666666", "87654321",
+	"password", "1qaz2wsx", "qwertyui", "qwerty123", "1q2w3e4r", "a1234567", "abc12345", "abcd1234",
+	"12341234", "123123123", "11223344", "147258369", "123456abc", "5201314a", "woaini1314", "iloveyou",
+	"password1", "admin123", "88889999", "99999999", "12344321", "asdfghjk", "zxcvbnm1", "qwertyuiop",
+	"wifi1234", "internet", "welcome1", "sunshine", "princess", "football", "baseball", "superman",
+	"1234qwer", "qazwsxedc", "a12345678", "aa123456", "computer", "letmein1", "trustno1", "changeme",
+}
+
+// dictionaryWords 用于在密钥中查找的常见单词和拼音
+var dictionaryWords = []string{
+	"password", "passwd", "admin", "wifi", "wlan", "welcome", "love", "iloveyou", "woaini", "china",
+	"qwerty", "dragon", "monkey", "sunshine", "princess", "football", "baseball", "master", "hello",
+	"shadow", "guest", "home", "family", "internet", "wireless", "network", "router", "tplink",
+	"huawei", "xiaomi", "netgear", "linksys", "office", "company", "secret", "letmein", "test",
+	"baby", "happy", "lucky", "super", "abc", "pass", "user", "root", "mima", "zhang", "wang", "li",
+	"liu", "chen", "yang", "zhao", "huang", "zhou", "beijing", "shanghai", "summer", "winter",
+}
+
+// keyboardSequences 键盘上的连续按键序列，包括横排和竖列
+var keyboardSequences = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+	"1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik,9ol.0p;/",
+	"qazwsxedcrfvtgbyhnujmik,ol.p;/",
+}
+
+// leetSubstitutions 常见的字母数字替换
+var leetSubstitutions = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's', '!': 'i',
+}
+
+// 日期和电话号码模式
+var (
+	fullDatePattern  = regexp.MustCompile(`(19|20)\d{2}(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])|(0[1-9]|[12]\d|3[01])(0[1-9]|1[0-2])(19|20)\d{2}|(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])(19|20)\d{2}`)
+	yearPattern      = regexp.MustCompile(`19[5-9]\d|20[0-4]\d`)
+	mobilePattern    = regexp.MustCompile(`1[3-9]\d{9}`)
+	shortDatePattern = regexp.MustCompile(`(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])`)
+)
+
+// AuditOptions 表示密钥审计的设置
+type AuditOptions struct {
+	Wordlist map[string]bool // 额外的字典，键为小写密码
+	Rates    []float64       // 离线破解速度（次/秒）
+	Corpus   *BreachCorpus   // 本地泄露密码库，为nil时不检查
+}
+
+// CrackTime 表示在指定速度下的预计破解时间
+type CrackTime struct {
+	Rate    float64
+	Seconds float64
+}
+
+// PassphraseAudit 表示一个已保存网络的密钥审计结果
+type PassphraseAudit struct {
+	SSID             string
+	Authentication   string
+	Password         string
+	Length           int
+	CharClasses      []string
+	CharsetEntropy   float64 // 按字符集估算的熵（位）
+	Entropy          float64 // 考虑字典、键盘序列、日期等模式后的有效熵（位）
+	Findings         []AuditFinding
+	InWordlist       bool
+	BreachChecked    bool // 是否已查询泄露密码库
+	BreachCount      int  // 在泄露密码库中出现的次数
+	OfflineCrackable bool
+	CrackTimes       []CrackTime
+	Strength         int
+}
+
+// StrengthLabel 返回强度等级的显示名称
+func (a PassphraseAudit) StrengthLabel() string {
+	if a.Strength >= 0 && a.Strength < len(strengthLabels) {
+		return strengthLabels[a.Strength]
+	}
+	return "未知"
+}
+
+// AuditFinding 表示审计发现的一个问题，密钥中的模式只记录类型和位置，不保存匹配的原文
+type AuditFinding struct {
+	Label      string
+	Start, End int // 模式在密钥中的字符（rune）下标，左闭右开，不是模式时均为0
+}
+
+// Text 返回问题的显示文本，reveal为true时包含匹配的密钥片段
+func (f AuditFinding) Text(password string, reveal bool) string {
+	if f.End <= f.Start {
+		return f.Label
+	}
+	position := fmt.Sprintf("%d位, 第%d-%d位", f.End-f.Start, f.Start+1, f.End)
+	if runes := []rune(password); reveal && f.End <= len(runes) {
+		return fmt.Sprintf("%s: %s (%s)", f.Label, string(runes[f.Start:f.End]), position)
+	}
+	return fmt.Sprintf("%s (%s)", f.Label, position)
+}
+
+// FindingTexts 返回所有问题的显示文本，reveal为false时不包含密钥片段
+func (a PassphraseAudit) FindingTexts(reveal bool) []string {
+	texts := []string{}
+	for _, finding := range a.Findings {
+		texts = append(texts, finding.Text(a.Password, reveal))
+	}
+	return texts
+}
+
+// addFinding 追加一个与密钥位置无关的问题
+func (a *PassphraseAudit) addFinding(format string, args ...interface{}) {
+	a.Findings = append(a.Findings, AuditFinding{Label: fmt.Sprintf(format, args...)})
+}
+
+// patternMatch 表示密钥中的一段可预测模式
+type patternMatch struct {
+	start, end int // 字符（rune）下标，左闭右开
+	bits       float64
+	label      string
+}
+
+// ParsePBKDF2Rates 解析逗号分隔的破解速度，支持k、M、G后缀，如 "2.5M,250M"
+func ParsePBKDF2Rates(value string) ([]float64, error) {
+	var rates []float64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		multiplier := 1.0
+		switch strings.ToUpper(part[len(part)-1:]) {
+		case "K":
+			multiplier = 1e3
+		case "M":
+			multiplier = 1e6
+		case "G":
+			multiplier = 1e9
+		}
+		if multiplier != 1 {
+			part = part[:len(part)-1]
+		}
+		rate, err := strconv.ParseFloat(part, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("无效的破解速度: %s", part)
+		}
+		rates = append(rates, rate*multiplier)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("未指定破解速度")
+	}
+	return rates, nil
+}
+
+// LoadAuditWordlist 加载审计使用的字典文件，每行一个密码
+func LoadAuditWordlist(path string) (map[string]bool, error) {
+	passwords, err := loadCustomDictionary(path)
+	if err != nil {
+		return nil, fmt.Errorf("加载字典失败: %v", err)
+	}
+	wordlist := make(map[string]bool, len(passwords))
+	for _, password := range passwords {
+		wordlist[strings.ToLower(password)] = true
+	}
+	return wordlist, nil
+}
+
+// charClasses 返回密钥包含的字符类别及字符集大小
+func charClasses(password string) ([]string, int) {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < 0x80:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	var classes []string
+	size := 0
+	for _, class := range []struct {
+		present bool
+		name    string
+		size    int
+	}{
+		{lower, "小写字母", 26},
+		{upper, "大写字母", 26},
+		{digit, "数字", 10},
+		{symbol, "符号", 33},
+		{other, "其他字符", 100},
+	} {
+		if class.present {
+			classes = append(classes, class.name)
+			size += class.size
+		}
+	}
+	return classes, size
+}
+
+// normalizeLeet 将密钥转换为小写并还原常见的字母数字替换
+func normalizeLeet(runes []rune) []rune {
+	normalized := make([]rune, len(runes))
+	for i, r := range runes {
+		r = unicode.ToLower(r)
+		if replacement, ok := leetSubstitutions[r]; ok {
+			r = replacement
+		}
+		normalized[i] = r
+	}
+	return normalized
+}
+
+// findDictionaryMatches 查找密钥中的常见单词，包括大小写变化和字母数字替换
+func findDictionaryMatches(runes []rune) []patternMatch {
+	lower := []rune(strings.ToLower(string(runes)))
+	leet := normalizeLeet(runes)
+	dictionaryBits := math.Log2(float64(len(dictionaryWords)))
+
+	var matches []patternMatch
+	for _, word := range dictionaryWords {
+		wordRunes := []rune(word)
+		if len(wordRunes) < 3 {
+			continue
+		}
+		for _, candidate := range [][]rune{lower, leet} {
+			for start := 0; start+len(wordRunes) <= len(candidate); start++ {
+				if string(candidate[start:start+len(wordRunes)]) != word {
+					continue
+				}
+				end := start + len(wordRunes)
+				bits := dictionaryBits
+				segment := runes[start:end]
+				if strings.ToLower(string(segment)) != string(segment) {
+					bits++
+				}
+				if strings.ToLower(string(segment)) != word {
+					bits++
+				}
+				matches = append(matches, patternMatch{start, end, bits, "字典词"})
+			}
+		}
+	}
+	return matches
+}
+
+// findKeyboardMatches 查找长度不少于4的键盘连续按键，正反方向均可
+func findKeyboardMatches(runes []rune) []patternMatch {
+	lower := []rune(strings.ToLower(string(runes)))
+	var matches []patternMatch
+	for start := 0; start < len(lower); start++ {
+		best := 0
+		for _, sequence := range keyboardSequences {
+			for _, seq := range []string{sequence, reverseString(sequence)} {
+				for end := start + 4; end <= len(lower); end++ {
+					if !strings.Contains(seq, string(lower[start:end])) {
+						break
+					}
+					if end-start > best {
+						best = end - start
+					}
+				}
+			}
+		}
+		if best >= 4 {
+			end := start + best
+			matches = append(matches, patternMatch{start, end, 6 + math.Log2(float64(best)), "键盘序列"})
+			start = end - 1
+		}
+	}
+	return matches
+}
+
+// findRepeatAndSequenceMatches 查找重复字符（如aaaa）和连续字符（如1234、dcba）
+func findRepeatAndSequenceMatches(runes []rune, charBits float64) []patternMatch {
+	var matches []patternMatch
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && runes[end] == runes[start] {
+			end++
+		}
+		if end-start >= 3 {
+			matches = append(matches, patternMatch{start, end, charBits + math.Log2(float64(end-start)), "重复字符"})
+			start = end
+			continue
+		}
+
+		end = start + 1
+		if start+1 < len(runes) {
+			step := runes[start+1] - runes[start]
+			if step == 1 || step == -1 {
+				for end < len(runes) && runes[end]-runes[end-1] == step {
+					end++
+				}
+			}
+		}
+		if end-start >= 4 {
+			matches = append(matches, patternMatch{start, end, 4 + math.Log2(float64(end-start)), "连续字符"})
+			start = end
+			continue
+		}
+		start++
+	}
+	return matches
+}
+
+// findRegexpMatches 查找日期、年份和手机号码
+func findRegexpMatches(password string) []patternMatch {
+	var matches []patternMatch
+	add := func(pattern *regexp.Regexp, bits float64, label string) {
+		for _, loc := range pattern.FindAllStringIndex(password, -1) {
+			start, end := runeRange(password, loc)
+			matches = append(matches, patternMatch{start, end, bits, label})
+		}
+	}
+	add(mobilePattern, 27, "手机号码")
+	add(fullDatePattern, 16, "日期")
+	add(shortDatePattern, 9, "月日")
+	add(yearPattern, 7, "年份")
+	return matches
+}
+
+// runeRange 将正则匹配的字节下标转换为字符下标
+func runeRange(value string, loc []int) (int, int) {
+	start := utf8.RuneCountInString(value[:loc[0]])
+	return start, start + utf8.RuneCountInString(value[loc[0]:loc[1]])
+}
+
+// reverseString 反转字符串
+func reverseString(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// estimateEntropy 以最少位数覆盖整个密钥，估算考虑模式后的有效熵，并返回用到的模式
+func estimateEntropy(runes []rune, charBits float64, matches []patternMatch) (float64, []AuditFinding) {
+	n := len(runes)
+	best := make([]float64, n+1)
+	choice := make([]*patternMatch, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = best[i-1] + charBits
+		choice[i] = nil
+		for j := range matches {
+			m := &matches[j]
+			if m.end == i && best[m.start]+m.bits < best[i] {
+				best[i] = best[m.start] + m.bits
+				choice[i] = m
+			}
+		}
+	}
+
+	var findings []AuditFinding
+	for i := n; i > 0; {
+		if m := choice[i]; m != nil {
+			findings = append([]AuditFinding{{Label: m.label, Start: m.start, End: m.end}}, findings...)
+			i = m.start
+		} else {
+			i--
+		}
+	}
+	return best[n], findings
+}
+
+// AuditPassphrase 评估一个已保存网络的密钥强度
+func AuditPassphrase(network SavedWiFi, options AuditOptions) PassphraseAudit {
+	password := network.Password
+	runes := []rune(password)
+	auth := network.AuthType()
+
+	audit := PassphraseAudit{
+		SSID:             network.SSID,
+		Authentication:   network.Authentication,
+		Password:         password,
+		Length:           len(runes),
+		OfflineCrackable: auth != AuthWPA3SAE,
+	}
+
+	classes, charsetSize := charClasses(password)
+	audit.CharClasses = classes
+	charBits := math.Log2(float64(max(charsetSize, 1)))
+	audit.CharsetEntropy = float64(len(runes)) * charBits
+
+	if len(runes) == 64 && isHexString(password) {
+		// 64位十六进制为原始PSK，相当于256位随机密钥
+		audit.CharsetEntropy = 256
+		audit.Entropy = 256
+	} else {
+		var matches []patternMatch
+		matches = append(matches, findDictionaryMatches(runes)...)
+		matches = append(matches, findKeyboardMatches(runes)...)
+		matches = append(matches, findRepeatAndSequenceMatches(runes, charBits)...)
+		matches = append(matches, findRegexpMatches(password)...)
+		audit.Entropy, audit.Findings = estimateEntropy(runes, charBits, matches)
+	}
+
+	// 手机号码可能被更便宜的连续数字等模式覆盖，单独提示
+	if loc := mobilePattern.FindStringIndex(password); loc != nil && !audit.hasFinding("手机号码") {
+		start, end := runeRange(password, loc)
+		audit.Findings = append(audit.Findings, AuditFinding{Label: "手机号码", Start: start, End: end})
+	}
+
+	// 整个密钥出现在常见密码列表或字典中
+	lower := strings.ToLower(password)
+	for rank, common := range commonWiFiPasswords {
+		if lower == common {
+			audit.InWordlist = true
+			audit.Entropy = math.Min(audit.Entropy, math.Log2(float64(rank+2)))
+			audit.addFinding("出现在内置常见密码列表中")
+			break
+		}
+	}
+	if options.Wordlist[lower] {
+		audit.InWordlist = true
+		audit.Entropy = math.Min(audit.Entropy, math.Log2(float64(len(options.Wordlist)+1)))
+		audit.addFinding("出现在字典文件中")
+	}
+
+	if options.Corpus != nil {
+		count, err := options.Corpus.Lookup(password)
+		if err != nil {
+			audit.addFinding("查询泄露密码库失败: %v", err)
+		} else {
+			audit.BreachChecked = true
+			audit.BreachCount = count
+		}
+		if count > 0 {
+			// 泄露过的密码会被优先加入破解字典，数据集约10亿条，出现次数越多排序越靠前
+			audit.InWordlist = true
+			audit.Entropy = math.Min(audit.Entropy, math.Max(1, 30-math.Log2(float64(count))))
+			audit.addFinding("出现在泄露密码库中（%d次）", count)
+		}
+	}
+
+	if network.SSID != "" && len([]rune(network.SSID)) >= 3 && strings.Contains(lower, strings.ToLower(network.SSID)) {
+		audit.addFinding("包含SSID")
+	}
+	if auth != AuthWEP && len(runes) < 12 && !(len(runes) == 64 && isHexString(password)) {
+		audit.addFinding("长度少于12个字符")
+	}
+	if len(classes) == 1 {
+		audit.addFinding("只包含%s", classes[0])
+	}
+
+	// 平均需要尝试一半的密钥空间
+	for _, rate := range options.Rates {
+		audit.CrackTimes = append(audit.CrackTimes, CrackTime{Rate: rate, Seconds: math.Pow(2, audit.Entropy-1) / rate})
+	}
+
+	switch {
+	case auth == AuthWEP:
+		audit.Strength = StrengthVeryWeak
+		audit.addFinding("WEP可在几分钟内被破解，与密钥强度无关")
+	case audit.InWordlist || audit.Entropy < 28:
+		audit.Strength = StrengthVeryWeak
+	case audit.Entropy < 40:
+		audit.Strength = StrengthWeak
+	case audit.Entropy < 60:
+		audit.Strength = StrengthMedium
+	case audit.Entropy < 80:
+		audit.Strength = StrengthStrong
+	default:
+		audit.Strength = StrengthVeryStrong
+	}
+	return audit
+}
+
+// hasFinding 判断是否已有指定类型的问题
+func (a PassphraseAudit) hasFinding(label string) bool {
+	for _, finding := range a.Findings {
+		if finding.Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditSavedNetworks 审计所有已获取密钥的个人认证网络，按强度从弱到强排序
+// 返回审计结果和未审计的网络（开放网络、企业网络或未获取到密钥）
+func AuditSavedNetworks(networks []SavedWiFi, options AuditOptions) ([]PassphraseAudit, []SavedWiFi) {
+	var audits []PassphraseAudit
+	var skipped []SavedWiFi
+	for _, network := range networks {
+		if !network.AuthType().IsPersonal() || !network.HasPassword() {
+			skipped = append(skipped, network)
+			continue
+		}
+		audits = append(audits, AuditPassphrase(network, options))
+	}
+
+	sort.SliceStable(audits, func(i, j int) bool {
+		if audits[i].Strength != audits[j].Strength {
+			return audits[i].Strength < audits[j].Strength
+		}
+		return audits[i].Entropy < audits[j].Entropy
+	})
+	return audits, skipped
+}
+
+// formatRate 格式化破解速度
+func formatRate(rate float64) string {
+	switch {
+	case rate >= 1e9:
+		return fmt.Sprintf("%g G次/秒", rate/1e9)
+	case rate >= 1e6:
+		return fmt.Sprintf("%g M次/秒", rate/1e6)
+	case rate >= 1e3:
+		return fmt.Sprintf("%g k次/秒", rate/1e3)
+	}
+	return fmt.Sprintf("%g 次/秒", rate)
+}
+
+// formatCrackTime 格式化预计破解时间
+func formatCrackTime(seconds float64) string {
+	const year = 365.25 * 24 * 3600
+	switch {
+	case seconds < 1:
+		return "瞬间"
+	case seconds < 60:
+		return fmt.Sprintf("%.0f秒", seconds)
+	case seconds < 3600:
+		return fmt.Sprintf("%.0f分钟", seconds/60)
+	case seconds < 24*3600:
+		return fmt.Sprintf("%.1f小时", seconds/3600)
+	case seconds < year:
+		return fmt.Sprintf("%.0f天", seconds/(24*3600))
+	case seconds < 1e4*year:
+		return fmt.Sprintf("%.0f年", seconds/year)
+	case seconds < 1e12*year:
+		return fmt.Sprintf("%.1e年", seconds/year)
+	}
+	return "超过1万亿年"
+}
+
+// FormatPassphraseAuditResult 格式化密钥审计结果，reveal为false时密钥只显示掩码
+func FormatPassphraseAuditResult(audits []PassphraseAudit, skipped []SavedWiFi, reveal bool) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("=== 已保存网络密钥强度审计 - %s ===\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	output.WriteString(fmt.Sprintf("审计 %d 个网络，按强度从弱到强排列:\n\n", len(audits)))
+
+	for i, audit := range audits {
+		output.WriteString(fmt.Sprintf("#%d %s [%s]\n", i+1, audit.SSID, audit.StrengthLabel()))
+		writeOptionalField(&output, "身份验证", audit.Authentication)
+		output.WriteString(fmt.Sprintf("  密钥: %s\n", ShowSecret(audit.Password, reveal)))
+		output.WriteString(fmt.Sprintf("  长度: %d\n", audit.Length))
+		output.WriteString(fmt.Sprintf("  字符类别: %s\n", strings.Join(audit.CharClasses, "、")))
+		output.WriteString(fmt.Sprintf("  字符集熵: %.1f 位\n", audit.CharsetEntropy))
+		output.WriteString(fmt.Sprintf("  有效熵: %.1f 位\n", audit.Entropy))
+		if audit.OfflineCrackable {
+			for _, crack := range audit.CrackTimes {
+				output.WriteString(fmt.Sprintf("  预计离线破解时间 (%s): %s\n", formatRate(crack.Rate), formatCrackTime(crack.Seconds)))
+			}
+		} else {
+			output.WriteString("  预计离线破解时间: 不适用（WPA3-SAE不能离线字典破解）\n")
+		}
+		if audit.BreachChecked && audit.BreachCount == 0 {
+			output.WriteString("  泄露密码库: 未出现\n")
+		}
+		for _, finding := range audit.FindingTexts(reveal) {
+			output.WriteString(fmt.Sprintf("  问题: %s\n", finding))
+		}
+		output.WriteString("\n")
+	}
+
+	if len(skipped) > 0 {
+		output.WriteString(fmt.Sprintf("未审计 %d 个网络:\n", len(skipped)))
+		for _, network := range skipped {
+			reason := network.KeyStatus.String()
+			if network.HasPassword() {
+				reason = fmt.Sprintf("%s网络", network.AuthType())
+			}
+			output.WriteString(fmt.Sprintf("  %s: %s\n", network.SSID, reason))
+		}
+	}
+	return output.String()
+}
@@ -0,0 +1,153 @@
+package wifi
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAuditFindingsRedacted(t *testing.T) {
+	keys := []string{"Zq13812345678!", "P@ssw0rd2024qwer", "aaaa19900101"}
+	for _, key := range keys {
+		network := SavedWiFi{SSID: "HomeNet", Password: key, KeyStatus: KeyPresent, Authentication: "WPA2-Personal"}
+		audits, skipped := AuditSavedNetworks([]SavedWiFi{network}, AuditOptions{Rates: DefaultPBKDF2Rates})
+		text := FormatPassphraseAuditResult(audits, skipped, false)
+		data, err := json.Marshal(NewAuditPayload(audits, skipped, false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(audits[0].Findings) == 0 {
+			t.Fatalf("%s: 未发现问题", key)
+		}
+
+		// 掩码会显示首尾各一个字符，检查密钥中任意连续5个字符都没有出现
+		runes := []rune(key)
+		for i := 0; i+5 <= len(runes); i++ {
+			part := string(runes[i : i+5])
+			if strings.Contains(text, part) {
+				t.Errorf("%s: 未显示密钥时文本中出现了 %q:\n%s", key, part, text)
+			}
+			if strings.Contains(string(data), part) {
+				t.Errorf("%s: 未保存密钥时载荷中出现了 %q: %s", key, part, data)
+			}
+		}
+	}
+
+	audit := AuditPassphrase(SavedWiFi{SSID: "HomeNet", Password: "Zq13812345678!", Authentication: "WPA2-Personal"}, AuditOptions{})
+	if !strings.Contains(strings.Join(audit.FindingTexts(false), "\n"), "手机号码 (11位, 第3-13位)") {
+		t.Errorf("问题 = %q，期望包含手机号码的位置", audit.FindingTexts(false))
+	}
+	if !strings.Contains(strings.Join(audit.FindingTexts(true), "\n"), "手机号码: 13812345678 (11位, 第3-13位)") {
+		t.Errorf("问题 = %q，显示密钥时期望包含手机号码原文", audit.FindingTexts(true))
+	}
+}
+
+func TestParsePBKDF2Rates(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []float64
+		valid bool
+	}{
+		{"2.5M,250M", []float64{2.5e6, 2.5e8}, true},
+		{"100k", []float64{1e5}, true},
+		{"1G", []float64{1e9}, true},
+		{" 5 , 7 ,", []float64{5, 7}, true},
+		{"", nil, false},
+		{"abc", nil, false},
+		{"-1", nil, false},
+		{"0", nil, false},
+		{"3x", nil, false},
+	}
+	for _, test := range tests {
+		rates, err := ParsePBKDF2Rates(test.value)
+		if (err == nil) != test.valid || !reflect.DeepEqual(rates, test.want) {
+			t.Errorf("ParsePBKDF2Rates(%q) = %v, %v，期望 %v", test.value, rates, err, test.want)
+		}
+	}
+}
+
+func TestCharClasses(t *testing.T) {
+	tests := []struct {
+		password string
+		classes  []string
+		size     int
+	}{
+		{"12345678", []string{"数字"}, 10},
+		{"abcDEF", []string{"小写字母", "大写字母"}, 52},
+		{"Xk9#mQ2$", []string{"小写字母", "大写字母", "数字", "符号"}, 95},
+		{"密码pass", []string{"小写字母", "其他字符"}, 126},
+	}
+	for _, test := range tests {
+		classes, size := charClasses(test.password)
+		if !reflect.DeepEqual(classes, test.classes) || size != test.size {
+			t.Errorf("charClasses(%q) = %v, %d，期望 %v, %d", test.password, classes, size, test.classes, test.size)
+		}
+	}
+}
+
+func TestAuditPatternDetectors(t *testing.T) {
+	tests := []struct {
+		password string
+		want     []AuditFinding
+	}{
+		{"abcdefgh", []AuditFinding{{"连续字符", 0, 8}}},
+		{"98765xZ", []AuditFinding{{"连续字符", 0, 5}}},
+		{"aaaaaaaa", []AuditFinding{{"重复字符", 0, 8}}},
+		{"zaq1xsw2", []AuditFinding{{"键盘序列", 0, 4}, {"键盘序列", 4, 8}}},
+		{"Xk;lkjhgf", []AuditFinding{{"键盘序列", 2, 9}}},
+		{"p@ssw0rd", []AuditFinding{{"字典词", 0, 8}}},
+		{"Pa55word", []AuditFinding{{"字典词", 0, 8}}},
+		{"qwerty2024!", []AuditFinding{{"字典词", 0, 6}, {"年份", 6, 10}}},
+		{"20240101x", []AuditFinding{{"日期", 0, 8}}},
+		{"x31122019", []AuditFinding{{"日期", 1, 9}}},
+		{"lovE0214", []AuditFinding{{"字典词", 0, 4}, {"月日", 4, 8}}},
+		{"Zq13812345678!", []AuditFinding{{"连续字符", 5, 13}, {"手机号码", 2, 13}}},
+		{"密码X13912398765", []AuditFinding{{"手机号码", 3, 14}}},
+		{"Xk9#mQ2$vL7!pR4&", nil},
+	}
+	for _, test := range tests {
+		audit := AuditPassphrase(SavedWiFi{SSID: "HomeNet", Password: test.password, Authentication: "WPA2-Personal"}, AuditOptions{})
+		var patterns []AuditFinding
+		for _, finding := range audit.Findings {
+			if finding.End > finding.Start {
+				patterns = append(patterns, finding)
+			}
+		}
+		if !reflect.DeepEqual(patterns, test.want) {
+			t.Errorf("%q 的模式 = %+v，期望 %+v", test.password, patterns, test.want)
+		}
+	}
+}
+
+func TestAuditEntropyAndStrength(t *testing.T) {
+	tests := []struct {
+		password string
+		auth     string
+		wordlist map[string]bool
+		entropy  float64
+		strength int
+	}{
+		// 内置常见密码列表第1位，log2(2)
+		{"12345678", "WPA2-Personal", nil, 1, StrengthVeryWeak},
+		{"Xk9#mQ", "WPA2-Personal", nil, 6 * math.Log2(95), StrengthWeak},
+		{"Xk9#mQ2$", "WPA2-Personal", nil, 8 * math.Log2(95), StrengthMedium},
+		{"Tr0ub4dor&3", "WPA2-Personal", nil, 11 * math.Log2(95), StrengthStrong},
+		{"Xk9#mQ2$vL7!pR4&", "WPA2-Personal", nil, 16 * math.Log2(95), StrengthVeryStrong},
+		{strings.Repeat("ab12", 16), "WPA2-Personal", nil, 256, StrengthVeryStrong},
+		// 字典文件中的密码，log2(字典大小+1)
+		{"Xk9#mQ2$vL7!pR4&", "WPA2-Personal", map[string]bool{"xk9#mq2$vl7!pr4&": true, "other": true, "third": true}, 2, StrengthVeryWeak},
+		{"Xk9#mQ2$vL7!p", "WEP", nil, 13 * math.Log2(95), StrengthVeryWeak},
+	}
+	for _, test := range tests {
+		audit := AuditPassphrase(SavedWiFi{SSID: "HomeNet", Password: test.password, Authentication: test.auth},
+			AuditOptions{Wordlist: test.wordlist, Rates: []float64{1e6}})
+		if math.Abs(audit.Entropy-test.entropy) > 1e-9 || audit.Strength != test.strength {
+			t.Errorf("%q: 有效熵 %.2f 强度 %d，期望 %.2f 强度 %d", test.password, audit.Entropy, audit.Strength, test.entropy, test.strength)
+		}
+		if want := math.Pow(2, test.entropy-1) / 1e6; math.Abs(audit.CrackTimes[0].Seconds-want) > want*1e-9 {
+			t.Errorf("%q: 破解时间 %g 秒，期望 %g", test.password, audit.CrackTimes[0].Seconds, want)
+		}
+	}
+}
@@ -8,10 +8,94 @@ import (
 	"time"
 )
 
+// KeyStatus 表示已保存网络密钥的获取状态
+type KeyStatus int
+
+const (
+	KeyUnknown    KeyStatus = iota // 未知
+	KeyPresent                     // 已获取密钥
+	KeyAbsent                      // 配置文件不包含密钥，如开放网络
+	KeyHidden                      // 配置文件包含密钥但无法读取，通常需要管理员权限
+	KeyError                       // 获取配置文件失败
+	KeyEnterprise                  // 802.1X企业认证，不使用预共享密钥
+)
+
+// String 返回密钥状态的描述
+func (k KeyStatus) String() string {
+	switch k {
+	case KeyPresent:
+		return "已获取"
+	case KeyAbsent:
+		return "无密钥"
+	case KeyHidden:
+		return "存在密钥但无法读取（需要管理员权限）"
+	case KeyError:
+		return "获取失败"
+	case KeyEnterprise:
+		return "802.1X企业认证，无预共享密钥"
+	}
+	return "未知"
+}
+
+// 配置文件作用范围
+const (
+	ScopeAllUsers    = "all-users"
+	ScopeCurrentUser = "current-user"
+)
+
+// 连接模式
+const (
+	ConnectionAuto   = "auto"
+	ConnectionManual = "manual"
+)
+
 // SavedWiFi 表示一个已保存的WiFi网络
 type SavedWiFi struct {
-	SSID     string
-	Password string
+	SSID             string
+	Password         string
+	KeyStatus        KeyStatus // 密钥获取状态
+	KeyError         string    // 获取失败时的错误信息
+	ProfileName      string    // 配置文件名称，可能与SSID不同
+	Scope            string    // 作用范围：所有用户或当前用户
+	Authentication   string    // 身份验证方式，多个用"/"分隔，如 WPA2-Personal/WPA3-Personal
+	Cipher           string    // 加密方式，多个用"/"分隔
+	KeyType          string    // 密钥类型，如 passPhrase、networkKey
+	ConnectionMode   string    // 连接模式：自动或手动
+	NonBroadcast     bool      // 即使网络未广播也连接（隐藏网络）
+	AutoSwitch       bool      // 是否自动切换到更优先的网络
+	MACRandomization string    // MAC地址随机化设置
+	Cost             string    // 费用设置
+	NetworkType      string    // 网络类型
+	RadioType        string    // 无线电类型
+
+	Enterprise *EnterpriseConfig // 802.1X企业认证配置，非企业网络为nil
+}
+
+// IsEnterprise 判断是否为802.1X企业认证网络
+func (s SavedWiFi) IsEnterprise() bool {
+	return s.Enterprise != nil
+}
+
+// HasPassword 判断是否已获取到密钥
+func (s SavedWiFi) HasPassword() bool {
+	return s.KeyStatus == KeyPresent
+}
+
+// PasswordDisplay 返回密钥的显示文本，未获取到密钥时显示状态
+func (s SavedWiFi) PasswordDisplay() string {
+	if s.HasPassword() {
+		return s.Password
+	}
+	if s.KeyStatus == KeyError && s.KeyError != "" {
+		return fmt.Sprintf("%s (%s)", s.KeyStatus, s.KeyError)
+	}
+	return s.KeyStatus.String()
+}
+
+// savedProfileRef 表示netsh列出的一个配置文件
+type savedProfileRef struct {
+	Name  string
+	Scope string
 }
 
 // GetSavedNetworks 获取已保存的WiFi网络
@@ -23,85 +107,216 @@ func GetSavedNetworks() ([]SavedWiFi, error) {
 		return nil, fmt.Errorf("获取WiFi配置文件失败: %v", err)
 	}
 
-	// 解析输出，提取SSID
-	ssids := extractSSIDs(string(output))
+	// 解析输出，提取配置文件名称
+	profiles := extractProfiles(string(output))
 
-	// 获取每个SSID的密码
+	// 获取每个配置文件的详细信息
 	var savedNetworks []SavedWiFi
-	for _, ssid := range ssids {
-		password, err := getPasswordForSSID(ssid)
+	for _, profile := range profiles {
+		network, err := getProfileDetails(profile.Name)
 		if err != nil {
-			// 如果获取密码失败，记录错误但继续处理其他网络
-			fmt.Printf("警告: 获取 %s 的密码失败: %v\n", ssid, err)
-			savedNetworks = append(savedNetworks, SavedWiFi{
-				SSID:     ssid,
-				Password: "获取失败",
-			})
-		} else {
-			savedNetworks = append(savedNetworks, SavedWiFi{
-				SSID:     ssid,
-				Password: password,
-			})
+			// 如果获取失败，记录错误但继续处理其他网络
+			warnf("获取 %s 的配置失败: %v", profile.Name, err)
+			network = SavedWiFi{
+				SSID:      profile.Name,
+				KeyStatus: KeyError,
+				KeyError:  err.Error(),
+			}
+		}
+		network.ProfileName = profile.Name
+		if network.Scope == "" {
+			network.Scope = profile.Scope
 		}
+
+		// 企业网络的服务器验证设置只能从导出的XML中获取
+		if network.IsEnterprise() {
+			if err := loadEnterpriseConfig(&network); err != nil {
+				warnf("获取 %s 的802.1X配置失败: %v", profile.Name, err)
+			}
+		}
+		savedNetworks = append(savedNetworks, network)
 	}
 
 	return savedNetworks, nil
 }
 
-// extractSSIDs 从netsh输出中提取SSID
-func extractSSIDs(output string) []string {
-	var ssids []string
+// profileLineRe 匹配"所有用户配置文件 : 名称"或"当前用户配置文件 : 名称"这一行
+var profileLineRe = regexp.MustCompile(`(?im)^\s*(所有用户配置文件|当前用户配置文件|All User Profile|Current User Profile)\s*:\s*(.+)$`)
 
-	// 使用正则表达式匹配"所有用户配置文件 : SSID名称"这一行
-	re := regexp.MustCompile(`(?i)所有用户配置文件\s*:\s*(.+)`)
-	if !re.MatchString(output) {
-		// 尝试英文版本
-		re = regexp.MustCompile(`(?i)All User Profile\s*:\s*(.+)`)
+// extractProfiles 从netsh输出中提取配置文件名称及其作用范围
+func extractProfiles(output string) []savedProfileRef {
+	var profiles []savedProfileRef
+	for _, match := range profileLineRe.FindAllStringSubmatch(output, -1) {
+		profiles = append(profiles, savedProfileRef{
+			Name:  strings.TrimSpace(match[2]),
+			Scope: parseScope(match[1]),
+		})
 	}
+	return profiles
+}
 
-	matches := re.FindAllStringSubmatch(output, -1)
-	for _, match := range matches {
-		if len(match) >= 2 {
-			ssid := strings.TrimSpace(match[1])
-			ssids = append(ssids, ssid)
-		}
+// parseScope 将配置文件类型文本转换为作用范围
+func parseScope(text string) string {
+	lower := strings.ToLower(text)
+	if strings.Contains(lower, "current") || strings.Contains(text, "当前用户") {
+		return ScopeCurrentUser
 	}
-
-	return ssids
+	if strings.Contains(lower, "all user") || strings.Contains(text, "所有用户") {
+		return ScopeAllUsers
+	}
+	return ""
 }
 
-// getPasswordForSSID 获取指定SSID的密码
-func getPasswordForSSID(ssid string) (string, error) {
-	// 使用netsh命令获取指定SSID的详细信息，包括密码
-	cmd := exec.Command("netsh", "wlan", "show", "profile", "name="+ssid, "key=clear")
+// getProfileDetails 获取指定配置文件的详细信息，包括密码
+func getProfileDetails(name string) (SavedWiFi, error) {
+	// 使用netsh命令获取指定配置文件的详细信息，包括密码
+	cmd := exec.Command("netsh", "wlan", "show", "profile", "name="+name, "key=clear")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("获取WiFi密码失败: %v", err)
+		return SavedWiFi{}, fmt.Errorf("获取WiFi配置文件详情失败: %v", err)
+	}
+
+	// 解析输出
+	network := parseProfileDetails(string(output))
+	if network.SSID == "" {
+		network.SSID = name
 	}
+	return network, nil
+}
 
-	// 解析输出，提取密码
-	return extractPassword(string(output)), nil
+// normalizeProfileKey 统一netsh输出中的键名，去除多余空格并转为小写
+func normalizeProfileKey(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(key), " "))
 }
 
-// extractPassword 从netsh输出中提取密码
-func extractPassword(output string) string {
-	// 使用正则表达式匹配"关键内容 : 密码"这一行
-	re := regexp.MustCompile(`(?i)关键内容\s*:\s*(.+)`)
-	if !re.MatchString(output) {
-		// 尝试英文版本
-		re = regexp.MustCompile(`(?i)Key Content\s*:\s*(.+)`)
+// parseProfileDetails 解析 netsh wlan show profile key=clear 的输出
+// 支持中英文两种输出，注意中文版中"密码"一行表示的是加密方式
+func parseProfileDetails(output string) SavedWiFi {
+	var network SavedWiFi
+	var authentications, ciphers []string
+	securityKey := ""
+	keyContentFound := false
+
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) < 2 {
+			continue
+		}
+		key := normalizeProfileKey(parts[0])
+		value := strings.TrimSpace(parts[1])
+		lowerValue := strings.ToLower(value)
+
+		switch key {
+		case "applied", "已应用":
+			network.Scope = parseScope(value)
+		case "name", "名称":
+			network.ProfileName = value
+		case "ssid name", "ssid 名称":
+			network.SSID = strings.Trim(value, `"“”`)
+		case "connection mode", "连接模式":
+			if strings.Contains(lowerValue, "manual") || strings.Contains(value, "手动") {
+				network.ConnectionMode = ConnectionManual
+			} else if value != "" {
+				network.ConnectionMode = ConnectionAuto
+			}
+		case "network broadcast", "网络广播":
+			network.NonBroadcast = strings.Contains(lowerValue, "even if") || strings.Contains(value, "即使")
+		case "autoswitch", "自动切换":
+			network.AutoSwitch = !(strings.Contains(lowerValue, "do not") || strings.Contains(value, "请勿"))
+		case "mac randomization", "mac 随机化", "随机硬件地址":
+			network.MACRandomization = value
+		case "network type", "网络类型":
+			network.NetworkType = value
+		case "radio type", "无线电类型":
+			network.RadioType = strings.TrimSpace(strings.Trim(value, "[]"))
+		case "authentication", "身份验证":
+			authentications = appendUnique(authentications, value)
+		case "cipher", "密码":
+			ciphers = appendUnique(ciphers, value)
+		case "security key", "安全密钥":
+			securityKey = lowerValue
+		case "key content", "关键内容":
+			network.Password = value
+			network.KeyType = guessKeyType(value)
+			keyContentFound = true
+		case "cost", "费用":
+			network.Cost = value
+		case "802.1x":
+			if strings.Contains(lowerValue, "enabled") || strings.Contains(value, "启用") {
+				network.Enterprise = &EnterpriseConfig{}
+			}
+		case "eap type", "eap 类型":
+			if network.Enterprise == nil {
+				network.Enterprise = &EnterpriseConfig{}
+			}
+			network.Enterprise.EAPMethod = parseEAPTypeText(value)
+		case "802.1x auth credential", "802.1x 身份验证凭据":
+			if network.Enterprise != nil {
+				network.Enterprise.CredentialSource = value
+			}
+		}
 	}
 
-	match := re.FindStringSubmatch(output)
-	if len(match) >= 2 {
-		return strings.TrimSpace(match[1])
+	network.Authentication = strings.Join(authentications, "/")
+	network.Cipher = strings.Join(ciphers, "/")
+
+	// 身份验证方式为企业版时同样视为802.1X网络
+	lowerAuth := strings.ToLower(network.Authentication)
+	if network.Enterprise == nil && (strings.Contains(lowerAuth, "enterprise") || strings.Contains(network.Authentication, "企业")) {
+		network.Enterprise = &EnterpriseConfig{}
 	}
 
-	return "未找到密码"
+	// 判断密钥状态
+	switch {
+	case keyContentFound:
+		network.KeyStatus = KeyPresent
+	case network.Enterprise != nil:
+		network.KeyStatus = KeyEnterprise
+	case strings.Contains(securityKey, "absent") || strings.Contains(securityKey, "不存在"):
+		network.KeyStatus = KeyAbsent
+	case strings.Contains(securityKey, "present") || strings.Contains(securityKey, "存在"):
+		network.KeyStatus = KeyHidden
+	default:
+		network.KeyStatus = KeyUnknown
+	}
+
+	return network
 }
 
-// FormatSavedNetworksResult 格式化已保存网络的结果
-func FormatSavedNetworksResult(networks []SavedWiFi) string {
+// guessKeyType 根据密钥内容推断密钥类型，netsh输出中不包含该信息
+// 64位十六进制为原始网络密钥，其余为密码短语
+func guessKeyType(key string) string {
+	if len(key) == 64 && isHexString(key) {
+		return "networkKey"
+	}
+	return "passPhrase"
+}
+
+// isHexString 判断字符串是否只包含十六进制字符
+func isHexString(value string) bool {
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return value != ""
+}
+
+// appendUnique 追加不重复的非空值
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// FormatSavedNetworksResult 格式化已保存网络的结果，reveal为false时密钥只显示掩码
+func FormatSavedNetworksResult(networks []SavedWiFi, reveal bool) string {
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("=== 已保存的WiFi网络 - %s ===\n\n", time.Now().Format("2006-01-02 15:04:05")))
 	result.WriteString(fmt.Sprintf("发现 %d 个已保存的WiFi网络:\n\n", len(networks)))
@@ -109,9 +324,71 @@ func FormatSavedNetworksResult(networks []SavedWiFi) string {
 	for i, network := range networks {
 		result.WriteString(fmt.Sprintf("网络 #%d:\n", i+1))
 		result.WriteString(fmt.Sprintf("  SSID: %s\n", network.SSID))
-		result.WriteString(fmt.Sprintf("  密码: %s\n", network.Password))
+		if network.ProfileName != "" && network.ProfileName != network.SSID {
+			result.WriteString(fmt.Sprintf("  配置文件: %s\n", network.ProfileName))
+		}
+		password := network.PasswordDisplay()
+		if network.HasPassword() {
+			password = ShowSecret(network.Password, reveal)
+		}
+		result.WriteString(fmt.Sprintf("  密码: %s\n", password))
+		writeOptionalField(&result, "身份验证", network.Authentication)
+		writeOptionalField(&result, "加密方式", network.Cipher)
+		writeOptionalField(&result, "密钥类型", network.KeyType)
+		writeOptionalField(&result, "连接模式", formatConnectionMode(network.ConnectionMode))
+		if network.ConnectionMode != "" {
+			result.WriteString(fmt.Sprintf("  自动切换: %s\n", formatBool(network.AutoSwitch)))
+		}
+		if network.NonBroadcast {
+			result.WriteString("  隐藏网络: 是\n")
+		}
+		writeOptionalField(&result, "MAC随机化", network.MACRandomization)
+		writeOptionalField(&result, "费用设置", network.Cost)
+		writeOptionalField(&result, "网络类型", network.NetworkType)
+		writeOptionalField(&result, "无线电类型", network.RadioType)
+		writeOptionalField(&result, "作用范围", formatScope(network.Scope))
+		if network.Enterprise != nil {
+			formatEnterpriseConfig(&result, network.Enterprise)
+		}
 		result.WriteString("\n")
 	}
 
 	return result.String()
 }
+
+// writeOptionalField 仅在值非空时写入字段
+func writeOptionalField(output *strings.Builder, name string, value string) {
+	if value != "" {
+		output.WriteString(fmt.Sprintf("  %s: %s\n", name, value))
+	}
+}
+
+// formatConnectionMode 返回连接模式的显示文本
+func formatConnectionMode(mode string) string {
+	switch mode {
+	case ConnectionAuto:
+		return "自动连接"
+	case ConnectionManual:
+		return "手动连接"
+	}
+	return mode
+}
+
+// formatScope 返回作用范围的显示文本
+func formatScope(scope string) string {
+	switch scope {
+	case ScopeAllUsers:
+		return "所有用户"
+	case ScopeCurrentUser:
+		return "当前用户"
+	}
+	return scope
+}
+
+// formatBool 返回布尔值的显示文本
+func formatBool(value bool) string {
+	if value {
+		return "是"
+	}
+	return "否"
+}
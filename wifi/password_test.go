@@ -0,0 +1,164 @@
+package wifi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// netshProfileSample 为 netsh wlan show profile name=HomeNet key=clear 的英文输出
+const netshProfileSample = `
+Profile HomeNet on interface Wi-Fi:
+=======================================================================
+
+Applied: All User Profile
+
+Profile information
+-------------------
+    Version                : 1
+    Type                   : Wireless LAN
+    Name                   : HomeNet
+    Control options        :
+        Connection mode    : Connect automatically
+        Network broadcast  : Connect only if this network is broadcasting
+        AutoSwitch         : Do not switch to other networks
+        MAC Randomization  : Disabled
+
+Connectivity settings
+---------------------
+    Number of SSIDs        : 1
+    SSID name              : "HomeNet"
+    Network type           : Infrastructure
+    Radio type             : [ Any Radio Type ]
+    Vendor extension          : Not present
+
+Security settings
+-----------------
+    Authentication         : WPA2-Personal
+    Cipher                 : CCMP
+    Authentication         : WPA2-Personal
+    Cipher                 : GCMP
+    Security key           : Present
+    Key Content            : p@ss:word 1
+
+Cost settings
+-------------
+    Cost                   : Unrestricted
+    Congested              : No
+    Approaching Data Limit : No
+    Over Data Limit        : No
+    Roaming                : No
+    Cost Source            : Default
+`
+
+// netshProfileSampleZH 为中文系统中企业网络配置文件的输出
+const netshProfileSampleZH = `
+接口 WLAN 上的配置文件 办公室:
+=======================================================================
+
+已应用: 当前用户配置文件
+
+配置文件信息
+-------------------
+    版本                   : 1
+    类型                   : 无线局域网
+    名称                   : 办公室
+    控制选项               :
+        连接模式           : 手动连接
+        网络广播           : 即使网络未广播也进行连接
+        自动切换           : 切换到其他网络
+        MAC 随机化         : 已禁用
+
+连接设置
+---------------------
+    SSID 数目              : 1
+    SSID 名称              :“办公室”
+    网络类型               : 结构
+    无线电类型             : [ 任何无线电类型 ]
+
+安全设置
+-----------------
+    身份验证               : WPA2 - 企业
+    密码                   : CCMP
+    安全密钥               : 不存在
+    802.1X                 : 已启用
+    EAP 类型               : Microsoft: 受保护的 EAP (PEAP)
+    802.1X 身份验证凭据    : 用户凭据
+`
+
+// netshProfileSampleHidden 为非管理员运行时带密钥的配置文件，输出中没有密钥内容
+const netshProfileSampleHidden = `
+Applied: All User Profile
+    Name                   : Cafe
+    SSID name              : "Cafe"
+    Authentication         : WPA3-Personal
+    Cipher                 : GCMP
+    Security key           : Present
+`
+
+// netshProfileSampleOpen 为开放网络的配置文件
+const netshProfileSampleOpen = `
+Applied: All User Profile
+    Name                   : Airport
+    SSID name              : "Airport"
+    Authentication         : Open
+    Cipher                 : None
+    Security key           : Absent
+`
+
+func TestParseProfileDetails(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   SavedWiFi
+	}{
+		{"英文", netshProfileSample, SavedWiFi{
+			SSID: "HomeNet", Password: "p@ss:word 1", KeyStatus: KeyPresent, ProfileName: "HomeNet",
+			Scope: ScopeAllUsers, Authentication: "WPA2-Personal", Cipher: "CCMP/GCMP", KeyType: "passPhrase",
+			ConnectionMode: ConnectionAuto, MACRandomization: "Disabled", Cost: "Unrestricted",
+			NetworkType: "Infrastructure", RadioType: "Any Radio Type",
+		}},
+		{"中文企业网络", netshProfileSampleZH, SavedWiFi{
+			SSID: "办公室", KeyStatus: KeyEnterprise, ProfileName: "办公室", Scope: ScopeCurrentUser,
+			Authentication: "WPA2 - 企业", Cipher: "CCMP", ConnectionMode: ConnectionManual, NonBroadcast: true,
+			AutoSwitch: true, MACRandomization: "已禁用", NetworkType: "结构", RadioType: "任何无线电类型",
+			Enterprise: &EnterpriseConfig{EAPMethod: "PEAP", CredentialSource: "用户凭据"},
+		}},
+		{"无法读取密钥", netshProfileSampleHidden, SavedWiFi{
+			SSID: "Cafe", KeyStatus: KeyHidden, ProfileName: "Cafe", Scope: ScopeAllUsers,
+			Authentication: "WPA3-Personal", Cipher: "GCMP",
+		}},
+		{"开放网络", netshProfileSampleOpen, SavedWiFi{
+			SSID: "Airport", KeyStatus: KeyAbsent, ProfileName: "Airport", Scope: ScopeAllUsers,
+			Authentication: "Open", Cipher: "None",
+		}},
+	}
+	for _, test := range tests {
+		if got := parseProfileDetails(test.output); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: 解析结果 %+v，期望 %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestExtractProfiles(t *testing.T) {
+	output := `
+Profiles on interface Wi-Fi:
+
+Group policy profiles (read only)
+---------------------------------
+    <None>
+
+User profiles
+-------------
+    All User Profile     : HomeNet
+    Current User Profile : Cafe: 2F
+    所有用户配置文件 : 办公室
+`
+	want := []savedProfileRef{
+		{Name: "HomeNet", Scope: ScopeAllUsers},
+		{Name: "Cafe: 2F", Scope: ScopeCurrentUser},
+		{Name: "办公室", Scope: ScopeAllUsers},
+	}
+	if got := extractProfiles(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("extractProfiles = %+v，期望 %+v", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+package wifi
+
+import (
+	"WifiSOS/utils"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// DefaultQRImageSize 写入PNG时的默认边长（像素）
+const DefaultQRImageSize = 512
+
+// qrLevels 纠错级别名称，L/M/Q/H分别可恢复约7%/15%/25%/30%的损坏
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// ParseQRLevel 解析纠错级别L、M、Q或H，为空时使用M
+func ParseQRLevel(level string) (qrcode.RecoveryLevel, error) {
+	if level == "" {
+		return qrcode.Medium, nil
+	}
+	if value, ok := qrLevels[strings.ToUpper(level)]; ok {
+		return value, nil
+	}
+	return 0, fmt.Errorf("无效的纠错级别: %s，应为L、M、Q或H", level)
+}
+
+// escapeWiFiQRValue 按WIFI二维码格式转义字段，反斜杠、分号、逗号、双引号和冒号前加反斜杠
+// 值不加双引号，iOS和Android系统相机会把双引号当作值的一部分；值中的双引号转义后不会被当作引号去除
+func escapeWiFiQRValue(value string) string {
+	var escaped strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(`\;,":`, r) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// WiFiQRPayload 生成连接网络的WIFI二维码内容，格式为 WIFI:T:WPA;S:SSID;P:密码;H:true;;
+// WPA3-SAE按WPA类型编码，手机会按接入点实际支持的方式连接；802.1X企业网络不支持
+func WiFiQRPayload(network SavedWiFi) (string, error) {
+	if network.SSID == "" {
+		return "", fmt.Errorf("网络没有SSID")
+	}
+
+	var security string
+	switch auth := network.AuthType(); {
+	case auth == AuthOpen || auth == AuthOWE:
+		security = "nopass"
+	case auth == AuthWEP:
+		security = "WEP"
+	case auth.IsPersonal():
+		security = "WPA"
+	case auth.IsEnterprise():
+		return "", fmt.Errorf("%s 是802.1X企业网络，无法通过二维码分享", network.SSID)
+	default:
+		return "", fmt.Errorf("无法识别 %s 的身份验证方式: %s", network.SSID, network.Authentication)
+	}
+	if security != "nopass" && !network.HasPassword() {
+		return "", fmt.Errorf("%s 的密钥%s", network.SSID, network.KeyStatus)
+	}
+
+	var payload strings.Builder
+	payload.WriteString("WIFI:T:" + security + ";")
+	payload.WriteString("S:" + escapeWiFiQRValue(network.SSID) + ";")
+	if security != "nopass" {
+		payload.WriteString("P:" + escapeWiFiQRValue(network.Password) + ";")
+	}
+	if network.NonBroadcast {
+		payload.WriteString("H:true;")
+	}
+	payload.WriteString(";")
+	return payload.String(), nil
+}
+
+// FindSavedNetwork 按SSID或配置文件名称查找已保存的网络，有多个匹配时优先返回已获取密钥的
+func FindSavedNetwork(networks []SavedWiFi, name string) (SavedWiFi, bool) {
+	var found *SavedWiFi
+	for i := range networks {
+		network := &networks[i]
+		if network.SSID != name && network.ProfileName != name {
+			continue
+		}
+		if found == nil || (!found.HasPassword() && network.HasPassword()) {
+			found = network
+		}
+	}
+	if found == nil {
+		return SavedWiFi{}, false
+	}
+	return *found, true
+}
+
+// NewWiFiQRCode 生成连接网络的二维码
+func NewWiFiQRCode(network SavedWiFi, level qrcode.RecoveryLevel) (*qrcode.QRCode, error) {
+	payload, err := WiFiQRPayload(network)
+	if err != nil {
+		return nil, err
+	}
+	code, err := qrcode.New(payload, level)
+	if err != nil {
+		return nil, fmt.Errorf("生成二维码失败: %v", err)
+	}
+	return code, nil
+}
+
+// FormatQRTerminal 使用半高方块字符在终端中显示二维码，适用于深色背景的终端
+func FormatQRTerminal(code *qrcode.QRCode) string {
+	return code.ToSmallString(false)
+}
+
+// FormatQRSVG 将二维码转换为SVG，每个模块为一个单位，包含四个模块宽的静区
+func FormatQRSVG(code *qrcode.QRCode) string {
+	bitmap := code.Bitmap()
+	size := len(bitmap)
+
+	var output strings.Builder
+	output.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	output.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", size, size))
+	output.WriteString(fmt.Sprintf(`<rect width="%d" height="%d" fill="#fff"/>`+"\n", size, size))
+	output.WriteString(`<path fill="#000" d="`)
+	for y, row := range bitmap {
+		// 同一行中连续的深色模块合并为一个矩形
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			output.WriteString(fmt.Sprintf("M%d %dh%dv1h-%dz", start, y, x-start, x-start))
+		}
+	}
+	output.WriteString(`"/>` + "\n")
+	output.WriteString("</svg>\n")
+	return output.String()
+}
+
+// WriteQRFile 将二维码写入文件，扩展名为.svg时写入SVG，否则写入PNG，没有扩展名时追加.png
+// 二维码包含明文密钥，以独占方式创建仅当前用户可读写的新文件，文件已存在时追加序号，返回实际写入的路径
+func WriteQRFile(code *qrcode.QRCode, path string, size int) (string, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	var data []byte
+	if strings.EqualFold(ext, "svg") {
+		data = []byte(FormatQRSVG(code))
+	} else {
+		if size <= 0 {
+			size = DefaultQRImageSize
+		}
+		png, err := code.PNG(size)
+		if err != nil {
+			return "", fmt.Errorf("生成PNG失败: %v", err)
+		}
+		data = png
+		if ext == "" {
+			ext = "png"
+		}
+	}
+	return utils.WriteNewFile(filepath.Dir(path), stem, ext, data)
+}
@@ -0,0 +1,93 @@
+package wifi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+)
+
+func TestWiFiQRRoundTrip(t *testing.T) {
+	tests := []struct {
+		ssid     string
+		password string
+	}{
+		{"HomeNet", "12345678"},
+		{`"Quoted"`, `"1234567"`},
+		{`a;b,c:d\e`, `p;a,s:s\w"d`},
+		{"咖啡馆", "中文密码12345"},
+	}
+	for _, test := range tests {
+		payload, err := WiFiQRPayload(SavedWiFi{SSID: test.ssid, Password: test.password, KeyStatus: KeyPresent, Authentication: "WPA2-Personal"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		network, _, err := ParseWiFiQRPayload(payload)
+		if err != nil {
+			t.Fatalf("ParseWiFiQRPayload(%s): %v", payload, err)
+		}
+		if network.SSID != test.ssid || network.Password != test.password {
+			t.Errorf("%s 解析为 SSID=%q 密码=%q，期望 SSID=%q 密码=%q", payload, network.SSID, network.Password, test.ssid, test.password)
+		}
+	}
+}
+
+func TestParseWiFiQRPayloadQuotes(t *testing.T) {
+	tests := []struct {
+		payload  string
+		ssid     string
+		password string
+	}{
+		// 部分生成器为值添加的双引号
+		{`WIFI:T:WPA;S:"HomeNet";P:"12345678";;`, "HomeNet", "12345678"},
+		// 转义的双引号属于值本身
+		{`WIFI:T:WPA;S:HomeNet;P:\"1234567\";;`, "HomeNet", `"1234567"`},
+		{`WIFI:T:WPA;S:"HomeNet\"";P:\"12345678";;`, `HomeNet"`, `"12345678"`},
+		{`WIFI:T:WPA;S:";P:"x"y";;`, `"`, `x"y`},
+	}
+	for _, test := range tests {
+		network, _, err := ParseWiFiQRPayload(test.payload)
+		if err != nil {
+			t.Fatalf("ParseWiFiQRPayload(%s): %v", test.payload, err)
+		}
+		if network.SSID != test.ssid || network.Password != test.password {
+			t.Errorf("%s 解析为 SSID=%q 密码=%q，期望 SSID=%q 密码=%q", test.payload, network.SSID, network.Password, test.ssid, test.password)
+		}
+	}
+}
+
+func TestWriteQRFileExclusive(t *testing.T) {
+	code, err := qrcode.New("WIFI:T:WPA;S:HomeNet;P:12345678;;", qrcode.Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "wifi.svg")
+	if err := os.WriteFile(existing, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := WriteQRFile(code, existing, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != filepath.Join(dir, "wifi_2.svg") {
+		t.Errorf("写入路径 = %s，期望 wifi_2.svg", written)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep" {
+		t.Error("已有文件被覆盖")
+	}
+	info, err := os.Stat(written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("新文件权限 = %v，期望 0600", info.Mode().Perm())
+	}
+
+	written, err = WriteQRFile(code, filepath.Join(dir, "wifi"), 64)
+	if err != nil || written != filepath.Join(dir, "wifi.png") {
+		t.Errorf("没有扩展名时写入 %s, %v，期望 wifi.png", written, err)
+	}
+}
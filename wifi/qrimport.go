@@ -0,0 +1,167 @@
+package wifi
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	zxingqr "github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// wifiQRPrefix WIFI二维码内容的前缀
+const wifiQRPrefix = "WIFI:"
+
+// DecodeQRImage 从PNG、JPEG或GIF图片中识别二维码，返回其中的文本
+func DecodeQRImage(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("读取图片失败: %v", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return "", fmt.Errorf("解码图片失败: %v", err)
+	}
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", fmt.Errorf("处理图片失败: %v", err)
+	}
+
+	// 拍摄的照片中二维码通常不在正中且有透视变形，使用更彻底的检测
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+	result, err := zxingqr.NewQRCodeReader().Decode(bitmap, hints)
+	if err != nil {
+		return "", fmt.Errorf("未能识别图片中的二维码: %v", err)
+	}
+	return result.GetText(), nil
+}
+
+// splitWiFiQRFields 按未转义的分号拆分字段，返回的键为大写，值已去除转义
+// 部分生成器为十六进制形式的值添加双引号，只去除值两端未转义的双引号，转义的双引号属于值本身
+func splitWiFiQRFields(payload string) map[string]string {
+	fields := make(map[string]string)
+	var current strings.Builder
+	quotes := make(map[int]bool) // 当前字段中未转义双引号的字节位置
+	escaped := false
+	flush := func() {
+		field := current.String()
+		current.Reset()
+		defer clear(quotes)
+		// 键中不会出现转义字符，值中的冒号已在拆分前去除转义，因此按第一个冒号拆分
+		key, value, found := strings.Cut(field, ":")
+		if !found || key == "" {
+			return
+		}
+		start, end := len(key)+1, len(field)-1
+		if end > start && quotes[start] && quotes[end] {
+			value = field[start+1 : end]
+		}
+		fields[strings.ToUpper(key)] = value
+	}
+	for _, r := range payload {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ';':
+			flush()
+		default:
+			if r == '"' {
+				quotes[current.Len()] = true
+			}
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		flush()
+	}
+	return fields
+}
+
+// ParseWiFiQRPayload 解析 WIFI:T:WPA;S:SSID;P:密码;H:true;; 格式的二维码内容
+// 返回的警告说明二维码中无法保存到SavedWiFi的信息
+func ParseWiFiQRPayload(payload string) (SavedWiFi, []string, error) {
+	payload = strings.TrimSpace(payload)
+	if len(payload) < len(wifiQRPrefix) || !strings.EqualFold(payload[:len(wifiQRPrefix)], wifiQRPrefix) {
+		return SavedWiFi{}, nil, fmt.Errorf("不是WiFi二维码: %s", payload)
+	}
+	fields := splitWiFiQRFields(payload[len(wifiQRPrefix):])
+
+	ssid := fields["S"]
+	if ssid == "" {
+		return SavedWiFi{}, nil, fmt.Errorf("二维码中没有SSID")
+	}
+	network := SavedWiFi{
+		SSID:           ssid,
+		ProfileName:    ssid,
+		ConnectionMode: ConnectionAuto,
+		NonBroadcast:   strings.EqualFold(fields["H"], "true"),
+		NetworkType:    "infrastructure",
+	}
+	password := fields["P"]
+
+	var warnings []string
+	security := strings.ToUpper(fields["T"])
+	switch {
+	case security == "" || security == "NOPASS":
+		network.Authentication = "Open"
+		network.KeyStatus = KeyAbsent
+	case security == "WEP":
+		network.Authentication, network.Cipher = "WEP", "WEP"
+		network.KeyType = "networkKey"
+	case security == "SAE" || security == "WPA3":
+		network.Authentication, network.Cipher = "WPA3-SAE", "CCMP"
+		network.KeyType = "passPhrase"
+	case strings.Contains(security, "EAP"):
+		network.Authentication, network.Cipher = "WPA2-EAP", "CCMP"
+		network.KeyStatus = KeyEnterprise
+		network.Enterprise = &EnterpriseConfig{
+			EAPMethod:   eapMethodDisplay(fields["E"]),
+			InnerMethod: eapMethodDisplay(fields["PH2"]),
+		}
+		if fields["I"] != "" || fields["A"] != "" || password != "" {
+			warnings = append(warnings, "二维码中的身份和密码不会保存到配置中，连接时需要手动输入")
+		}
+		warnings = append(warnings, "二维码不包含服务器证书验证设置")
+		password = ""
+	case strings.HasPrefix(security, "WPA"):
+		// R:1表示关闭过渡模式，接入点只允许WPA3
+		network.Authentication, network.Cipher = "WPA2-PSK", "CCMP"
+		if fields["R"] == "1" {
+			network.Authentication = "WPA3-SAE"
+		}
+		network.KeyType = "passPhrase"
+	default:
+		return SavedWiFi{}, nil, fmt.Errorf("无法识别的加密类型: %s", fields["T"])
+	}
+
+	if network.KeyStatus == KeyUnknown {
+		if password == "" {
+			return SavedWiFi{}, nil, fmt.Errorf("%s 网络的二维码中没有密码", security)
+		}
+		RegisterSecret(password)
+		network.Password = password
+		network.KeyStatus = KeyPresent
+		if len(password) == 64 && isHexString(password) {
+			network.KeyType = "networkKey"
+		}
+	}
+	return network, warnings, nil
+}
+
+// ImportQRImage 识别图片中的WiFi二维码并转换为SavedWiFi记录
+func ImportQRImage(path string) (SavedWiFi, []string, error) {
+	payload, err := DecodeQRImage(path)
+	if err != nil {
+		return SavedWiFi{}, nil, err
+	}
+	return ParseWiFiQRPayload(payload)
+}
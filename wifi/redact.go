@@ -0,0 +1,141 @@
+package wifi
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// minSecretLength 短于此长度的密钥不登记，避免把普通文本当作密钥替换
+const minSecretLength = 4
+
+// MaskSecret 返回密钥的掩码形式，只保留首尾字符并标注长度，如 h******2 (8个字符)
+// 不超过4个字符的密钥全部隐藏
+func MaskSecret(secret string) string {
+	runes := []rune(secret)
+	n := len(runes)
+	if n == 0 {
+		return ""
+	}
+	if n <= 4 {
+		return fmt.Sprintf("%s (%d个字符)", strings.Repeat("*", n), n)
+	}
+	return fmt.Sprintf("%c%s%c (%d个字符)", runes[0], strings.Repeat("*", n-2), runes[n-1], n)
+}
+
+// ShowSecret reveal为true时返回密钥原文，否则返回掩码
+func ShowSecret(secret string, reveal bool) string {
+	if reveal {
+		return secret
+	}
+	return MaskSecret(secret)
+}
+
+// secretRedactor 记录已知的密钥，在日志和错误信息输出前将其替换为掩码
+type secretRedactor struct {
+	mu       sync.Mutex
+	reveal   bool
+	secrets  map[string]bool
+	replacer *strings.Replacer // 登记新密钥后置为nil，使用时重建
+}
+
+// redactor 进程内共用的密钥登记表，读取配置、识别二维码和爆破时登记密钥
+var redactor = &secretRedactor{secrets: make(map[string]bool)}
+
+// SetRevealSecrets 设置日志和错误信息中是否显示密钥原文，默认显示掩码
+func SetRevealSecrets(reveal bool) {
+	redactor.mu.Lock()
+	defer redactor.mu.Unlock()
+	redactor.reveal = reveal
+}
+
+// RegisterSecret 登记密钥，之后输出的日志和错误信息中出现该密钥时会被替换为掩码
+func RegisterSecret(secret string) {
+	if len([]rune(secret)) < minSecretLength {
+		return
+	}
+	redactor.mu.Lock()
+	defer redactor.mu.Unlock()
+	if !redactor.secrets[secret] {
+		redactor.secrets[secret] = true
+		redactor.replacer = nil
+	}
+}
+
+// registerNetworkSecrets 登记已获取到的网络密钥
+func registerNetworkSecrets(networks []SavedWiFi) {
+	for _, network := range networks {
+		if network.HasPassword() {
+			RegisterSecret(network.Password)
+		}
+	}
+}
+
+// Redact 将文本中已登记的密钥替换为掩码
+func Redact(text string) string {
+	redactor.mu.Lock()
+	defer redactor.mu.Unlock()
+	if redactor.reveal || len(redactor.secrets) == 0 {
+		return text
+	}
+	if redactor.replacer == nil {
+		// 较长的密钥优先替换，避免包含其他密钥的密钥只被替换一部分
+		secrets := make([]string, 0, len(redactor.secrets))
+		for secret := range redactor.secrets {
+			secrets = append(secrets, secret)
+		}
+		sort.Slice(secrets, func(i, j int) bool {
+			if len(secrets[i]) != len(secrets[j]) {
+				return len(secrets[i]) > len(secrets[j])
+			}
+			return secrets[i] < secrets[j]
+		})
+		pairs := make([]string, 0, len(secrets)*2)
+		for _, secret := range secrets {
+			pairs = append(pairs, secret, MaskSecret(secret))
+		}
+		redactor.replacer = strings.NewReplacer(pairs...)
+	}
+	return redactor.replacer.Replace(text)
+}
+
+// RedactError 返回已将密钥替换为掩码的错误，err为nil时返回nil
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s", Redact(err.Error()))
+}
+
+// logOutput 日志的输出位置，结构化输出时设置为标准错误，避免与结果混在一起
+var logOutput io.Writer = os.Stdout
+
+// SetLogOutput 设置扫描、读取配置和爆破过程中日志的输出位置
+func SetLogOutput(w io.Writer) {
+	logOutput = w
+}
+
+// logf 输出日志，已登记的密钥会被替换为掩码
+func logf(format string, args ...interface{}) {
+	fmt.Fprint(logOutput, Redact(fmt.Sprintf(format, args...)))
+}
+
+// warnings 运行过程中产生的警告，保存结果时写入结果文件
+var warnings []string
+
+// warnf 输出警告并记录下来，已登记的密钥会被替换为掩码
+func warnf(format string, args ...interface{}) {
+	message := Redact(fmt.Sprintf(format, args...))
+	warnings = append(warnings, message)
+	fmt.Fprintf(logOutput, "警告: %s\n", message)
+}
+
+// TakeWarnings 返回并清空已记录的警告
+func TakeWarnings() []string {
+	taken := warnings
+	warnings = nil
+	return taken
+}
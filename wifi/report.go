@@ -0,0 +1,515 @@
+package wifi
+
+import (
+	"WifiSOS/utils"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 密钥审计、卫生检查和快照比较结果的架构
+const (
+	AuditSchema   = "wifisos.audit/v1"
+	HygieneSchema = "wifisos.hygiene/v1"
+	DiffSchema    = "wifisos.diff/v1"
+)
+
+// ReportFormats report命令支持的格式，md是markdown的简写
+var ReportFormats = []string{OutputText, OutputMarkdown, "md", OutputCSV, OutputHTML}
+
+// ScanPayload 返回保存到结果文件的扫描结果
+func ScanPayload(networks []WiFiNetwork) []ScanRecord {
+	records := []ScanRecord{}
+	for _, network := range networks {
+		records = append(records, NewScanRecord(network))
+	}
+	return records
+}
+
+// SavedPayload 返回保存到结果文件的已保存网络，reveal为false时只保存密钥掩码
+func SavedPayload(networks []SavedWiFi, reveal bool) []SavedRecord {
+	records := []SavedRecord{}
+	for _, network := range networks {
+		records = append(records, NewSavedRecord(network, reveal))
+	}
+	return records
+}
+
+// CrackTimeRecord 按某一PBKDF2速度估算的离线破解时间
+type CrackTimeRecord struct {
+	RatePerSecond float64 `json:"rate_per_second"`
+	Seconds       float64 `json:"seconds"`
+}
+
+// AuditRecord 一个网络的密钥审计结果，密钥原文只在reveal时保存
+type AuditRecord struct {
+	SSID             string            `json:"ssid"`
+	Authentication   string            `json:"authentication"`
+	Password         string            `json:"password,omitempty"`
+	PasswordMask     string            `json:"password_mask"`
+	Strength         int               `json:"strength"` // 0到4，越大越强
+	StrengthLabel    string            `json:"strength_label"`
+	Length           int               `json:"length"`
+	CharClasses      []string          `json:"char_classes"`
+	CharsetEntropy   float64           `json:"charset_entropy"`
+	Entropy          float64           `json:"entropy"`
+	Findings         []string          `json:"findings"` // 只记录模式类型和位置，不包含密钥片段
+	InWordlist       bool              `json:"in_wordlist"`
+	BreachChecked    bool              `json:"breach_checked"`
+	BreachCount      int               `json:"breach_count"`
+	OfflineCrackable bool              `json:"offline_crackable"`
+	CrackTimes       []CrackTimeRecord `json:"crack_times"`
+}
+
+// AuditPayload 保存到结果文件的密钥审计结果，架构为wifisos.audit/v1
+type AuditPayload struct {
+	Audits  []AuditRecord `json:"audits"`
+	Skipped []SavedRecord `json:"skipped"` // 开放网络、企业网络等未审计的网络
+}
+
+// NewAuditPayload 将密钥审计结果转换为结果文件的载荷
+func NewAuditPayload(audits []PassphraseAudit, skipped []SavedWiFi, reveal bool) AuditPayload {
+	payload := AuditPayload{Audits: []AuditRecord{}, Skipped: SavedPayload(skipped, reveal)}
+	for _, audit := range audits {
+		record := AuditRecord{
+			SSID:             audit.SSID,
+			Authentication:   audit.Authentication,
+			PasswordMask:     MaskSecret(audit.Password),
+			Strength:         audit.Strength,
+			StrengthLabel:    audit.StrengthLabel(),
+			Length:           audit.Length,
+			CharClasses:      append([]string{}, audit.CharClasses...),
+			CharsetEntropy:   audit.CharsetEntropy,
+			Entropy:          audit.Entropy,
+			Findings:         audit.FindingTexts(false),
+			InWordlist:       audit.InWordlist,
+			BreachChecked:    audit.BreachChecked,
+			BreachCount:      audit.BreachCount,
+			OfflineCrackable: audit.OfflineCrackable,
+			CrackTimes:       []CrackTimeRecord{},
+		}
+		if reveal {
+			record.Password = audit.Password
+		}
+		for _, crack := range audit.CrackTimes {
+			record.CrackTimes = append(record.CrackTimes, CrackTimeRecord{RatePerSecond: crack.Rate, Seconds: crack.Seconds})
+		}
+		payload.Audits = append(payload.Audits, record)
+	}
+	return payload
+}
+
+func (r AuditRecord) outputFields() []outputField {
+	breach := ""
+	if r.BreachChecked {
+		breach = strconv.Itoa(r.BreachCount)
+	}
+	var crackTimes []string
+	for _, crack := range r.CrackTimes {
+		crackTimes = append(crackTimes, fmt.Sprintf("%s: %s", formatRate(crack.RatePerSecond), formatCrackTime(crack.Seconds)))
+	}
+	return []outputField{
+		{"ssid", "SSID", r.SSID},
+		{"authentication", "身份验证", r.Authentication},
+		{"strength_label", "强度", r.StrengthLabel},
+		{"password", "密钥", r.Password},
+		{"password_mask", "密钥掩码", r.PasswordMask},
+		{"length", "长度", strconv.Itoa(r.Length)},
+		{"char_classes", "字符类别", strings.Join(r.CharClasses, ";")},
+		{"charset_entropy", "字符集熵(位)", strconv.FormatFloat(r.CharsetEntropy, 'f', 1, 64)},
+		{"entropy", "有效熵(位)", strconv.FormatFloat(r.Entropy, 'f', 1, 64)},
+		{"in_wordlist", "在字典中", strconv.FormatBool(r.InWordlist)},
+		{"breach_count", "泄露次数", breach},
+		{"crack_times", "预计离线破解时间", strings.Join(crackTimes, ";")},
+		{"findings", "问题", strings.Join(r.Findings, ";")},
+	}
+}
+
+func (f HygieneFinding) outputFields() []outputField {
+	return []outputField{
+		{"kind", "类型", f.Label()},
+		{"ssid", "SSID", f.SSID},
+		{"profiles", "配置文件", strings.Join(f.Profiles, ";")},
+		{"detail", "说明", f.Detail},
+		{"commands", "修复命令", strings.Join(f.Commands, ";")},
+		{"warnings", "警告", strings.Join(f.Warnings, ";")},
+	}
+}
+
+// diffRecord 快照比较结果中的一项变化，新增和删除的配置文件各占一行，变化的配置文件每个字段占一行
+type diffRecord struct {
+	Change      string
+	ProfileName string
+	SSID        string
+	Field       string
+	Old         string
+	New         string
+}
+
+func (r diffRecord) outputFields() []outputField {
+	return []outputField{
+		{"change", "变化", r.Change},
+		{"profile_name", "配置文件", r.ProfileName},
+		{"ssid", "SSID", r.SSID},
+		{"field", "字段", r.Field},
+		{"old", "旧值", r.Old},
+		{"new", "新值", r.New},
+	}
+}
+
+// diffRecords 将快照比较结果展开为记录
+func diffRecords(diff *SnapshotDiff) []outputRecord {
+	var records []outputRecord
+	for _, network := range diff.Added {
+		records = append(records, diffRecord{Change: "新增", ProfileName: network.ProfileName, SSID: network.SSID, New: describeSnapshotNetwork(network)})
+	}
+	for _, network := range diff.Removed {
+		records = append(records, diffRecord{Change: "删除", ProfileName: network.ProfileName, SSID: network.SSID, Old: describeSnapshotNetwork(network)})
+	}
+	for _, change := range diff.Changed {
+		for _, field := range change.Changes {
+			records = append(records, diffRecord{
+				Change:      "变化",
+				ProfileName: change.ProfileName,
+				SSID:        change.SSID,
+				Field:       field.Field,
+				Old:         displayChangeValue(field.Old),
+				New:         displayChangeValue(field.New),
+			})
+		}
+	}
+	return records
+}
+
+// network 将扫描记录还原为扫描到的网络
+func (r ScanRecord) network() WiFiNetwork {
+	network := WiFiNetwork{
+		SSID:          r.SSID,
+		BSSID:         r.BSSID,
+		Security:      r.Security,
+		Band:          r.Band,
+		ChannelWidth:  r.ChannelWidthMHz,
+		CenterChannel: r.CenterChannel,
+		Class:         r.Class,
+	}
+	if r.SignalPercent != nil {
+		network.Signal = fmt.Sprintf("%d%%", *r.SignalPercent)
+	}
+	if r.Channel != 0 {
+		network.Channel = strconv.Itoa(r.Channel)
+	}
+	return network
+}
+
+// displaySecret 返回记录中密钥的显示文本，reveal且保存了原文时为原文，否则为掩码
+func displaySecret(password string, mask string, reveal bool) string {
+	if reveal && password != "" {
+		return password
+	}
+	return mask
+}
+
+// network 将已保存网络的记录还原，Password为密钥的显示文本
+func (r SavedRecord) network(reveal bool) SavedWiFi {
+	network := SavedWiFi{
+		SSID:             r.SSID,
+		Password:         displaySecret(r.Password, r.PasswordMask, reveal),
+		KeyStatus:        parseKeyStatusName(r.KeyStatus),
+		KeyError:         r.KeyError,
+		ProfileName:      r.ProfileName,
+		Scope:            r.Scope,
+		Authentication:   r.Authentication,
+		Cipher:           r.Cipher,
+		KeyType:          r.KeyType,
+		ConnectionMode:   r.ConnectionMode,
+		NonBroadcast:     r.NonBroadcast,
+		AutoSwitch:       r.AutoSwitch,
+		MACRandomization: r.MACRandomization,
+		Cost:             r.Cost,
+		NetworkType:      r.NetworkType,
+		RadioType:        r.RadioType,
+	}
+	if e := r.Enterprise; e != nil {
+		network.Enterprise = &EnterpriseConfig{
+			EAPMethod:        e.EAPMethod,
+			InnerMethod:      e.InnerMethod,
+			ValidationKnown:  e.ServerValidation != nil,
+			PromptDisabled:   e.PromptDisabled,
+			ServerNames:      e.ServerNames,
+			TrustedRootCAs:   e.TrustedRootCAs,
+			CredentialSource: e.CredentialSource,
+		}
+		if e.ServerValidation != nil {
+			network.Enterprise.ServerValidation = *e.ServerValidation
+		}
+	}
+	return network
+}
+
+// audit 将审计记录还原，Password为密钥的显示文本
+func (r AuditRecord) audit(reveal bool) PassphraseAudit {
+	audit := PassphraseAudit{
+		SSID:             r.SSID,
+		Authentication:   r.Authentication,
+		Password:         displaySecret(r.Password, r.PasswordMask, reveal),
+		Length:           r.Length,
+		CharClasses:      r.CharClasses,
+		CharsetEntropy:   r.CharsetEntropy,
+		Entropy:          r.Entropy,
+		InWordlist:       r.InWordlist,
+		BreachChecked:    r.BreachChecked,
+		BreachCount:      r.BreachCount,
+		OfflineCrackable: r.OfflineCrackable,
+		Strength:         r.Strength,
+	}
+	for _, finding := range r.Findings {
+		audit.Findings = append(audit.Findings, AuditFinding{Label: finding})
+	}
+	for _, crack := range r.CrackTimes {
+		audit.CrackTimes = append(audit.CrackTimes, CrackTime{Rate: crack.RatePerSecond, Seconds: crack.Seconds})
+	}
+	return audit
+}
+
+// reportContent 从结果文件中读取的报告内容
+type reportContent struct {
+	title     string
+	text      string // 原有的中文文本格式
+	prototype outputRecord
+	records   []outputRecord
+	payload   interface{} // 解析后的载荷，密钥已按reveal处理
+}
+
+// loadReportContent 按载荷架构解析结果文件，reveal为false时不输出密钥原文
+func loadReportContent(envelope *utils.Envelope, reveal bool) (*reportContent, error) {
+	payload := []byte(envelope.Payload)
+	decode := func(v interface{}) error {
+		if err := json.Unmarshal(payload, v); err != nil {
+			return fmt.Errorf("解析结果载荷失败: %v", err)
+		}
+		return nil
+	}
+
+	switch envelope.PayloadSchema {
+	case ScanSchema:
+		var items []ScanRecord
+		if err := decode(&items); err != nil {
+			return nil, err
+		}
+		content := &reportContent{title: "WiFi扫描结果", prototype: ScanRecord{}, payload: items}
+		var networks []WiFiNetwork
+		for _, item := range items {
+			networks = append(networks, item.network())
+			content.records = append(content.records, item)
+		}
+		content.text = FormatNetworksResult(networks)
+		return content, nil
+	case SavedSchema:
+		var items []SavedRecord
+		if err := decode(&items); err != nil {
+			return nil, err
+		}
+		content := &reportContent{title: "已保存的WiFi网络", prototype: SavedRecord{}}
+		var networks []SavedWiFi
+		for i, item := range items {
+			networks = append(networks, item.network(reveal))
+			if !reveal {
+				items[i].Password = ""
+			}
+			content.records = append(content.records, items[i])
+		}
+		content.payload = items
+		content.text = FormatSavedNetworksResult(networks, true)
+		return content, nil
+	case BruteSchema:
+		var item BruteRecord
+		if err := decode(&item); err != nil {
+			return nil, err
+		}
+		result := &BruteForceResult{
+			SSID:        item.SSID,
+			TestedCount: item.TestedCount,
+			Success:     item.Success,
+			Password:    displaySecret(item.Password, item.PasswordMask, reveal),
+			ElapsedTime: time.Duration(item.ElapsedSeconds * float64(time.Second)),
+		}
+		// 保存时已按掩码写入的尝试密码保持不变
+		for i, password := range item.FailedAttempts {
+			if !reveal && !envelope.Redacted {
+				item.FailedAttempts[i] = MaskSecret(password)
+			}
+			result.FailedAttempts = append(result.FailedAttempts, item.FailedAttempts[i])
+		}
+		if !reveal {
+			item.Password = ""
+		}
+		return &reportContent{
+			title:     "WiFi密码爆破结果",
+			text:      FormatBruteForceResult(result, true),
+			prototype: BruteRecord{},
+			records:   []outputRecord{item},
+			payload:   item,
+		}, nil
+	case AuditSchema:
+		var items AuditPayload
+		if err := decode(&items); err != nil {
+			return nil, err
+		}
+		content := &reportContent{title: "已保存网络密钥强度审计", prototype: AuditRecord{}}
+		var audits []PassphraseAudit
+		var skipped []SavedWiFi
+		for i, item := range items.Audits {
+			audits = append(audits, item.audit(reveal))
+			if !reveal {
+				items.Audits[i].Password = ""
+			}
+			content.records = append(content.records, items.Audits[i])
+		}
+		for i, item := range items.Skipped {
+			skipped = append(skipped, item.network(reveal))
+			if !reveal {
+				items.Skipped[i].Password = ""
+			}
+		}
+		content.payload = items
+		content.text = FormatPassphraseAuditResult(audits, skipped, true)
+		return content, nil
+	case HygieneSchema:
+		var report HygieneReport
+		if err := decode(&report); err != nil {
+			return nil, err
+		}
+		content := &reportContent{title: "已保存网络卫生检查", text: FormatHygieneReport(&report), prototype: HygieneFinding{}, payload: &report}
+		for _, finding := range report.Findings {
+			content.records = append(content.records, finding)
+		}
+		return content, nil
+	case DiffSchema:
+		var diff SnapshotDiff
+		if err := decode(&diff); err != nil {
+			return nil, err
+		}
+		if diff.Old == nil || diff.New == nil {
+			return nil, fmt.Errorf("解析结果载荷失败: 缺少快照信息")
+		}
+		return &reportContent{
+			title:     "已保存网络配置变化",
+			text:      FormatSnapshotDiff(&diff),
+			prototype: diffRecord{},
+			records:   diffRecords(&diff),
+			payload:   &diff,
+		}, nil
+	}
+	return nil, fmt.Errorf("不支持的结果载荷架构: %s", envelope.PayloadSchema)
+}
+
+// formatCommandLine 返回命令行的显示文本，包含空白的参数加引号
+func formatCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// envelopeFields 返回结果文件中运行信息的显示字段，警告单独显示
+func envelopeFields(envelope *utils.Envelope) []outputField {
+	fields := []outputField{
+		{"command", "命令", formatCommandLine(envelope.Command)},
+		{"version", "版本", strings.TrimSpace(envelope.Tool + " " + envelope.Version)},
+		{"host", "主机", fmt.Sprintf("%s (%s)", envelope.Host, envelope.OS)},
+		{"interface", "网卡", envelope.Interface},
+		{"backend", "数据来源", envelope.Backend},
+		{"started", "开始时间", envelope.Started.Local().Format("2006-01-02 15:04:05")},
+		{"finished", "结束时间", fmt.Sprintf("%s（耗时 %.1f秒）",
+			envelope.Finished.Local().Format("2006-01-02 15:04:05"), envelope.Duration().Seconds())},
+		{"payload_schema", "载荷架构", envelope.PayloadSchema},
+	}
+	if envelope.Redacted {
+		fields = append(fields, outputField{"redacted", "密钥", "保存时未使用 --reveal，结果中只有密钥掩码"})
+	}
+
+	var shown []outputField
+	for _, field := range fields {
+		if field.Value != "" {
+			shown = append(shown, field)
+		}
+	}
+	return shown
+}
+
+// formatEnvelopeText 输出文本格式的报告，运行信息在前，之后是原有的结果文本
+func formatEnvelopeText(envelope *utils.Envelope, content *reportContent) string {
+	var output strings.Builder
+	output.WriteString("=== WifiSOS 运行记录 ===\n")
+	for _, field := range envelopeFields(envelope) {
+		output.WriteString(fmt.Sprintf("%s: %s\n", field.Label, field.Value))
+	}
+	if len(envelope.Warnings) > 0 {
+		output.WriteString("警告:\n")
+		for _, warning := range envelope.Warnings {
+			output.WriteString(fmt.Sprintf("  - %s\n", warning))
+		}
+	}
+	output.WriteString("\n")
+	output.WriteString(content.text)
+	return output.String()
+}
+
+// formatEnvelopeMarkdown 输出Markdown格式的报告
+func formatEnvelopeMarkdown(envelope *utils.Envelope, content *reportContent) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# %s - %s\n\n", content.title, envelope.Finished.Local().Format("2006-01-02 15:04:05")))
+	output.WriteString("## 运行信息\n\n")
+	for _, field := range envelopeFields(envelope) {
+		output.WriteString(fmt.Sprintf("- %s: %s\n", field.Label, escapeMarkdownCell(field.Value)))
+	}
+	if len(envelope.Warnings) > 0 {
+		output.WriteString("\n## 警告\n\n")
+		for _, warning := range envelope.Warnings {
+			output.WriteString(fmt.Sprintf("- %s\n", escapeMarkdownCell(warning)))
+		}
+	}
+	output.WriteString(fmt.Sprintf("\n## 结果\n\n共 %d 条记录\n\n", len(content.records)))
+	writeMarkdownTable(&output, content.records)
+	return output.String()
+}
+
+// RenderReport 根据保存的结果文件重新生成报告，不需要重新扫描
+// 多个结果文件的文本和Markdown报告依次连接，HTML报告合并为一个页面，CSV只支持单个结果文件
+// reveal为false或保存时未使用 --reveal 时只输出密钥掩码
+func RenderReport(envelopes []*utils.Envelope, format string, reveal bool) (string, error) {
+	if len(envelopes) == 0 {
+		return "", fmt.Errorf("没有结果文件")
+	}
+	if format == OutputHTML {
+		return RenderHTMLReport(envelopes, reveal)
+	}
+	if format == OutputCSV && len(envelopes) > 1 {
+		return "", fmt.Errorf("CSV报告只支持单个结果文件")
+	}
+
+	var reports []string
+	for _, envelope := range envelopes {
+		content, err := loadReportContent(envelope, reveal && !envelope.Redacted)
+		if err != nil {
+			return "", err
+		}
+		switch format {
+		case OutputText, "":
+			reports = append(reports, formatEnvelopeText(envelope, content))
+		case OutputMarkdown, "md":
+			reports = append(reports, formatEnvelopeMarkdown(envelope, content))
+		case OutputCSV:
+			return renderOutputCSV(content.prototype, content.records)
+		default:
+			return "", fmt.Errorf("不支持的报告格式: %s", format)
+		}
+	}
+	return strings.Join(reports, "\n"), nil
+}
@@ -0,0 +1,78 @@
+package wifi
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// 已保存网络的数据来源
+const (
+	SourceNetsh          = "netsh"   // 本机netsh
+	SourceWindowsXML     = "xml"     // 导出的Windows WLAN配置文件XML目录
+	SourceNetworkManager = "nm"      // NetworkManager keyfile目录
+	SourceWpaSupplicant  = "wpa"     // wpa_supplicant.conf
+	SourceAndroid        = "android" // Android WifiConfigStore.xml
+	SourceOpenWrt        = "openwrt" // OpenWrt /etc/config/wireless
+)
+
+// DefaultSavedSource 返回当前系统默认的已保存网络来源
+// Linux上优先使用NetworkManager，不存在时使用wpa_supplicant
+func DefaultSavedSource() string {
+	if runtime.GOOS != "linux" {
+		return SourceNetsh
+	}
+	if _, err := os.Stat(DefaultNetworkManagerDir); err == nil {
+		return SourceNetworkManager
+	}
+	return SourceWpaSupplicant
+}
+
+// LoadSavedNetworks 从指定来源加载已保存的网络，path为空时使用该来源的默认位置
+// 获取到的密钥会被登记，之后的日志和错误信息中只显示掩码
+func LoadSavedNetworks(source string, path string) ([]SavedWiFi, error) {
+	networks, err := loadSavedNetworks(source, path)
+	if err != nil {
+		return nil, err
+	}
+	registerNetworkSecrets(networks)
+	return networks, nil
+}
+
+// loadSavedNetworks 按来源读取已保存的网络
+func loadSavedNetworks(source string, path string) ([]SavedWiFi, error) {
+	if source == "" {
+		source = DefaultSavedSource()
+	}
+
+	switch source {
+	case SourceNetsh:
+		return GetSavedNetworks()
+	case SourceWindowsXML:
+		if path == "" {
+			return nil, fmt.Errorf("读取导出的配置文件需要指定目录")
+		}
+		return LoadProfilesFromDir(path)
+	case SourceNetworkManager:
+		if path == "" {
+			path = DefaultNetworkManagerDir
+		}
+		return LoadNetworkManagerProfiles(path)
+	case SourceWpaSupplicant:
+		if path == "" {
+			path = DefaultWpaSupplicantConf
+		}
+		return LoadWpaSupplicantConf(path)
+	case SourceAndroid:
+		if path == "" {
+			path = DefaultAndroidConfigStore
+		}
+		return LoadAndroidConfigStore(path)
+	case SourceOpenWrt:
+		if path == "" {
+			path = DefaultOpenWrtWireless
+		}
+		return LoadOpenWrtWireless(path)
+	}
+	return nil, fmt.Errorf("不支持的数据来源: %s", source)
+}
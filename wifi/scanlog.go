@@ -0,0 +1,177 @@
+package wifi
+
+import (
+	"WifiSOS/utils"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// scanHistoryPatterns 在目录中查找扫描历史时读取的文件
+// 结果文件名可以通过模板修改，因此.json结果文件和.csv信号序列按内容识别，.txt为早期版本保存的文本结果
+var scanHistoryPatterns = []string{"*.json", "*.csv", "wifi_scan_*.txt"}
+
+// errNotScanHistory 文件不是扫描结果或信号序列，在目录中查找时跳过
+var errNotScanHistory = errors.New("不是扫描结果或信号监测序列")
+
+// scanResultTitle 扫描结果文件的标题前缀，后接扫描时间
+const scanResultTitle = "=== WiFi扫描结果 - "
+
+// ScanHistory 表示从历史扫描结果中汇总出的各SSID最近一次出现的时间
+type ScanHistory struct {
+	Files    []string             `json:"files"`     // 读取的扫描结果文件
+	LastSeen map[string]time.Time `json:"last_seen"` // SSID最近一次被扫描到的时间
+}
+
+// Seen 判断SSID在since之后是否被扫描到过
+func (h *ScanHistory) Seen(ssid string, since time.Time) bool {
+	last, ok := h.LastSeen[ssid]
+	return ok && !last.Before(since)
+}
+
+// record 记录SSID在某一时间被扫描到
+func (h *ScanHistory) record(ssid string, seen time.Time) {
+	if ssid == "" {
+		return
+	}
+	if last, ok := h.LastSeen[ssid]; !ok || seen.After(last) {
+		h.LastSeen[ssid] = seen
+	}
+}
+
+// scanHistoryFiles 返回路径下可能是扫描历史的文件，path为文件时直接返回，isDir表示path是目录
+func scanHistoryFiles(path string) (files []string, isDir bool, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if !info.IsDir() {
+		return []string{path}, false, nil
+	}
+
+	for _, pattern := range scanHistoryPatterns {
+		matches, err := filepath.Glob(filepath.Join(path, pattern))
+		if err != nil {
+			return nil, true, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, true, nil
+}
+
+// LoadScanHistory 读取scan命令保存的扫描结果（.json，早期版本为.txt）和信号监测序列（.csv）
+// paths可以是文件或目录，目录中不是扫描历史的文件会被跳过
+func LoadScanHistory(paths ...string) (*ScanHistory, error) {
+	history := &ScanHistory{LastSeen: make(map[string]time.Time)}
+	for _, path := range paths {
+		files, isDir, err := scanHistoryFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取扫描历史失败: %v", err)
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("读取扫描历史失败: %v", err)
+			}
+			switch strings.ToLower(filepath.Ext(file)) {
+			case ".csv":
+				err = history.parseWatchCSV(data)
+			case ".json":
+				err = history.parseScanEnvelope(data)
+			default:
+				info, statErr := os.Stat(file)
+				if statErr != nil {
+					return nil, fmt.Errorf("读取扫描历史失败: %v", statErr)
+				}
+				history.parseScanResult(data, info.ModTime())
+			}
+			if isDir && errors.Is(err, errNotScanHistory) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("解析 %s 失败: %v", file, err)
+			}
+			history.Files = append(history.Files, file)
+		}
+	}
+	return history, nil
+}
+
+// parseScanEnvelope 解析scan命令保存的结果文件，扫描时间取运行结束的时间
+func (h *ScanHistory) parseScanEnvelope(data []byte) error {
+	envelope, err := utils.ParseEnvelope(data)
+	if err != nil || envelope.PayloadSchema != ScanSchema {
+		return errNotScanHistory
+	}
+	var records []ScanRecord
+	if err := json.Unmarshal(envelope.Payload, &records); err != nil {
+		return err
+	}
+	for _, record := range records {
+		h.record(record.SSID, envelope.Finished)
+	}
+	return nil
+}
+
+// parseScanResult 解析扫描结果文本，从详细信息中读取SSID
+// 扫描时间取标题中的时间，标题缺失时使用文件修改时间
+func (h *ScanHistory) parseScanResult(data []byte, modTime time.Time) {
+	scanned := modTime
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, scanResultTitle) {
+			text := strings.TrimSuffix(strings.TrimPrefix(line, scanResultTitle), " ===")
+			if t, err := time.ParseInLocation("2006-01-02 15:04:05", text, time.Local); err == nil {
+				scanned = t
+			}
+			continue
+		}
+		if ssid, found := strings.CutPrefix(line, "  SSID: "); found {
+			h.record(ssid, scanned)
+		}
+	}
+}
+
+// parseWatchCSV 解析信号监测序列，只记录信号可见的采样
+func (h *ScanHistory) parseWatchCSV(data []byte) error {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return fmt.Errorf("%w: %v", errNotScanHistory, err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	timeColumn, hasTime := columns["time"]
+	ssidColumn, hasSSID := columns["ssid"]
+	signalColumn, hasSignal := columns["signal"]
+	if !hasTime || !hasSSID {
+		return fmt.Errorf("%w: 缺少time或ssid列", errNotScanHistory)
+	}
+
+	for _, record := range records[1:] {
+		if hasSignal && record[signalColumn] == "" {
+			continue
+		}
+		seen, err := time.Parse(time.RFC3339, record[timeColumn])
+		if err != nil {
+			continue
+		}
+		h.record(record[ssidColumn], seen)
+	}
+	return nil
+}
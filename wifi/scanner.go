@@ -3,6 +3,7 @@ package wifi
 import (
 	"fmt"
 	"os/exec"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -10,19 +11,23 @@ import (
 
 // WiFiNetwork 表示一个WiFi网络
 type WiFiNetwork struct {
-	SSID     string // 网络名称
-	BSSID    string // MAC地址
-	Signal   string // 信号强度
-	Channel  string // 信道
-	Security string // 安全类型
+	SSID          string // 网络名称
+	BSSID         string // MAC地址
+	Signal        string // 信号强度
+	Channel       string // 信道
+	Security      string // 安全类型
+	Band          string // 频段，为空时根据信道号推断
+	ChannelWidth  int    // 信道宽度（MHz），0表示未知
+	CenterChannel int    // 中心信道，0表示根据信道宽度推算
+	Class         string // 网络分类，为空表示未分类
 }
 
 // String 返回WiFiNetwork的字符串表示
 func (w WiFiNetwork) String() string {
-	return fmt.Sprintf("SSID: %-20s | 信号: %-10s | 信道: %-8s | 安全类型: %-15s | BSSID: %s",
+	return fmt.Sprintf("SSID: %-20s | 信号: %-10s | 信道: %-22s | 安全类型: %-15s | BSSID: %s",
 		w.SSID,
 		w.Signal,
-		w.Channel,
+		w.ChannelInfo().String(),
 		w.Security,
 		w.BSSID)
 }
@@ -35,18 +40,70 @@ func (w WiFiNetwork) IsValid() bool {
 
 // FormatSignal 格式化信号强度显示
 func (w WiFiNetwork) FormatSignal() string {
-	// 移除百分号并尝试解析数字
-	signal := strings.TrimSuffix(w.Signal, "%")
-	if signalNum, err := strconv.Atoi(signal); err == nil {
-		// 将信号强度转换为星号表示
-		stars := signalNum / 20 // 每20%一颗星
-		return strings.Repeat("*", stars) + fmt.Sprintf(" (%s)", w.Signal)
+	if signal, ok := w.SignalValue(); ok {
+		// 使用八级方块字符表示信号强度
+		return string(signalLevel(signal)) + fmt.Sprintf(" (%s)", w.Signal)
 	}
 	return w.Signal
 }
 
-// ScanNetworks 扫描附近的WiFi网络
+// SignalValue 返回信号强度的百分比数值
+func (w WiFiNetwork) SignalValue() (int, bool) {
+	signal := strings.TrimSpace(strings.TrimSuffix(w.Signal, "%"))
+	value, err := strconv.Atoi(signal)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// ChannelNumber 返回信道号数值
+func (w WiFiNetwork) ChannelNumber() (int, bool) {
+	channel, err := strconv.Atoi(strings.TrimSpace(w.Channel))
+	if err != nil {
+		return 0, false
+	}
+	return channel, true
+}
+
+// ChannelInfo 返回网络的信道信息
+func (w WiFiNetwork) ChannelInfo() ChannelInfo {
+	channel, ok := w.ChannelNumber()
+	if !ok {
+		return ChannelInfo{}
+	}
+	return NewChannelInfo(w.Band, channel, w.ChannelWidth, w.CenterChannel)
+}
+
+// SetFrequency 根据频率（MHz）设置频段和信道，用于wpa_cli、iw等以频率表示信道的数据源
+func (w *WiFiNetwork) SetFrequency(freq int) {
+	band, channel := FrequencyChannel(freq)
+	if channel == 0 {
+		return
+	}
+	w.Band = band
+	w.Channel = strconv.Itoa(channel)
+}
+
+// ApplyBeaconIEs 根据Beacon帧中的信息元素补充信道宽度和中心信道
+func (w *WiFiNetwork) ApplyBeaconIEs(ies []byte) {
+	channel, _ := w.ChannelNumber()
+	info := ChannelInfoFromIEs(w.Band, channel, ies)
+	if !info.IsValid() {
+		return
+	}
+	w.Band = info.Band
+	w.Channel = strconv.Itoa(info.Primary)
+	w.ChannelWidth = info.Width
+	w.CenterChannel = info.Center
+}
+
+// ScanNetworks 扫描附近的WiFi网络，Windows上使用netsh，Linux上使用wpa_cli
 func ScanNetworks() ([]WiFiNetwork, error) {
+	if runtime.GOOS == "linux" {
+		return scanWpaCli()
+	}
+
 	// 首先刷新网络列表
 	refreshCmd := exec.Command("netsh", "wlan", "show", "networks", "refresh")
 	_, err := refreshCmd.CombinedOutput()
@@ -62,7 +119,7 @@ func ScanNetworks() ([]WiFiNetwork, error) {
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// 如果失败，尝试不带mode=Bssid参数重试
-		fmt.Printf("使用mode=Bssid扫描失败，尝试基本扫描...\n")
+		logf("使用mode=Bssid扫描失败，尝试基本扫描...\n")
 		cmd = exec.Command("netsh", "wlan", "show", "networks")
 		output, err = cmd.CombinedOutput()
 		if err != nil {
@@ -86,17 +143,51 @@ func ScanNetworks() ([]WiFiNetwork, error) {
 
 	// 验证解析结果
 	if len(networks) == 0 {
-		fmt.Println("警告: 未解析到任何网络信息")
+		warnf("未解析到任何网络信息")
 	}
 
 	return networks, nil
 }
 
+// ScanBackend 扫描使用的系统命令
+var ScanBackend = scanBackend()
+
+// scanBackend 返回当前系统扫描使用的系统命令
+func scanBackend() string {
+	if runtime.GOOS == "linux" {
+		return wpaCliBackend
+	}
+	return "netsh"
+}
+
+// InterfaceName 返回第一个无线网卡的名称，获取失败时返回空字符串
+func InterfaceName() string {
+	if runtime.GOOS == "linux" {
+		return wpaCliInterface()
+	}
+	output, err := exec.Command("netsh", "wlan", "show", "interfaces").CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		if key = strings.TrimSpace(key); key == "Name" || key == "名称" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
 // parseNetshOutput 解析netsh命令的输出
 func parseNetshOutput(output string) []WiFiNetwork {
 	var networks []WiFiNetwork
 	var currentNetwork WiFiNetwork
 	var inBssidSection bool = false
+	// netsh在SSID级别（第一个BSSID之前）给出身份验证和加密方式，同一SSID的每个BSSID都使用它
+	var authentication, encryption string
 
 	lines := strings.Split(output, "\n")
 	for _, line := range lines {
@@ -121,6 +212,7 @@ func parseNetshOutput(output string) []WiFiNetwork {
 					SSID: strings.TrimSpace(parts[1]),
 				}
 			}
+			authentication, encryption = "", ""
 			inBssidSection = false
 		} else if strings.Contains(line, "BSSID") && strings.Contains(line, ":") {
 			// 新的BSSID部分开始
@@ -136,10 +228,24 @@ func parseNetshOutput(output string) []WiFiNetwork {
 			if len(parts) >= 2 {
 				currentNetwork.BSSID = strings.TrimSpace(parts[1])
 			}
+			currentNetwork.Security = networkSecurity(authentication, encryption)
 			inBssidSection = true
-		} else if inBssidSection {
+		} else if !inBssidSection {
+			// SSID级别的属性
+			key, value, found := strings.Cut(line, ":")
+			if !found {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "authentication", "身份验证":
+				authentication = strings.TrimSpace(value)
+			case "encryption", "加密":
+				encryption = strings.TrimSpace(value)
+			}
+		} else {
 			// 在BSSID部分内处理其他属性
 			lowerLine := strings.ToLower(line)
+			key := strings.TrimSpace(strings.SplitN(lowerLine, ":", 2)[0])
 
 			// 处理安全类型 - 检查多种可能的关键词
 			if strings.Contains(lowerLine, "authentication") ||
@@ -157,9 +263,13 @@ func parseNetshOutput(output string) []WiFiNetwork {
 				if len(parts) >= 2 {
 					currentNetwork.Signal = strings.TrimSpace(parts[1])
 				}
-			} else if strings.Contains(lowerLine, "channel") ||
-				strings.Contains(lowerLine, "信道") ||
-				strings.Contains(lowerLine, "频道") {
+			} else if key == "band" || key == "波段" || key == "频段" {
+				parts := strings.SplitN(line, ":", 2)
+				if len(parts) >= 2 {
+					currentNetwork.Band = NormalizeBand(parts[1])
+				}
+			} else if key == "channel" || key == "信道" || key == "频道" {
+				// 精确匹配键名，避免误将"Channel Utilization"等行识别为信道
 				parts := strings.SplitN(line, ":", 2)
 				if len(parts) >= 2 {
 					currentNetwork.Channel = strings.TrimSpace(parts[1])
@@ -173,16 +283,18 @@ func parseNetshOutput(output string) []WiFiNetwork {
 		networks = append(networks, currentNetwork)
 	}
 
-	// 调试输出
-	fmt.Printf("解析到 %d 个WiFi网络\n", len(networks))
-	for i, net := range networks {
-		fmt.Printf("网络 #%d: SSID=%s, BSSID=%s, Signal=%s, Channel=%s, Security=%s\n",
-			i+1, net.SSID, net.BSSID, net.Signal, net.Channel, net.Security)
-	}
-
 	return networks
 }
 
+// networkSecurity 根据SSID级别的身份验证和加密方式返回安全类型
+// 使用WEP加密的网络在netsh中身份验证显示为Open，此时按WEP处理，避免被当作开放网络
+func networkSecurity(authentication string, encryption string) string {
+	if ParseAuthType(authentication) == AuthOpen && strings.Contains(strings.ToUpper(encryption), "WEP") {
+		return "WEP"
+	}
+	return authentication
+}
+
 // FormatNetworksResult 格式化网络扫描结果
 func FormatNetworksResult(networks []WiFiNetwork) string {
 	var result strings.Builder
@@ -208,16 +320,17 @@ func FormatNetworksResult(networks []WiFiNetwork) string {
 	}
 
 	// 添加表头
-	result.WriteString(fmt.Sprintf("%-4s | %-*s | %-15s | %-10s | %-20s | %s\n",
+	result.WriteString(fmt.Sprintf("%-4s | %-*s | %-15s | %-22s | %-20s | %-14s | %s\n",
 		"序号",
 		maxSSIDLen, "SSID",
 		"信号强度",
 		"信道",
 		"安全类型",
+		"分类",
 		"BSSID"))
 
 	// 添加分隔线
-	separatorLen := 4 + 3 + maxSSIDLen + 3 + 15 + 3 + 10 + 3 + 20 + 3 + 17
+	separatorLen := 4 + 3 + maxSSIDLen + 3 + 15 + 3 + 22 + 3 + 20 + 3 + 14 + 3 + 17
 	result.WriteString(strings.Repeat("-", separatorLen) + "\n")
 
 	// 添加网络信息
@@ -228,8 +341,8 @@ func FormatNetworksResult(networks []WiFiNetwork) string {
 			signal = "N/A"
 		}
 
-		channel := network.Channel
-		if channel == "" {
+		channel := network.ChannelInfo().String()
+		if network.Channel == "" {
 			channel = "N/A"
 		}
 
@@ -249,13 +362,19 @@ func FormatNetworksResult(networks []WiFiNetwork) string {
 			signalDisplay = network.FormatSignal()
 		}
 
+		class := network.Class
+		if class == "" {
+			class = "-"
+		}
+
 		// 添加网络信息行
-		result.WriteString(fmt.Sprintf("%-4d | %-*s | %-15s | %-10s | %-20s | %s\n",
+		result.WriteString(fmt.Sprintf("%-4d | %-*s | %-15s | %-22s | %-20s | %-14s | %s\n",
 			i+1,
 			maxSSIDLen, network.SSID,
 			signalDisplay,
 			channel,
 			security,
+			class,
 			bssid))
 	}
 
@@ -267,14 +386,28 @@ func FormatNetworksResult(networks []WiFiNetwork) string {
 		result.WriteString(fmt.Sprintf("  BSSID: %s\n", network.BSSID))
 		result.WriteString(fmt.Sprintf("  信号强度: %s\n", network.Signal))
 		result.WriteString(fmt.Sprintf("  信道: %s\n", network.Channel))
+		if info := network.ChannelInfo(); info.IsValid() {
+			result.WriteString(fmt.Sprintf("  频段: %s\n", info.Band))
+			result.WriteString(fmt.Sprintf("  中心频率: %d MHz (中心信道 %d)\n", info.CenterFrequency, info.Center))
+			if info.Width > 0 {
+				result.WriteString(fmt.Sprintf("  信道宽度: %d MHz\n", info.Width))
+			} else {
+				result.WriteString("  信道宽度: 未知\n")
+			}
+			if info.DFS {
+				result.WriteString("  DFS: 是\n")
+			}
+		}
 		result.WriteString(fmt.Sprintf("  安全类型: %s\n", network.Security))
+		result.WriteString(fmt.Sprintf("  分类: %s\n", ClassLabel(network.Class)))
 		result.WriteString("\n")
 	}
 
 	// 添加注释说明
 	result.WriteString("\n注意:\n")
-	result.WriteString("- 信号强度: * = 20%, ***** = 100%\n")
+	result.WriteString("- 信号强度: " + string(sparkLevels) + " 对应 0% 到 100%\n")
 	result.WriteString("- N/A 表示信息不可用\n")
+	result.WriteString("- 未提供频段信息时根据信道号推断，6GHz网络需要频段信息才能识别\n")
 	result.WriteString("- 某些字段可能因系统限制或权限不足而无法显示\n")
 
 	return result.String()
@@ -0,0 +1,89 @@
+package wifi
+
+import "testing"
+
+// netshNetworksSample 为 netsh wlan show networks mode=bssid 的英文输出
+const netshNetworksSample = `
+Interface name : Wi-Fi
+There are 3 networks currently visible.
+
+SSID 1 : HomeNet
+    Network type            : Infrastructure
+    Authentication          : WPA2-Personal
+    Encryption              : CCMP
+    BSSID 1                 : aa:bb:cc:dd:ee:01
+         Signal             : 92%
+         Radio type         : 802.11ax
+         Band               : 5 GHz
+         Channel            : 36
+         Basic rates (Mbps) : 6 12 24
+         Other rates (Mbps) : 9 18 36 48 54
+    BSSID 2                 : aa:bb:cc:dd:ee:02
+         Signal             : 60%
+         Radio type         : 802.11n
+         Band               : 2.4 GHz
+         Channel            : 6
+         Channel Utilization: 12 (4 %)
+
+SSID 2 : CoffeeShop
+    Network type            : Infrastructure
+    Authentication          : Open
+    Encryption              : None
+    BSSID 1                 : 11:22:33:44:55:66
+         Signal             : 40%
+         Radio type         : 802.11n
+         Channel            : 11
+
+SSID 3 : OldPrinter
+    Network type            : Infrastructure
+    Authentication          : Open
+    Encryption              : WEP
+    BSSID 1                 : 11:22:33:44:55:77
+         Signal             : 20%
+         Channel            : 1
+`
+
+// netshNetworksSampleZH 为中文系统的输出
+const netshNetworksSampleZH = `
+接口名称 : WLAN
+当前有 1 个网络可见。
+
+SSID 1 : 办公室
+    网络类型            : 结构
+    身份验证            : WPA3-个人
+    加密                : CCMP
+    BSSID 1             : aa:bb:cc:dd:ee:03
+         信号           : 80%
+         无线电类型     : 802.11ax
+         波段           : 6 GHz
+         频道           : 37
+`
+
+func TestParseNetshOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []WiFiNetwork
+	}{
+		{"英文", netshNetworksSample, []WiFiNetwork{
+			{SSID: "HomeNet", BSSID: "aa:bb:cc:dd:ee:01", Signal: "92%", Channel: "36", Security: "WPA2-Personal", Band: Band5G},
+			{SSID: "HomeNet", BSSID: "aa:bb:cc:dd:ee:02", Signal: "60%", Channel: "6", Security: "WPA2-Personal", Band: Band2G},
+			{SSID: "CoffeeShop", BSSID: "11:22:33:44:55:66", Signal: "40%", Channel: "11", Security: "Open"},
+			{SSID: "OldPrinter", BSSID: "11:22:33:44:55:77", Signal: "20%", Channel: "1", Security: "WEP"},
+		}},
+		{"中文", netshNetworksSampleZH, []WiFiNetwork{
+			{SSID: "办公室", BSSID: "aa:bb:cc:dd:ee:03", Signal: "80%", Channel: "37", Security: "WPA3-个人", Band: Band6G},
+		}},
+	}
+	for _, test := range tests {
+		got := parseNetshOutput(test.output)
+		if len(got) != len(test.want) {
+			t.Fatalf("%s: 解析到 %d 个网络，期望 %d 个: %+v", test.name, len(got), len(test.want), got)
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: 网络 #%d = %+v，期望 %+v", test.name, i+1, got[i], test.want[i])
+			}
+		}
+	}
+}
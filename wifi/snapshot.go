@@ -0,0 +1,373 @@
+package wifi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// SnapshotVersion 已保存网络快照的格式版本，版本2起密钥指纹改用scrypt派生
+const SnapshotVersion = 2
+
+// 密钥指纹的scrypt参数，N=2^15、r=8、p=1，每次计算约需100毫秒，远慢于WPA的PBKDF2-SHA1(4096次)
+const (
+	fingerprintLogN = 15
+	fingerprintR    = 8
+	fingerprintP    = 1
+)
+
+// SavedSnapshot 表示某一时刻已保存网络的快照，用于比较不同时间或不同机器上的配置
+// 快照不包含密钥原文，只记录密钥指纹，足以判断密钥是否变更
+// 指纹仍可用于离线字典验证，弱密码可被猜出，快照文件应与配置文件同等保管
+type SavedSnapshot struct {
+	Version  int               `json:"version"`
+	Created  time.Time         `json:"created"`
+	Hostname string            `json:"hostname,omitempty"`
+	Source   string            `json:"source,omitempty"`
+	Networks []SnapshotNetwork `json:"networks"`
+}
+
+// SnapshotNetwork 表示快照中的一个已保存网络
+type SnapshotNetwork struct {
+	ProfileName    string `json:"profile_name"`
+	SSID           string `json:"ssid"`
+	Authentication string `json:"authentication,omitempty"`
+	Cipher         string `json:"cipher,omitempty"`
+	KeyType        string `json:"key_type,omitempty"`
+	KeyStatus      string `json:"key_status"`                // present、absent、hidden、error、enterprise或unknown
+	KeyFingerprint string `json:"key_fingerprint,omitempty"` // scrypt(密钥, 盐)的前8字节，见keyFingerprint
+	ConnectionMode string `json:"connection_mode,omitempty"`
+	AutoSwitch     bool   `json:"auto_switch,omitempty"`
+	NonBroadcast   bool   `json:"non_broadcast,omitempty"`
+	Scope          string `json:"scope,omitempty"`
+	EAPMethod      string `json:"eap_method,omitempty"`
+	InnerMethod    string `json:"inner_method,omitempty"`
+	ServerValidate string `json:"server_validation,omitempty"` // 是、否，未获取到时为空
+}
+
+// keyStatusNames 快照和结构化输出中密钥状态的取值，使用英文以便其他程序解析
+var keyStatusNames = map[KeyStatus]string{
+	KeyUnknown:    "unknown",
+	KeyPresent:    "present",
+	KeyAbsent:     "absent",
+	KeyHidden:     "hidden",
+	KeyError:      "error",
+	KeyEnterprise: "enterprise",
+}
+
+// parseKeyStatusName 将快照中的密钥状态转换回KeyStatus
+func parseKeyStatusName(value string) KeyStatus {
+	for status, name := range keyStatusNames {
+		if name == value {
+			return status
+		}
+	}
+	return KeyUnknown
+}
+
+// keyFingerprint 返回密钥指纹，以SSID作为盐，不同网络的相同密钥指纹不同
+// 为了在不同快照和不同机器之间比较，盐只由SSID决定，拿到快照的人可以逐个猜测密码并比对指纹。
+// 使用scrypt使每次猜测的代价高于破解抓到的WPA握手包，但字典中的弱密码仍会被找到
+func keyFingerprint(ssid string, password string) (string, error) {
+	key, err := scrypt.Key([]byte(password), []byte("wifisos-fingerprint\x00"+ssid), 1<<fingerprintLogN, fingerprintR, fingerprintP, 8)
+	if err != nil {
+		return "", fmt.Errorf("计算密钥指纹失败: %v", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// NewSavedSnapshot 根据已保存的网络生成快照
+func NewSavedSnapshot(networks []SavedWiFi, source string) (*SavedSnapshot, error) {
+	hostname, _ := os.Hostname()
+	snapshot := &SavedSnapshot{
+		Version:  SnapshotVersion,
+		Created:  time.Now(),
+		Hostname: hostname,
+		Source:   source,
+		Networks: []SnapshotNetwork{},
+	}
+	for _, network := range networks {
+		item := SnapshotNetwork{
+			ProfileName:    network.ProfileName,
+			SSID:           network.SSID,
+			Authentication: network.Authentication,
+			Cipher:         network.Cipher,
+			KeyType:        network.KeyType,
+			KeyStatus:      keyStatusNames[network.KeyStatus],
+			ConnectionMode: network.ConnectionMode,
+			AutoSwitch:     network.AutoSwitch,
+			NonBroadcast:   network.NonBroadcast,
+			Scope:          network.Scope,
+		}
+		if item.ProfileName == "" {
+			item.ProfileName = network.SSID
+		}
+		if network.HasPassword() {
+			fingerprint, err := keyFingerprint(network.SSID, network.Password)
+			if err != nil {
+				return nil, err
+			}
+			item.KeyFingerprint = fingerprint
+		}
+		if network.Enterprise != nil {
+			item.EAPMethod = network.Enterprise.EAPMethod
+			item.InnerMethod = network.Enterprise.InnerMethod
+			if network.Enterprise.ValidationKnown {
+				item.ServerValidate = formatBool(network.Enterprise.ServerValidation)
+			}
+		}
+		snapshot.Networks = append(snapshot.Networks, item)
+	}
+	return snapshot, nil
+}
+
+// Marshal 将快照转换为JSON
+func (s *SavedSnapshot) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成快照失败: %v", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// LoadSavedSnapshot 读取saved --snapshot保存的快照文件
+func LoadSavedSnapshot(path string) (*SavedSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取快照失败: %v", err)
+	}
+	var snapshot SavedSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("解析快照 %s 失败: %v", path, err)
+	}
+	if snapshot.Version == 1 {
+		return nil, fmt.Errorf("快照 %s 使用旧版SHA-256密钥指纹，请重新生成快照", path)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("不支持的快照版本: %d", snapshot.Version)
+	}
+	return &snapshot, nil
+}
+
+// FieldChange 表示一个字段的变化，值为空表示未设置
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ProfileChange 表示两个快照中同一配置文件的变化
+type ProfileChange struct {
+	ProfileName string        `json:"profile_name"`
+	SSID        string        `json:"ssid"`
+	Changes     []FieldChange `json:"changes"`
+}
+
+// SnapshotDiff 表示两个快照之间的差异
+type SnapshotDiff struct {
+	Old       *SavedSnapshot    `json:"old"`
+	New       *SavedSnapshot    `json:"new"`
+	Added     []SnapshotNetwork `json:"added"`
+	Removed   []SnapshotNetwork `json:"removed"`
+	Changed   []ProfileChange   `json:"changed"`
+	Unchanged int               `json:"unchanged"`
+}
+
+// HasChanges 判断两个快照是否存在差异
+func (d *SnapshotDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// snapshotKeys 按配置文件名称和作用范围索引快照中的网络，同名配置文件按出现顺序区分
+func snapshotKeys(networks []SnapshotNetwork) ([]string, map[string]SnapshotNetwork) {
+	var keys []string
+	index := make(map[string]SnapshotNetwork)
+	for _, network := range networks {
+		base := network.ProfileName + "\x00" + network.Scope
+		key := base
+		for n := 2; ; n++ {
+			if _, exists := index[key]; !exists {
+				break
+			}
+			key = fmt.Sprintf("%s\x00%d", base, n)
+		}
+		keys = append(keys, key)
+		index[key] = network
+	}
+	return keys, index
+}
+
+// describeKeyChange 返回密钥状态变化前后的比较值，状态相同但指纹不同时标记密钥已变更
+// 比较结果以明文保存，不包含指纹
+func describeKeyChange(before SnapshotNetwork, after SnapshotNetwork) (string, string) {
+	oldValue := parseKeyStatusName(before.KeyStatus).String()
+	newValue := parseKeyStatusName(after.KeyStatus).String()
+	if oldValue == newValue && before.KeyFingerprint != after.KeyFingerprint {
+		newValue += " (密钥已变更)"
+	}
+	return oldValue, newValue
+}
+
+// withoutFingerprint 返回去除密钥指纹的网络
+func withoutFingerprint(network SnapshotNetwork) SnapshotNetwork {
+	network.KeyFingerprint = ""
+	return network
+}
+
+// withoutFingerprints 返回去除密钥指纹的快照副本
+func withoutFingerprints(snapshot *SavedSnapshot) *SavedSnapshot {
+	redacted := *snapshot
+	redacted.Networks = make([]SnapshotNetwork, len(snapshot.Networks))
+	for i, network := range snapshot.Networks {
+		redacted.Networks[i] = withoutFingerprint(network)
+	}
+	return &redacted
+}
+
+// diffNetwork 比较同一配置文件在两个快照中的字段
+func diffNetwork(before SnapshotNetwork, after SnapshotNetwork) []FieldChange {
+	var changes []FieldChange
+	compare := func(field string, oldValue string, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	compare("SSID", before.SSID, after.SSID)
+	oldKey, newKey := describeKeyChange(before, after)
+	compare("密钥", oldKey, newKey)
+	compare("身份验证", before.Authentication, after.Authentication)
+	compare("加密方式", before.Cipher, after.Cipher)
+	compare("密钥类型", before.KeyType, after.KeyType)
+	compare("EAP方法", before.EAPMethod, after.EAPMethod)
+	compare("内层认证", before.InnerMethod, after.InnerMethod)
+	compare("验证服务器证书", before.ServerValidate, after.ServerValidate)
+	compare("连接模式", formatConnectionMode(before.ConnectionMode), formatConnectionMode(after.ConnectionMode))
+	compare("自动切换", formatBool(before.AutoSwitch), formatBool(after.AutoSwitch))
+	compare("隐藏网络", formatBool(before.NonBroadcast), formatBool(after.NonBroadcast))
+	return changes
+}
+
+// DiffSnapshots 比较两个快照，返回新增、删除和变化的配置文件
+// 比较结果中的快照和网络均不包含密钥指纹
+func DiffSnapshots(before *SavedSnapshot, after *SavedSnapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{Old: withoutFingerprints(before), New: withoutFingerprints(after)}
+	oldKeys, oldIndex := snapshotKeys(before.Networks)
+	newKeys, newIndex := snapshotKeys(after.Networks)
+
+	for _, key := range oldKeys {
+		if _, exists := newIndex[key]; !exists {
+			diff.Removed = append(diff.Removed, withoutFingerprint(oldIndex[key]))
+		}
+	}
+	for _, key := range newKeys {
+		network := newIndex[key]
+		previous, exists := oldIndex[key]
+		if !exists {
+			diff.Added = append(diff.Added, withoutFingerprint(network))
+			continue
+		}
+		if changes := diffNetwork(previous, network); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, ProfileChange{
+				ProfileName: network.ProfileName,
+				SSID:        network.SSID,
+				Changes:     changes,
+			})
+		} else {
+			diff.Unchanged++
+		}
+	}
+
+	sort.SliceStable(diff.Added, func(i, j int) bool { return diff.Added[i].ProfileName < diff.Added[j].ProfileName })
+	sort.SliceStable(diff.Removed, func(i, j int) bool { return diff.Removed[i].ProfileName < diff.Removed[j].ProfileName })
+	sort.SliceStable(diff.Changed, func(i, j int) bool { return diff.Changed[i].ProfileName < diff.Changed[j].ProfileName })
+	return diff
+}
+
+// describeSnapshot 返回快照的来源说明
+func describeSnapshot(snapshot *SavedSnapshot) string {
+	parts := []string{snapshot.Created.Format("2006-01-02 15:04:05")}
+	if snapshot.Hostname != "" {
+		parts = append(parts, snapshot.Hostname)
+	}
+	if snapshot.Source != "" {
+		parts = append(parts, snapshot.Source)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeSnapshotNetwork 返回新增或删除的网络的简要说明
+func describeSnapshotNetwork(network SnapshotNetwork) string {
+	var details []string
+	if network.SSID != network.ProfileName {
+		details = append(details, "SSID: "+network.SSID)
+	}
+	if network.Authentication != "" {
+		details = append(details, network.Authentication)
+	}
+	if mode := formatConnectionMode(network.ConnectionMode); mode != "" {
+		details = append(details, mode)
+	}
+	if scope := formatScope(network.Scope); scope != "" {
+		details = append(details, scope)
+	}
+	if len(details) == 0 {
+		return network.ProfileName
+	}
+	return fmt.Sprintf("%s (%s)", network.ProfileName, strings.Join(details, ", "))
+}
+
+// displayChangeValue 返回变化前后的显示值，空值显示为"无"
+func displayChangeValue(value string) string {
+	if value == "" {
+		return "无"
+	}
+	return value
+}
+
+// FormatSnapshotDiff 格式化两个快照之间的差异
+func FormatSnapshotDiff(diff *SnapshotDiff) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("=== 已保存网络配置变化 - %s ===\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	output.WriteString(fmt.Sprintf("旧快照: %s，%d 个网络\n", describeSnapshot(diff.Old), len(diff.Old.Networks)))
+	output.WriteString(fmt.Sprintf("新快照: %s，%d 个网络\n\n", describeSnapshot(diff.New), len(diff.New.Networks)))
+
+	if !diff.HasChanges() {
+		output.WriteString("两个快照之间没有变化\n")
+		return output.String()
+	}
+
+	output.WriteString(fmt.Sprintf("新增 %d 个，删除 %d 个，变化 %d 个，未变化 %d 个\n\n",
+		len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged))
+
+	if len(diff.Added) > 0 {
+		output.WriteString("新增的配置文件:\n")
+		for _, network := range diff.Added {
+			output.WriteString(fmt.Sprintf("  + %s\n", describeSnapshotNetwork(network)))
+		}
+		output.WriteString("\n")
+	}
+	if len(diff.Removed) > 0 {
+		output.WriteString("删除的配置文件:\n")
+		for _, network := range diff.Removed {
+			output.WriteString(fmt.Sprintf("  - %s\n", describeSnapshotNetwork(network)))
+		}
+		output.WriteString("\n")
+	}
+	if len(diff.Changed) > 0 {
+		output.WriteString("变化的配置文件:\n")
+		for _, change := range diff.Changed {
+			output.WriteString(fmt.Sprintf("  * %s\n", change.ProfileName))
+			for _, field := range change.Changes {
+				output.WriteString(fmt.Sprintf("      %s: %s -> %s\n", field.Field, displayChangeValue(field.Old), displayChangeValue(field.New)))
+			}
+		}
+		output.WriteString("\n")
+	}
+	return output.String()
+}
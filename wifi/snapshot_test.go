@@ -0,0 +1,45 @@
+package wifi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotDiffKeyChange(t *testing.T) {
+	network := SavedWiFi{ProfileName: "HomeNet", SSID: "HomeNet", Password: "correct horse", KeyStatus: KeyPresent, Authentication: "WPA2-Personal"}
+	before, err := NewSavedSnapshot([]SavedWiFi{network}, SourceNetsh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.Password = "battery staple"
+	added := SavedWiFi{ProfileName: "Office", SSID: "Office", Password: "correct horse", KeyStatus: KeyPresent, Authentication: "WPA2-Personal"}
+	after, err := NewSavedSnapshot([]SavedWiFi{network, added}, SourceNetsh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if before.Networks[0].KeyFingerprint == after.Networks[1].KeyFingerprint {
+		t.Error("不同SSID的相同密钥指纹应不同")
+	}
+
+	diff := DiffSnapshots(before, after)
+	if len(diff.Added) != 1 || len(diff.Changed) != 1 || diff.Changed[0].Changes[0].Field != "密钥" {
+		t.Fatalf("比较结果 = %+v，期望新增1个、密钥变化1个", diff)
+	}
+	if change := diff.Changed[0].Changes[0]; change.Old != "已获取" || change.New != "已获取 (密钥已变更)" {
+		t.Errorf("密钥变化 = %+v", change)
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, snapshot := range []*SavedSnapshot{before, after} {
+		for _, item := range snapshot.Networks {
+			if strings.Contains(string(data), item.KeyFingerprint) {
+				t.Errorf("比较结果中包含密钥指纹 %s", item.KeyFingerprint)
+			}
+		}
+	}
+}
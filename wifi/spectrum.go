@@ -0,0 +1,190 @@
+package wifi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 频谱图默认宽度（字符数）和高度（行数）
+const (
+	DefaultSpectrumWidth  = 100
+	DefaultSpectrumHeight = 10
+)
+
+// spectrumLabels AP在频谱图中使用的标记字符
+var spectrumLabels = []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789")
+
+// bandRange 表示一个频段在频谱图上的显示范围
+type bandRange struct {
+	Band     string
+	Low      int   // 起始频率（MHz）
+	High     int   // 结束频率（MHz）
+	Channels []int // 坐标轴上标注的信道
+}
+
+// spectrumBands 各频段的显示范围和坐标轴信道
+var spectrumBands = []bandRange{
+	{Band2G, 2400, 2495, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}},
+	{Band5G, 5150, 5895, []int{36, 40, 44, 48, 52, 56, 60, 64, 100, 104, 108, 112, 116, 120, 124, 128, 132, 136, 140, 144, 149, 153, 157, 161, 165, 169, 173, 177}},
+	{Band6G, 5925, 7125, []int{1, 17, 33, 49, 65, 81, 97, 113, 129, 145, 161, 177, 193, 209, 225}},
+}
+
+// spectrumAP 表示频谱图中的一个AP
+type spectrumAP struct {
+	Label   rune
+	Network WiFiNetwork
+	Channel ChannelInfo
+	Signal  int
+}
+
+// FormatSpectrum 将扫描结果渲染为按频段划分的终端频谱图
+// 每个AP按信道宽度绘制为一个凸起，高度表示信号强度，未知信道宽度按20MHz绘制
+func FormatSpectrum(networks []WiFiNetwork, width int, height int) string {
+	if width <= 0 {
+		width = DefaultSpectrumWidth
+	}
+	if height <= 0 {
+		height = DefaultSpectrumHeight
+	}
+
+	var output strings.Builder
+
+	// 按频段归类AP，信号强的优先分配标记
+	sorted := append([]WiFiNetwork(nil), networks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, _ := sorted[i].SignalValue()
+		sj, _ := sorted[j].SignalValue()
+		return si > sj
+	})
+
+	byBand := make(map[string][]spectrumAP)
+	skipped := 0
+	drawn := 0
+	for _, network := range sorted {
+		info := network.ChannelInfo()
+		if !info.IsValid() {
+			skipped++
+			continue
+		}
+		signal, _ := network.SignalValue()
+		byBand[info.Band] = append(byBand[info.Band], spectrumAP{
+			Label:   spectrumLabels[drawn%len(spectrumLabels)],
+			Network: network,
+			Channel: info,
+			Signal:  signal,
+		})
+		drawn++
+	}
+
+	if len(byBand) == 0 {
+		output.WriteString("没有可绘制频谱的WiFi网络\n")
+		return output.String()
+	}
+
+	for _, band := range spectrumBands {
+		aps := byBand[band.Band]
+		if len(aps) == 0 {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("=== %s 频段 (%d-%d MHz) ===\n\n", band.Band, band.Low, band.High))
+		output.WriteString(renderSpectrumBand(band, aps, width, height))
+		output.WriteString("\n")
+		for _, ap := range aps {
+			output.WriteString(fmt.Sprintf("  %c  %-20s | 信道: %-22s | 信号: %-5s | BSSID: %s\n",
+				ap.Label, ap.Network.SSID, ap.Channel, ap.Network.Signal, ap.Network.BSSID))
+		}
+		output.WriteString("\n")
+	}
+
+	if skipped > 0 {
+		output.WriteString(fmt.Sprintf("注意: %d 个网络的信道信息无法识别，未绘制在频谱图中\n", skipped))
+	}
+
+	return output.String()
+}
+
+// renderSpectrumBand 绘制单个频段的频谱图
+func renderSpectrumBand(band bandRange, aps []spectrumAP, width int, height int) string {
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", width))
+	}
+
+	// 频率到列的映射
+	span := float64(band.High - band.Low)
+	column := func(freq float64) int {
+		col := int((freq - float64(band.Low)) / span * float64(width-1))
+		if col < 0 {
+			return 0
+		}
+		if col >= width {
+			return width - 1
+		}
+		return col
+	}
+
+	// 先绘制信号弱的AP，信号强的覆盖在上层
+	ordered := append([]spectrumAP(nil), aps...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Signal < ordered[j].Signal
+	})
+
+	for _, ap := range ordered {
+		left := column(float64(ap.Channel.LowFrequency()))
+		right := column(float64(ap.Channel.HighFrequency()))
+		peak := ap.Signal * height / 100
+		if peak < 1 {
+			peak = 1
+		}
+		if peak > height {
+			peak = height
+		}
+
+		for col := left; col <= right; col++ {
+			// 两侧边缘降低一半高度，形成凸起形状
+			level := peak
+			if (col == left || col == right) && right-left >= 2 {
+				level = (peak + 1) / 2
+			}
+			top := height - level
+			grid[top][col] = ap.Label
+			if col == left || col == right {
+				for row := top + 1; row < height; row++ {
+					grid[row][col] = '|'
+				}
+			}
+		}
+	}
+
+	var output strings.Builder
+	for row, line := range grid {
+		percent := (height - row) * 100 / height
+		output.WriteString(fmt.Sprintf("%4d%% |%s\n", percent, string(line)))
+	}
+	output.WriteString("      +" + strings.Repeat("-", width) + "\n")
+
+	// 标注信道号，避免标签相互重叠
+	axis := []rune(strings.Repeat(" ", width))
+	lastEnd := -1
+	for _, channel := range band.Channels {
+		freq := ChannelFrequency(band.Band, channel)
+		if freq == 0 {
+			continue
+		}
+		label := strconv.Itoa(channel)
+		col := column(float64(freq)) - len(label)/2
+		if col < 0 {
+			col = 0
+		}
+		if col <= lastEnd || col+len(label) > width {
+			continue
+		}
+		copy(axis[col:], []rune(label))
+		lastEnd = col + len(label)
+	}
+	output.WriteString("  信道 " + string(axis) + "\n")
+
+	return output.String()
+}
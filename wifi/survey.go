@@ -0,0 +1,313 @@
+package wifi
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 覆盖盲区判定阈值：企业SSID最佳信号低于该值视为覆盖不足
+const DefaultCoverageThreshold = 40
+
+// SurveySample 表示某个位置的一次扫描采样
+type SurveySample struct {
+	Time     time.Time
+	Networks []WiFiNetwork
+}
+
+// SurveyLocation 表示一个已标记的勘测位置
+type SurveyLocation struct {
+	Name    string
+	Samples []SurveySample
+}
+
+// SiteSurvey 表示一次完整的现场勘测
+type SiteSurvey struct {
+	CorporateSSID     string
+	CoverageThreshold int
+	StartTime         time.Time
+	Locations         []*SurveyLocation
+}
+
+// SurveyAP 表示某个位置可见的单个AP的汇总信息
+type SurveyAP struct {
+	SSID       string
+	BSSID      string
+	Channel    ChannelInfo
+	Security   string
+	BestSignal int
+	AvgSignal  int
+	SeenCount  int
+}
+
+// ChannelUsage 表示某个位置上单个信道的占用情况
+type ChannelUsage struct {
+	Channel ChannelInfo
+	APCount int
+}
+
+// ChannelOverlap 表示某个位置上频谱互相重叠的两个信道
+type ChannelOverlap struct {
+	ChannelA ChannelInfo
+	ChannelB ChannelInfo
+	APCount  int
+}
+
+// LocationSummary 表示某个位置的勘测汇总
+type LocationSummary struct {
+	Name            string
+	SampleCount     int
+	APs             []SurveyAP
+	BestBySSID      map[string]int
+	CorporateSignal int
+	CoverageGap     bool
+	ChannelUsage    []ChannelUsage
+	Overlaps        []ChannelOverlap
+}
+
+// NewSiteSurvey 创建一次新的现场勘测
+func NewSiteSurvey(corporateSSID string, threshold int) *SiteSurvey {
+	if threshold <= 0 {
+		threshold = DefaultCoverageThreshold
+	}
+	return &SiteSurvey{
+		CorporateSSID:     corporateSSID,
+		CoverageThreshold: threshold,
+		StartTime:         time.Now(),
+	}
+}
+
+// Location 返回指定名称的位置，不存在时创建
+func (s *SiteSurvey) Location(name string) *SurveyLocation {
+	for _, loc := range s.Locations {
+		if loc.Name == name {
+			return loc
+		}
+	}
+	loc := &SurveyLocation{Name: name}
+	s.Locations = append(s.Locations, loc)
+	return loc
+}
+
+// AddSample 为位置添加一次扫描采样
+func (l *SurveyLocation) AddSample(networks []WiFiNetwork) {
+	l.Samples = append(l.Samples, SurveySample{
+		Time:     time.Now(),
+		Networks: networks,
+	})
+}
+
+// Summarize 汇总位置上的所有采样
+func (l *SurveyLocation) Summarize(corporateSSID string, threshold int) LocationSummary {
+	summary := LocationSummary{
+		Name:        l.Name,
+		SampleCount: len(l.Samples),
+		BestBySSID:  make(map[string]int),
+	}
+
+	// 按BSSID汇总每个AP
+	apIndex := make(map[string]*SurveyAP)
+	signalSum := make(map[string]int)
+	var order []string
+	for _, sample := range l.Samples {
+		for _, network := range sample.Networks {
+			key := strings.ToLower(network.BSSID)
+			if key == "" {
+				key = network.SSID
+			}
+			signal, _ := network.SignalValue()
+
+			ap, ok := apIndex[key]
+			if !ok {
+				ap = &SurveyAP{
+					SSID:     network.SSID,
+					BSSID:    network.BSSID,
+					Channel:  network.ChannelInfo(),
+					Security: network.Security,
+				}
+				apIndex[key] = ap
+				order = append(order, key)
+			}
+			ap.SeenCount++
+			signalSum[key] += signal
+			if signal > ap.BestSignal {
+				ap.BestSignal = signal
+			}
+
+			if best, ok := summary.BestBySSID[network.SSID]; !ok || signal > best {
+				summary.BestBySSID[network.SSID] = signal
+			}
+		}
+	}
+
+	// 按频段和信道统计占用情况，同一主信道按最宽的信道宽度统计
+	usageIndex := make(map[string]int)
+	for _, key := range order {
+		ap := apIndex[key]
+		ap.AvgSignal = signalSum[key] / ap.SeenCount
+		summary.APs = append(summary.APs, *ap)
+		if !ap.Channel.IsValid() {
+			continue
+		}
+		channelKey := fmt.Sprintf("%s/%d", ap.Channel.Band, ap.Channel.Primary)
+		if i, ok := usageIndex[channelKey]; ok {
+			summary.ChannelUsage[i].APCount++
+			if ap.Channel.SpanWidth() > summary.ChannelUsage[i].Channel.SpanWidth() {
+				summary.ChannelUsage[i].Channel = ap.Channel
+			}
+			continue
+		}
+		usageIndex[channelKey] = len(summary.ChannelUsage)
+		summary.ChannelUsage = append(summary.ChannelUsage, ChannelUsage{Channel: ap.Channel, APCount: 1})
+	}
+	sort.Slice(summary.ChannelUsage, func(i, j int) bool {
+		return summary.ChannelUsage[i].Channel.Frequency < summary.ChannelUsage[j].Channel.Frequency
+	})
+	sort.SliceStable(summary.APs, func(i, j int) bool {
+		return summary.APs[i].BestSignal > summary.APs[j].BestSignal
+	})
+
+	// 企业SSID覆盖情况
+	if corporateSSID != "" {
+		summary.CorporateSignal = summary.BestBySSID[corporateSSID]
+		summary.CoverageGap = summary.CorporateSignal < threshold
+	}
+
+	summary.Overlaps = channelOverlaps(summary.ChannelUsage)
+	return summary
+}
+
+// channelOverlaps 计算频谱相互重叠但主信道不同的信道组合
+// 2.4GHz信道间隔5MHz、信道宽度20MHz，信道号相差小于5即存在重叠；5/6GHz的宽信道同样可能重叠
+func channelOverlaps(usage []ChannelUsage) []ChannelOverlap {
+	var overlaps []ChannelOverlap
+	for i := 0; i < len(usage); i++ {
+		for j := i + 1; j < len(usage); j++ {
+			if usage[i].Channel.Overlaps(usage[j].Channel) {
+				overlaps = append(overlaps, ChannelOverlap{
+					ChannelA: usage[i].Channel,
+					ChannelB: usage[j].Channel,
+					APCount:  usage[i].APCount + usage[j].APCount,
+				})
+			}
+		}
+	}
+	return overlaps
+}
+
+// Summaries 返回所有位置的汇总
+func (s *SiteSurvey) Summaries() []LocationSummary {
+	var summaries []LocationSummary
+	for _, loc := range s.Locations {
+		summaries = append(summaries, loc.Summarize(s.CorporateSSID, s.CoverageThreshold))
+	}
+	return summaries
+}
+
+// surveyTemplate 勘测报告的HTML模板
+var surveyTemplate = template.Must(template.New("survey").Funcs(template.FuncMap{
+	"sortedSSIDs": func(m map[string]int) []string {
+		var keys []string
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if m[keys[i]] != m[keys[j]] {
+				return m[keys[i]] > m[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+		return keys
+	},
+	"bestSignal": func(m map[string]int, key string) int { return m[key] },
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>WiFi现场勘测报告</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 0.5em 0 1.5em 0; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f0f0f0; }
+.gap { color: #fff; background: #c0392b; padding: 2px 6px; }
+.ok { color: #fff; background: #27ae60; padding: 2px 6px; }
+</style>
+</head>
+<body>
+<h1>WiFi现场勘测报告</h1>
+<p>开始时间: {{.Survey.StartTime.Format "2006-01-02 15:04:05"}}<br>
+生成时间: {{.Generated}}<br>
+位置数量: {{len .Summaries}}{{if .Survey.CorporateSSID}}<br>
+企业SSID: {{.Survey.CorporateSSID}}（覆盖阈值 {{.Survey.CoverageThreshold}}%）{{end}}</p>
+
+{{if .Survey.CorporateSSID}}
+<h2>覆盖概览</h2>
+<table>
+<tr><th>位置</th><th>采样次数</th><th>企业SSID最佳信号</th><th>状态</th></tr>
+{{range .Summaries}}<tr><td>{{.Name}}</td><td>{{.SampleCount}}</td><td>{{.CorporateSignal}}%</td><td>{{if .CoverageGap}}<span class="gap">覆盖盲区</span>{{else}}<span class="ok">正常</span>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{range .Summaries}}
+<h2>位置: {{.Name}}</h2>
+<p>采样次数: {{.SampleCount}}，可见AP: {{len .APs}}</p>
+
+<h3>可见AP</h3>
+<table>
+<tr><th>SSID</th><th>BSSID</th><th>信道</th><th>安全类型</th><th>最佳信号</th><th>平均信号</th><th>出现次数</th></tr>
+{{range .APs}}<tr><td>{{.SSID}}</td><td>{{.BSSID}}</td><td>{{.Channel}}</td><td>{{.Security}}</td><td>{{.BestSignal}}%</td><td>{{.AvgSignal}}%</td><td>{{.SeenCount}}</td></tr>
+{{end}}</table>
+
+<h3>各SSID最佳信号</h3>
+<table>
+<tr><th>SSID</th><th>最佳信号</th></tr>
+{{$best := .BestBySSID}}{{range sortedSSIDs $best}}<tr><td>{{.}}</td><td>{{bestSignal $best .}}%</td></tr>
+{{end}}</table>
+
+<h3>信道占用</h3>
+<table>
+<tr><th>信道</th><th>频段</th><th>中心频率</th><th>信道宽度</th><th>DFS</th><th>AP数量</th></tr>
+{{range .ChannelUsage}}<tr><td>{{.Channel.Primary}}</td><td>{{.Channel.Band}}</td><td>{{.Channel.CenterFrequency}} MHz</td><td>{{if .Channel.Width}}{{.Channel.Width}} MHz{{else}}未知{{end}}</td><td>{{if .Channel.DFS}}是{{else}}否{{end}}</td><td>{{.APCount}}</td></tr>
+{{end}}</table>
+
+{{if .Overlaps}}<h3>信道重叠</h3>
+<table>
+<tr><th>信道A</th><th>信道B</th><th>涉及AP数量</th></tr>
+{{range .Overlaps}}<tr><td>{{.ChannelA}}</td><td>{{.ChannelB}}</td><td>{{.APCount}}</td></tr>
+{{end}}</table>{{else}}<p>未发现信道重叠</p>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// FormatSurveyHTML 生成现场勘测HTML报告
+func FormatSurveyHTML(survey *SiteSurvey) (string, error) {
+	var output strings.Builder
+	err := surveyTemplate.Execute(&output, map[string]interface{}{
+		"Survey":    survey,
+		"Summaries": survey.Summaries(),
+		"Generated": time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("生成勘测报告失败: %v", err)
+	}
+	return output.String(), nil
+}
+
+// FormatSurveyLocationResult 格式化单个位置的勘测汇总，用于终端显示
+func FormatSurveyLocationResult(summary LocationSummary) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("位置 %s: 采样 %d 次，可见AP %d 个\n",
+		summary.Name, summary.SampleCount, len(summary.APs)))
+	for _, ap := range summary.APs {
+		output.WriteString(fmt.Sprintf("  %-20s | %-17s | 信道: %-22s | 最佳: %3d%% | 平均: %3d%%\n",
+			ap.SSID, ap.BSSID, ap.Channel, ap.BestSignal, ap.AvgSignal))
+	}
+	if summary.CoverageGap {
+		output.WriteString(fmt.Sprintf("  警告: 企业SSID信号不足 (%d%%)\n", summary.CorporateSignal))
+	}
+	return output.String()
+}
@@ -0,0 +1,169 @@
+package wifi
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// surveyNetwork 构造勘测采样中的一个网络
+func surveyNetwork(ssid, bssid string, signal int, channel string, width int) WiFiNetwork {
+	return WiFiNetwork{
+		SSID:         ssid,
+		BSSID:        bssid,
+		Signal:       fmt.Sprintf("%d%%", signal),
+		Channel:      channel,
+		Security:     "WPA2-Personal",
+		ChannelWidth: width,
+	}
+}
+
+func TestSurveySummarize(t *testing.T) {
+	tests := []struct {
+		name            string
+		samples         [][]WiFiNetwork
+		corporateSSID   string
+		threshold       int
+		wantAPs         int
+		wantBest        map[string]int
+		wantCorporate   int
+		wantGap         bool
+		wantUsage       map[string]int
+		wantOverlapKeys []string
+	}{
+		{
+			name: "每个SSID取最佳信号",
+			samples: [][]WiFiNetwork{
+				{
+					surveyNetwork("Corp", "aa:00:00:00:00:01", 40, "1", 0),
+					surveyNetwork("Corp", "aa:00:00:00:00:02", 55, "11", 0),
+					surveyNetwork("Guest", "aa:00:00:00:00:03", 80, "1", 0),
+				},
+				{
+					// 同一AP的BSSID大小写不同仍按同一个AP汇总
+					surveyNetwork("Corp", "AA:00:00:00:00:01", 70, "1", 0),
+				},
+			},
+			corporateSSID: "Corp",
+			threshold:     60,
+			wantAPs:       3,
+			wantBest:      map[string]int{"Corp": 70, "Guest": 80},
+			wantCorporate: 70,
+			wantUsage:     map[string]int{"2.4GHz/1": 2, "2.4GHz/11": 1},
+		},
+		{
+			name:          "低于阈值为覆盖盲区",
+			samples:       [][]WiFiNetwork{{surveyNetwork("Corp", "aa:00:00:00:00:01", 59, "6", 0)}},
+			corporateSSID: "Corp",
+			threshold:     60,
+			wantAPs:       1,
+			wantBest:      map[string]int{"Corp": 59},
+			wantCorporate: 59,
+			wantGap:       true,
+			wantUsage:     map[string]int{"2.4GHz/6": 1},
+		},
+		{
+			name:          "等于阈值不是覆盖盲区",
+			samples:       [][]WiFiNetwork{{surveyNetwork("Corp", "aa:00:00:00:00:01", 60, "6", 0)}},
+			corporateSSID: "Corp",
+			threshold:     60,
+			wantAPs:       1,
+			wantBest:      map[string]int{"Corp": 60},
+			wantCorporate: 60,
+			wantUsage:     map[string]int{"2.4GHz/6": 1},
+		},
+		{
+			name:          "企业SSID不可见",
+			samples:       [][]WiFiNetwork{{surveyNetwork("Guest", "aa:00:00:00:00:03", 90, "6", 0)}},
+			corporateSSID: "Corp",
+			threshold:     40,
+			wantAPs:       1,
+			wantBest:      map[string]int{"Guest": 90},
+			wantGap:       true,
+			wantUsage:     map[string]int{"2.4GHz/6": 1},
+		},
+		{
+			name:          "没有采样的位置",
+			corporateSSID: "Corp",
+			threshold:     40,
+			wantBest:      map[string]int{},
+			wantGap:       true,
+			wantUsage:     map[string]int{},
+		},
+		{
+			name:      "未指定企业SSID不判定盲区",
+			threshold: 40,
+			wantBest:  map[string]int{},
+			wantUsage: map[string]int{},
+		},
+		{
+			name: "信道重叠",
+			samples: [][]WiFiNetwork{{
+				surveyNetwork("A", "aa:00:00:00:00:01", 50, "1", 0),
+				surveyNetwork("B", "aa:00:00:00:00:02", 50, "1", 0),
+				surveyNetwork("C", "aa:00:00:00:00:03", 50, "3", 0),
+				surveyNetwork("D", "aa:00:00:00:00:04", 50, "6", 0),
+				surveyNetwork("E", "aa:00:00:00:00:05", 50, "11", 0),
+				surveyNetwork("F", "aa:00:00:00:00:06", 50, "36", 80),
+				surveyNetwork("G", "aa:00:00:00:00:07", 50, "44", 20),
+				surveyNetwork("H", "aa:00:00:00:00:08", 50, "149", 80),
+			}},
+			threshold:       40,
+			wantAPs:         8,
+			wantBest:        map[string]int{"A": 50, "B": 50, "C": 50, "D": 50, "E": 50, "F": 50, "G": 50, "H": 50},
+			wantUsage:       map[string]int{"2.4GHz/1": 2, "2.4GHz/3": 1, "2.4GHz/6": 1, "2.4GHz/11": 1, "5GHz/36": 1, "5GHz/44": 1, "5GHz/149": 1},
+			wantOverlapKeys: []string{"1-3:3", "3-6:2", "36-44:2"},
+		},
+	}
+
+	for _, test := range tests {
+		location := &SurveyLocation{Name: test.name}
+		for _, networks := range test.samples {
+			location.AddSample(networks)
+		}
+		summary := location.Summarize(test.corporateSSID, test.threshold)
+
+		if summary.SampleCount != len(test.samples) {
+			t.Errorf("%s: SampleCount = %d，期望 %d", test.name, summary.SampleCount, len(test.samples))
+		}
+		if len(summary.APs) != test.wantAPs {
+			t.Errorf("%s: AP数量 = %d，期望 %d", test.name, len(summary.APs), test.wantAPs)
+		}
+		if !reflect.DeepEqual(summary.BestBySSID, test.wantBest) {
+			t.Errorf("%s: BestBySSID = %v，期望 %v", test.name, summary.BestBySSID, test.wantBest)
+		}
+		if summary.CorporateSignal != test.wantCorporate || summary.CoverageGap != test.wantGap {
+			t.Errorf("%s: 企业SSID信号 = %d，盲区 = %v，期望 %d、%v",
+				test.name, summary.CorporateSignal, summary.CoverageGap, test.wantCorporate, test.wantGap)
+		}
+		usage := make(map[string]int)
+		for _, u := range summary.ChannelUsage {
+			usage[fmt.Sprintf("%s/%d", u.Channel.Band, u.Channel.Primary)] = u.APCount
+		}
+		if !reflect.DeepEqual(usage, test.wantUsage) {
+			t.Errorf("%s: 信道占用 = %v，期望 %v", test.name, usage, test.wantUsage)
+		}
+		var overlapKeys []string
+		for _, o := range summary.Overlaps {
+			overlapKeys = append(overlapKeys, fmt.Sprintf("%d-%d:%d", o.ChannelA.Primary, o.ChannelB.Primary, o.APCount))
+		}
+		if !reflect.DeepEqual(overlapKeys, test.wantOverlapKeys) {
+			t.Errorf("%s: 信道重叠 = %v，期望 %v", test.name, overlapKeys, test.wantOverlapKeys)
+		}
+	}
+}
+
+func TestSurveySummarizeAverage(t *testing.T) {
+	location := &SurveyLocation{Name: "大厅"}
+	location.AddSample([]WiFiNetwork{surveyNetwork("Corp", "aa:00:00:00:00:01", 40, "6", 0)})
+	location.AddSample([]WiFiNetwork{surveyNetwork("Corp", "aa:00:00:00:00:01", 70, "6", 0)})
+	location.AddSample([]WiFiNetwork{surveyNetwork("Corp", "aa:00:00:00:00:01", 61, "6", 0)})
+
+	summary := location.Summarize("Corp", DefaultCoverageThreshold)
+	if len(summary.APs) != 1 {
+		t.Fatalf("AP数量 = %d，期望 1", len(summary.APs))
+	}
+	if ap := summary.APs[0]; ap.BestSignal != 70 || ap.AvgSignal != 57 || ap.SeenCount != 3 {
+		t.Errorf("AP汇总 = 最佳%d 平均%d 出现%d次，期望 最佳70 平均57 出现3次", ap.BestSignal, ap.AvgSignal, ap.SeenCount)
+	}
+}
@@ -0,0 +1,260 @@
+package wifi
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+)
+
+// 密码管理器导出格式，所有网络写入同一个文件
+const (
+	ExportKeePass   = "keepass"   // KeePass 2.x XML
+	ExportBitwarden = "bitwarden" // Bitwarden未加密JSON
+	ExportCSV       = "csv"       // 1Password等密码管理器通用的CSV
+)
+
+// vaultFolderName 导入密码管理器后条目所在的分组名称
+const vaultFolderName = "WiFi"
+
+// vaultField 表示密码管理器条目中的一个自定义字段
+type vaultField struct {
+	Name  string
+	Value string
+}
+
+// vaultEntry 表示导出到密码管理器的一个条目，SSID为标题，密钥为密码
+type vaultEntry struct {
+	Title    string
+	Password string
+	Fields   []vaultField
+}
+
+// newVaultEntry 将已获取密钥的网络转换为密码管理器条目，身份验证和加密方式作为自定义字段
+func newVaultEntry(network SavedWiFi) (vaultEntry, error) {
+	if !network.HasPassword() {
+		return vaultEntry{}, fmt.Errorf("%s", network.PasswordDisplay())
+	}
+
+	auth := network.AuthType().String()
+	if network.AuthType() == AuthUnknown {
+		auth = network.Authentication
+	}
+	entry := vaultEntry{Title: network.SSID, Password: network.Password}
+	for _, field := range []vaultField{
+		{"身份验证", auth},
+		{"加密方式", network.Cipher},
+		{"密钥类型", network.KeyType},
+		{"隐藏网络", formatBool(network.NonBroadcast)},
+	} {
+		if field.Value != "" {
+			entry.Fields = append(entry.Fields, field)
+		}
+	}
+	if network.ProfileName != "" && network.ProfileName != network.SSID {
+		entry.Fields = append(entry.Fields, vaultField{"配置文件", network.ProfileName})
+	}
+	return entry, nil
+}
+
+// vaultFileName 返回密码管理器导出文件的名称和扩展名
+func vaultFileName(format string) (string, string) {
+	switch format {
+	case ExportKeePass:
+		return "keepass", "xml"
+	case ExportBitwarden:
+		return "bitwarden", "json"
+	}
+	return "wifi", "csv"
+}
+
+// formatVaultFile 按密码管理器格式生成导入文件
+func formatVaultFile(format string, entries []vaultEntry) ([]byte, error) {
+	switch format {
+	case ExportKeePass:
+		return formatKeePassXML(entries)
+	case ExportBitwarden:
+		return formatBitwardenJSON(entries)
+	case ExportCSV:
+		return formatVaultCSV(entries)
+	}
+	return nil, fmt.Errorf("不支持的导出格式: %s", format)
+}
+
+// keePassFile KeePass 2.x XML导出格式的根元素
+type keePassFile struct {
+	XMLName xml.Name    `xml:"KeePassFile"`
+	Meta    keePassMeta `xml:"Meta"`
+	Root    keePassRoot `xml:"Root"`
+}
+
+type keePassMeta struct {
+	Generator string `xml:"Generator"`
+}
+
+type keePassRoot struct {
+	Group keePassGroup `xml:"Group"`
+}
+
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+}
+
+type keePassEntry struct {
+	UUID    string          `xml:"UUID"`
+	Strings []keePassString `xml:"String"`
+}
+
+type keePassString struct {
+	Key   string       `xml:"Key"`
+	Value keePassValue `xml:"Value"`
+}
+
+// keePassValue 字段值，ProtectInMemory为True时KeePass在内存中加密保存
+type keePassValue struct {
+	ProtectInMemory string `xml:"ProtectInMemory,attr,omitempty"`
+	Text            string `xml:",chardata"`
+}
+
+// keePassUUID 生成KeePass使用的Base64编码随机UUID
+func keePassUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+// formatKeePassXML 生成可通过"导入 - KeePass XML (2.x)"导入的文件
+func formatKeePassXML(entries []vaultEntry) ([]byte, error) {
+	group := keePassGroup{UUID: keePassUUID(), Name: vaultFolderName}
+	for _, entry := range entries {
+		item := keePassEntry{
+			UUID: keePassUUID(),
+			Strings: []keePassString{
+				{Key: "Title", Value: keePassValue{Text: entry.Title}},
+				{Key: "UserName"},
+				{Key: "Password", Value: keePassValue{ProtectInMemory: "True", Text: entry.Password}},
+				{Key: "URL"},
+				{Key: "Notes"},
+			},
+		}
+		for _, field := range entry.Fields {
+			item.Strings = append(item.Strings, keePassString{Key: field.Name, Value: keePassValue{Text: field.Value}})
+		}
+		group.Entries = append(group.Entries, item)
+	}
+
+	file := keePassFile{Meta: keePassMeta{Generator: "WifiSOS"}, Root: keePassRoot{Group: group}}
+	data, err := xml.MarshalIndent(file, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("生成KeePass XML失败: %v", err)
+	}
+	return append([]byte(`<?xml version="1.0" encoding="utf-8" standalone="yes"?>`+"\n"), append(data, '\n')...), nil
+}
+
+// bitwardenExport Bitwarden未加密JSON导出格式
+type bitwardenExport struct {
+	Encrypted bool              `json:"encrypted"`
+	Folders   []bitwardenFolder `json:"folders"`
+	Items     []bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// bitwardenItem Bitwarden条目，Type为1表示登录信息
+type bitwardenItem struct {
+	ID       string           `json:"id"`
+	FolderID string           `json:"folderId"`
+	Type     int              `json:"type"`
+	Name     string           `json:"name"`
+	Notes    *string          `json:"notes"`
+	Favorite bool             `json:"favorite"`
+	Fields   []bitwardenField `json:"fields"`
+	Login    bitwardenLogin   `json:"login"`
+}
+
+// bitwardenField 自定义字段，Type为0表示文本
+type bitwardenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
+type bitwardenLogin struct {
+	URIs     []string `json:"uris"`
+	Username *string  `json:"username"`
+	Password string   `json:"password"`
+	TOTP     *string  `json:"totp"`
+}
+
+// formatBitwardenJSON 生成可通过"导入数据 - Bitwarden (json)"导入的文件
+func formatBitwardenJSON(entries []vaultEntry) ([]byte, error) {
+	folder := bitwardenFolder{ID: newUUID(), Name: vaultFolderName}
+	export := bitwardenExport{Folders: []bitwardenFolder{folder}, Items: []bitwardenItem{}}
+	for _, entry := range entries {
+		item := bitwardenItem{
+			ID:       newUUID(),
+			FolderID: folder.ID,
+			Type:     1,
+			Name:     entry.Title,
+			Fields:   []bitwardenField{},
+			Login:    bitwardenLogin{URIs: []string{}, Password: entry.Password},
+		}
+		for _, field := range entry.Fields {
+			item.Fields = append(item.Fields, bitwardenField{Name: field.Name, Value: field.Value})
+		}
+		export.Items = append(export.Items, item)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成Bitwarden JSON失败: %v", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// formatVaultCSV 生成密码管理器通用的CSV，前五列为1Password可识别的标准列，其余列导入时可映射为自定义字段
+func formatVaultCSV(entries []vaultEntry) ([]byte, error) {
+	header := []string{"Title", "Website", "Username", "Password", "Notes"}
+	var extra []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		for _, field := range entry.Fields {
+			if !seen[field.Name] {
+				seen[field.Name] = true
+				extra = append(extra, field.Name)
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(append(header, extra...)); err != nil {
+		return nil, fmt.Errorf("生成CSV失败: %v", err)
+	}
+	for _, entry := range entries {
+		values := make(map[string]string)
+		for _, field := range entry.Fields {
+			values[field.Name] = field.Value
+		}
+		record := []string{entry.Title, "", "", entry.Password, ""}
+		for _, name := range extra {
+			record = append(record, values[name])
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, fmt.Errorf("生成CSV失败: %v", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("生成CSV失败: %v", err)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,134 @@
+package wifi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+// vaultSample 含XML、JSON和CSV特殊字符的网络，用于验证各导出格式的转义
+var vaultSample = SavedWiFi{
+	SSID:           `Cafe <&",> 5G`,
+	Password:       `p<a&s"s,w>o'rd`,
+	KeyStatus:      KeyPresent,
+	ProfileName:    `Cafe "profile", <1>`,
+	Authentication: "WPA2-Personal",
+	Cipher:         "AES",
+	KeyType:        "passPhrase",
+	NonBroadcast:   true,
+}
+
+func vaultSampleEntry(t *testing.T) vaultEntry {
+	t.Helper()
+	entry, err := newVaultEntry(vaultSample)
+	if err != nil {
+		t.Fatalf("newVaultEntry 返回错误: %v", err)
+	}
+	return entry
+}
+
+func TestNewVaultEntryWithoutKey(t *testing.T) {
+	network := vaultSample
+	network.KeyStatus = KeyAbsent
+	if _, err := newVaultEntry(network); err == nil {
+		t.Errorf("newVaultEntry 对未获取密钥的网络应返回错误")
+	}
+}
+
+func TestFormatKeePassXMLRoundTrip(t *testing.T) {
+	entry := vaultSampleEntry(t)
+	data, err := formatVaultFile(ExportKeePass, []vaultEntry{entry})
+	if err != nil {
+		t.Fatalf("formatVaultFile(keepass) 返回错误: %v", err)
+	}
+
+	var file keePassFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		t.Fatalf("解析KeePass XML失败: %v\n%s", err, data)
+	}
+	if file.Root.Group.Name != vaultFolderName || len(file.Root.Group.Entries) != 1 {
+		t.Fatalf("KeePass分组 = %+v，期望一个条目", file.Root.Group)
+	}
+	values := make(map[string]keePassValue)
+	for _, s := range file.Root.Group.Entries[0].Strings {
+		values[s.Key] = s.Value
+	}
+	if values["Title"].Text != vaultSample.SSID {
+		t.Errorf("Title = %q，期望 %q", values["Title"].Text, vaultSample.SSID)
+	}
+	if got := values["Password"]; got.Text != vaultSample.Password || got.ProtectInMemory != "True" {
+		t.Errorf("Password = %+v，期望 %q 并受内存保护", got, vaultSample.Password)
+	}
+	for _, field := range entry.Fields {
+		if values[field.Name].Text != field.Value {
+			t.Errorf("字段 %s = %q，期望 %q", field.Name, values[field.Name].Text, field.Value)
+		}
+	}
+}
+
+func TestFormatBitwardenJSONRoundTrip(t *testing.T) {
+	entry := vaultSampleEntry(t)
+	data, err := formatVaultFile(ExportBitwarden, []vaultEntry{entry})
+	if err != nil {
+		t.Fatalf("formatVaultFile(bitwarden) 返回错误: %v", err)
+	}
+
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("解析Bitwarden JSON失败: %v\n%s", err, data)
+	}
+	if export.Encrypted || len(export.Folders) != 1 || len(export.Items) != 1 {
+		t.Fatalf("Bitwarden导出 = %+v，期望未加密的一个分组和一个条目", export)
+	}
+	item := export.Items[0]
+	if item.Name != vaultSample.SSID || item.Login.Password != vaultSample.Password {
+		t.Errorf("条目 = %q / %q，期望 %q / %q", item.Name, item.Login.Password, vaultSample.SSID, vaultSample.Password)
+	}
+	if item.Type != 1 || item.FolderID != export.Folders[0].ID {
+		t.Errorf("条目类型 = %d，分组 = %q，期望登录信息并位于 %q", item.Type, item.FolderID, export.Folders[0].ID)
+	}
+	var fields []vaultField
+	for _, field := range item.Fields {
+		fields = append(fields, vaultField{field.Name, field.Value})
+	}
+	if !reflect.DeepEqual(fields, entry.Fields) {
+		t.Errorf("自定义字段 = %v，期望 %v", fields, entry.Fields)
+	}
+}
+
+func TestFormatVaultCSVRoundTrip(t *testing.T) {
+	entry := vaultSampleEntry(t)
+	data, err := formatVaultFile(ExportCSV, []vaultEntry{entry})
+	if err != nil {
+		t.Fatalf("formatVaultFile(csv) 返回错误: %v", err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("解析CSV失败: %v\n%s", err, data)
+	}
+	if len(records) != 2 {
+		t.Fatalf("CSV行数 = %d，期望 2", len(records))
+	}
+	header, record := records[0], records[1]
+	if want := []string{"Title", "Website", "Username", "Password", "Notes"}; !reflect.DeepEqual(header[:5], want) {
+		t.Errorf("CSV标准列 = %v，期望 %v", header[:5], want)
+	}
+	if record[0] != vaultSample.SSID || record[3] != vaultSample.Password {
+		t.Errorf("CSV记录 = %q / %q，期望 %q / %q", record[0], record[3], vaultSample.SSID, vaultSample.Password)
+	}
+	for i, field := range entry.Fields {
+		if header[5+i] != field.Name || record[5+i] != field.Value {
+			t.Errorf("CSV第%d列 = %s: %q，期望 %s: %q", 6+i, header[5+i], record[5+i], field.Name, field.Value)
+		}
+	}
+}
+
+func TestFormatVaultFileUnsupported(t *testing.T) {
+	if _, err := formatVaultFile("lastpass", nil); err == nil {
+		t.Errorf("formatVaultFile 对不支持的格式应返回错误")
+	}
+}
@@ -0,0 +1,538 @@
+package wifi
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// WLAN配置文件XML的命名空间，MacRandomization元素位于v3命名空间
+const (
+	wlanProfileNamespace   = "http://www.microsoft.com/networking/WLAN/profile/v1"
+	wlanProfileNamespaceV3 = "http://www.microsoft.com/networking/WLAN/profile/v3"
+	oneXNamespace          = "http://www.microsoft.com/networking/OneX/v1"
+	eapHostConfigNamespace = "http://www.microsoft.com/provisioning/EapHostConfig"
+	eapCommonNamespace     = "http://www.microsoft.com/provisioning/EapCommon"
+)
+
+// WLANProfile 表示Windows WLAN配置文件XML（netsh wlan export profile 的导出格式）
+// 结构体标签不限定命名空间，可同时兼容v1/v2/v3命名空间下的元素
+type WLANProfile struct {
+	XMLName          xml.Name          `xml:"WLANProfile"`
+	Name             string            `xml:"name"`
+	SSIDConfig       SSIDConfig        `xml:"SSIDConfig"`
+	ConnectionType   string            `xml:"connectionType"`
+	ConnectionMode   string            `xml:"connectionMode"`
+	AutoSwitch       bool              `xml:"autoSwitch"`
+	MSM              MSM               `xml:"MSM"`
+	MacRandomization *MacRandomization `xml:"MacRandomization"`
+}
+
+// SSIDConfig 表示配置文件中的SSID设置
+type SSIDConfig struct {
+	SSID         []ProfileSSID `xml:"SSID"`
+	NonBroadcast bool          `xml:"nonBroadcast"`
+}
+
+// ProfileSSID 表示一个SSID，hex为SSID的十六进制编码
+type ProfileSSID struct {
+	Hex  string `xml:"hex"`
+	Name string `xml:"name"`
+}
+
+// MSM 表示配置文件中的媒体相关设置
+type MSM struct {
+	Security ProfileSecurity `xml:"security"`
+}
+
+// ProfileSecurity 表示配置文件中的安全设置
+type ProfileSecurity struct {
+	AuthEncryption AuthEncryption `xml:"authEncryption"`
+	SharedKey      *SharedKey     `xml:"sharedKey"`
+	OneX           *OneX          `xml:"OneX"`
+}
+
+// AuthEncryption 表示身份验证和加密方式
+type AuthEncryption struct {
+	Authentication string `xml:"authentication"`
+	Encryption     string `xml:"encryption"`
+	UseOneX        bool   `xml:"useOneX"`
+}
+
+// SharedKey 表示预共享密钥，protected为true时keyMaterial为加密后的数据
+type SharedKey struct {
+	KeyType     string `xml:"keyType"`
+	Protected   bool   `xml:"protected"`
+	KeyMaterial string `xml:"keyMaterial"`
+}
+
+// OneX 表示802.1X设置，EAP方法的具体配置由parseEAPConfigXML解析
+type OneX struct {
+	AuthMode  string     `xml:"authMode,omitempty"`
+	EAPConfig *EAPConfig `xml:"EAPConfig"`
+}
+
+// EAPConfig 表示OneX中的EAP配置
+type EAPConfig struct {
+	EapHostConfig EapHostConfig `xml:"EapHostConfig"`
+}
+
+// EapHostConfig 表示EapHost的方法选择和方法配置
+type EapHostConfig struct {
+	EapMethod EapMethod       `xml:"EapMethod"`
+	Config    EapMethodConfig `xml:"Config"`
+}
+
+// EapMethod 表示EAP方法编号，微软自带方法的厂商相关字段均为0
+type EapMethod struct {
+	Type       int `xml:"Type"`
+	VendorId   int `xml:"VendorId"`
+	VendorType int `xml:"VendorType"`
+	AuthorId   int `xml:"AuthorId"`
+}
+
+// EapMethodConfig 表示EAP方法的配置，各方法的元素和命名空间不同，按原始XML保存
+type EapMethodConfig struct {
+	InnerXML string `xml:",innerxml"`
+}
+
+// MacRandomization 表示MAC地址随机化设置（v3命名空间）
+type MacRandomization struct {
+	EnableRandomization bool `xml:"enableRandomization"`
+	RandomizeEveryday   bool `xml:"randomizeEveryday"`
+}
+
+// MarshalXML 使用v1命名空间输出WLANProfile元素
+func (p WLANProfile) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain WLANProfile
+	start.Name = xml.Name{Space: wlanProfileNamespace, Local: "WLANProfile"}
+	return e.EncodeElement(plain(p), start)
+}
+
+// MarshalXML 使用v3命名空间输出MacRandomization元素
+func (m MacRandomization) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain MacRandomization
+	start.Name = xml.Name{Space: wlanProfileNamespaceV3, Local: "MacRandomization"}
+	return e.EncodeElement(plain(m), start)
+}
+
+// MarshalXML 使用OneX命名空间输出OneX元素
+func (o OneX) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain OneX
+	start.Name = xml.Name{Space: oneXNamespace, Local: "OneX"}
+	return e.EncodeElement(plain(o), start)
+}
+
+// MarshalXML 使用EapHostConfig命名空间输出EapHostConfig元素
+func (h EapHostConfig) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain EapHostConfig
+	start.Name = xml.Name{Space: eapHostConfigNamespace, Local: "EapHostConfig"}
+	return e.EncodeElement(plain(h), start)
+}
+
+// MarshalXML 输出EapMethod元素，其中的各字段位于EapCommon命名空间
+func (m EapMethod) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	fields := []struct {
+		name  string
+		value int
+	}{
+		{"Type", m.Type},
+		{"VendorId", m.VendorId},
+		{"VendorType", m.VendorType},
+		{"AuthorId", m.AuthorId},
+	}
+	for _, field := range fields {
+		element := xml.StartElement{Name: xml.Name{Space: eapCommonNamespace, Local: field.name}}
+		if err := e.EncodeElement(field.value, element); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Marshal 生成WLAN配置文件XML，可通过 netsh wlan add profile 导入
+func (p *WLANProfile) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(p, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("生成WLAN配置文件失败: %v", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// ParseWLANProfile 解析WLAN配置文件XML
+func ParseWLANProfile(data []byte) (*WLANProfile, error) {
+	var profile WLANProfile
+	if err := xml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("解析WLAN配置文件失败: %v", err)
+	}
+	return &profile, nil
+}
+
+// SSIDName 返回配置文件中第一个SSID的名称，名称为空时使用hex解码
+func (p *WLANProfile) SSIDName() string {
+	for _, ssid := range p.SSIDConfig.SSID {
+		if ssid.Name != "" {
+			return ssid.Name
+		}
+		if decoded, err := hex.DecodeString(ssid.Hex); err == nil && len(decoded) > 0 {
+			return string(decoded)
+		}
+	}
+	return p.Name
+}
+
+// ToSavedWiFi 将配置文件转换为SavedWiFi记录
+func (p *WLANProfile) ToSavedWiFi() SavedWiFi {
+	security := p.MSM.Security
+	network := SavedWiFi{
+		SSID:           p.SSIDName(),
+		ProfileName:    p.Name,
+		Authentication: security.AuthEncryption.Authentication,
+		Cipher:         security.AuthEncryption.Encryption,
+		NonBroadcast:   p.SSIDConfig.NonBroadcast,
+		AutoSwitch:     p.AutoSwitch,
+		NetworkType:    p.ConnectionType,
+	}
+
+	switch strings.ToLower(p.ConnectionMode) {
+	case "auto":
+		network.ConnectionMode = ConnectionAuto
+	case "manual":
+		network.ConnectionMode = ConnectionManual
+	}
+
+	if p.MacRandomization != nil {
+		switch {
+		case p.MacRandomization.EnableRandomization && p.MacRandomization.RandomizeEveryday:
+			network.MACRandomization = "每天更改"
+		case p.MacRandomization.EnableRandomization:
+			network.MACRandomization = "启用"
+		default:
+			network.MACRandomization = "禁用"
+		}
+	}
+
+	switch {
+	case security.AuthEncryption.UseOneX || security.OneX != nil:
+		network.KeyStatus = KeyEnterprise
+		network.Enterprise = &EnterpriseConfig{}
+		if security.OneX != nil {
+			network.Enterprise.CredentialSource = security.OneX.AuthMode
+		}
+	case security.SharedKey == nil:
+		network.KeyStatus = KeyAbsent
+	case security.SharedKey.Protected:
+		// 受保护的密钥使用DPAPI加密，只能在原机器上解密
+		network.KeyStatus = KeyHidden
+		network.KeyType = security.SharedKey.KeyType
+	default:
+		network.KeyStatus = KeyPresent
+		network.KeyType = security.SharedKey.KeyType
+		network.Password = security.SharedKey.KeyMaterial
+	}
+
+	return network
+}
+
+// NewWLANProfile 根据SavedWiFi记录生成WLAN配置文件，是ToSavedWiFi的逆过程
+func NewWLANProfile(network SavedWiFi) (*WLANProfile, error) {
+	name := network.SSID
+	if name == "" {
+		name = network.ProfileName
+	}
+	if name == "" {
+		return nil, fmt.Errorf("SSID为空")
+	}
+
+	ssid := ProfileSSID{Hex: strings.ToUpper(hex.EncodeToString([]byte(name))), Name: name}
+	if !validXMLText(name) {
+		// 包含控制字符等的SSID只能以hex表示
+		ssid.Name, name = "", ssid.Hex
+	}
+	profile := &WLANProfile{
+		Name: name,
+		SSIDConfig: SSIDConfig{
+			SSID:         []ProfileSSID{ssid},
+			NonBroadcast: network.NonBroadcast,
+		},
+		ConnectionType: "ESS",
+		ConnectionMode: "auto",
+		AutoSwitch:     network.AutoSwitch,
+	}
+	if network.ConnectionMode == ConnectionManual {
+		profile.ConnectionMode = "manual"
+	}
+	if network.NetworkType == "adhoc" || strings.EqualFold(network.NetworkType, "IBSS") {
+		profile.ConnectionType = "IBSS"
+	}
+
+	switch macRandomizationMode(network.MACRandomization) {
+	case "random":
+		profile.MacRandomization = &MacRandomization{EnableRandomization: true, RandomizeEveryday: true}
+	case "stable":
+		profile.MacRandomization = &MacRandomization{EnableRandomization: true}
+	case "permanent":
+		profile.MacRandomization = &MacRandomization{}
+	}
+
+	auth := &profile.MSM.Security.AuthEncryption
+	switch network.AuthType() {
+	case AuthOpen:
+		auth.Authentication, auth.Encryption = "open", "none"
+	case AuthOWE:
+		auth.Authentication, auth.Encryption = "OWE", "AES"
+	case AuthWEP:
+		auth.Authentication, auth.Encryption = "open", "WEP"
+	case AuthWPAPSK:
+		// 只使用TKIP时保持WPA，否则按WPA2导出
+		cipher := strings.ToUpper(network.Cipher)
+		if strings.Contains(cipher, "TKIP") && !strings.Contains(cipher, "CCMP") && !strings.Contains(cipher, "AES") {
+			auth.Authentication, auth.Encryption = "WPAPSK", "TKIP"
+		} else {
+			auth.Authentication, auth.Encryption = "WPA2PSK", "AES"
+		}
+	case AuthWPA2PSK:
+		auth.Authentication, auth.Encryption = "WPA2PSK", "AES"
+	case AuthWPA3SAE:
+		auth.Authentication, auth.Encryption = "WPA3SAE", "AES"
+	case AuthWPAEAP, AuthWPA2EAP, AuthWPA3EAP:
+		if network.Enterprise == nil {
+			return nil, fmt.Errorf("缺少802.1X配置")
+		}
+		eapConfig, err := newEAPConfig(network.Enterprise)
+		if err != nil {
+			return nil, err
+		}
+		auth.Authentication, auth.Encryption, auth.UseOneX = "WPA2", "AES", true
+		if network.AuthType() == AuthWPA3EAP {
+			auth.Authentication = "WPA3ENT"
+		}
+		authMode := network.Enterprise.CredentialSource
+		if _, ok := oneXAuthModes[authMode]; !ok {
+			authMode = "machineOrUser"
+		}
+		profile.MSM.Security.OneX = &OneX{AuthMode: authMode, EAPConfig: eapConfig}
+	default:
+		return nil, fmt.Errorf("无法识别的身份验证方式: %s", network.Authentication)
+	}
+
+	if network.AuthType().IsPersonal() && network.HasPassword() {
+		keyType := "passPhrase"
+		if network.AuthType() == AuthWEP || guessKeyType(network.Password) == "networkKey" {
+			keyType = "networkKey"
+		}
+		profile.MSM.Security.SharedKey = &SharedKey{
+			KeyType:     keyType,
+			KeyMaterial: network.Password,
+		}
+	}
+	return profile, nil
+}
+
+// 配置文件各字段允许的取值
+var (
+	oneXAuthModes       = map[string]bool{"machineOrUser": true, "machine": true, "user": true, "guest": true}
+	profileAuthMethods  = map[string]bool{"open": true, "shared": true, "WPA": true, "WPAPSK": true, "WPA2": true, "WPA2PSK": true, "WPA3": true, "WPA3SAE": true, "WPA3ENT": true, "WPA3ENT192": true, "OWE": true}
+	profileEncryptions  = map[string]bool{"none": true, "WEP": true, "TKIP": true, "AES": true, "GCMP256": true}
+	profileEnterprises  = map[string]bool{"WPA": true, "WPA2": true, "WPA3": true, "WPA3ENT": true, "WPA3ENT192": true}
+	profilePersonalPSKs = map[string]bool{"WPAPSK": true, "WPA2PSK": true, "WPA3SAE": true}
+)
+
+// validXMLText 判断字符串是否只包含XML 1.0允许的字符，encoding/xml会将非法字符静默替换
+func validXMLText(value string) bool {
+	if !utf8.ValidString(value) {
+		return false
+	}
+	for _, r := range value {
+		if (r < 0x20 && r != '\t' && r != '\n' && r != '\r') || r == 0xFFFE || r == 0xFFFF {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate 检查配置文件是否符合WLANProfile架构的约束，避免netsh导入失败或生成错误的配置
+func (p *WLANProfile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("配置文件名称为空")
+	}
+	if !validXMLText(p.Name) {
+		return fmt.Errorf("配置文件名称包含XML不允许的字符")
+	}
+	if len(p.SSIDConfig.SSID) == 0 {
+		return fmt.Errorf("缺少SSID")
+	}
+	for _, ssid := range p.SSIDConfig.SSID {
+		if ssid.Hex == "" && ssid.Name == "" {
+			return fmt.Errorf("SSID为空")
+		}
+		if ssid.Hex != "" {
+			decoded, err := hex.DecodeString(ssid.Hex)
+			if err != nil {
+				return fmt.Errorf("SSID的hex编码无效: %s", ssid.Hex)
+			}
+			if ssid.Name != "" && string(decoded) != ssid.Name {
+				return fmt.Errorf("SSID的hex编码与名称不一致")
+			}
+			if len(decoded) > 32 {
+				return fmt.Errorf("SSID超过32字节")
+			}
+		} else if len(ssid.Name) > 32 {
+			return fmt.Errorf("SSID超过32字节")
+		}
+		if !validXMLText(ssid.Name) {
+			return fmt.Errorf("SSID包含XML不允许的字符，需要只使用hex")
+		}
+	}
+
+	switch p.ConnectionType {
+	case "ESS":
+	case "IBSS":
+		if p.ConnectionMode == "auto" {
+			return fmt.Errorf("IBSS网络只能手动连接")
+		}
+	default:
+		return fmt.Errorf("无效的连接类型: %s", p.ConnectionType)
+	}
+	if p.ConnectionMode != "" && p.ConnectionMode != "auto" && p.ConnectionMode != "manual" {
+		return fmt.Errorf("无效的连接模式: %s", p.ConnectionMode)
+	}
+
+	security := p.MSM.Security
+	auth := security.AuthEncryption
+	if !profileAuthMethods[auth.Authentication] {
+		return fmt.Errorf("无效的身份验证方式: %s", auth.Authentication)
+	}
+	if !profileEncryptions[auth.Encryption] {
+		return fmt.Errorf("无效的加密方式: %s", auth.Encryption)
+	}
+	switch {
+	case auth.Authentication == "open" && auth.Encryption != "none" && auth.Encryption != "WEP":
+		return fmt.Errorf("开放网络只能使用none或WEP加密")
+	case auth.Authentication == "shared" && auth.Encryption != "WEP":
+		return fmt.Errorf("shared身份验证只能使用WEP加密")
+	case (profileEnterprises[auth.Authentication] || profilePersonalPSKs[auth.Authentication]) && auth.Encryption != "AES" && auth.Encryption != "TKIP" && auth.Encryption != "GCMP256":
+		return fmt.Errorf("%s不能使用%s加密", auth.Authentication, auth.Encryption)
+	case profileEnterprises[auth.Authentication] && (!auth.UseOneX || security.OneX == nil):
+		return fmt.Errorf("%s需要802.1X配置", auth.Authentication)
+	case auth.UseOneX && security.OneX == nil:
+		return fmt.Errorf("useOneX为true但缺少OneX配置")
+	}
+	if security.OneX != nil && security.OneX.AuthMode != "" && !oneXAuthModes[security.OneX.AuthMode] {
+		return fmt.Errorf("无效的802.1X认证模式: %s", security.OneX.AuthMode)
+	}
+
+	if key := security.SharedKey; key != nil {
+		if auth.UseOneX {
+			return fmt.Errorf("802.1X网络不能包含预共享密钥")
+		}
+		if !validXMLText(key.KeyMaterial) {
+			return fmt.Errorf("密钥包含XML不允许的字符")
+		}
+		if !key.Protected {
+			if err := validateKeyMaterial(auth.Encryption, key.KeyType, key.KeyMaterial); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateKeyMaterial 检查预共享密钥的长度和格式
+func validateKeyMaterial(encryption string, keyType string, material string) error {
+	switch {
+	case encryption == "WEP":
+		switch {
+		case len(material) == 5 || len(material) == 13:
+		case (len(material) == 10 || len(material) == 26) && isHexString(material):
+		default:
+			return fmt.Errorf("WEP密钥必须为5或13个字符，或10或26位十六进制")
+		}
+	case keyType == "networkKey":
+		if len(material) != 64 || !isHexString(material) {
+			return fmt.Errorf("networkKey必须为64位十六进制")
+		}
+	case keyType == "passPhrase":
+		if n := len(material); n < 8 || n > 63 {
+			return fmt.Errorf("密码长度必须为8到63个字符，当前为%d", n)
+		}
+	default:
+		return fmt.Errorf("无效的密钥类型: %s", keyType)
+	}
+	return nil
+}
+
+// LoadProfileXML 从WLAN配置文件XML加载SavedWiFi记录，包括802.1X配置
+func LoadProfileXML(data []byte) (SavedWiFi, error) {
+	profile, err := ParseWLANProfile(data)
+	if err != nil {
+		return SavedWiFi{}, err
+	}
+	network := profile.ToSavedWiFi()
+
+	if network.IsEnterprise() {
+		config, err := parseEAPConfigXML(data)
+		if err != nil {
+			return SavedWiFi{}, err
+		}
+		if config != nil {
+			if config.CredentialSource == "" {
+				config.CredentialSource = network.Enterprise.CredentialSource
+			}
+			network.Enterprise = config
+		}
+	}
+	return network, nil
+}
+
+// LoadProfilesFromDir 从目录中加载所有导出的WLAN配置文件XML
+// 单个文件解析失败时输出警告并继续处理其他文件
+func LoadProfilesFromDir(dir string) ([]SavedWiFi, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s 不是目录", dir)
+	}
+
+	var files []string
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".xml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("遍历目录失败: %v", err)
+	}
+	sort.Strings(files)
+
+	var networks []SavedWiFi
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			warnf("读取 %s 失败: %v", file, err)
+			continue
+		}
+		network, err := LoadProfileXML(data)
+		if err != nil {
+			warnf("解析 %s 失败: %v", file, err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
@@ -0,0 +1,119 @@
+package wifi
+
+import (
+	"strings"
+	"testing"
+)
+
+// marshalAndParse 生成配置文件XML后重新解析，返回解析出的记录
+func marshalAndParse(t *testing.T, network SavedWiFi) SavedWiFi {
+	t.Helper()
+	profile, err := NewWLANProfile(network)
+	if err != nil {
+		t.Fatalf("NewWLANProfile(%q): %v", network.SSID, err)
+	}
+	if err := profile.Validate(); err != nil {
+		t.Fatalf("Validate(%q): %v", network.SSID, err)
+	}
+	data, err := profile.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseWLANProfile(data)
+	if err != nil {
+		t.Fatalf("ParseWLANProfile: %v\n%s", err, data)
+	}
+	return parsed.ToSavedWiFi()
+}
+
+func TestWLANProfileRoundTrip(t *testing.T) {
+	tests := []struct {
+		ssid     string
+		password string
+	}{
+		{"Tom & Jerry", "salt&pepper1"},
+		{"<script>", "a<b>c<d>e"},
+		{"CDATA]]>", "]]><![CDATA[x"},
+		{`Say "hi"`, `"quoted" pass`},
+		{"Joe's WiFi", "it's'a'key"},
+		{"办公室 5G", "中文密码12345"},
+	}
+	for _, test := range tests {
+		network := SavedWiFi{
+			SSID: test.ssid, Password: test.password, KeyStatus: KeyPresent,
+			Authentication: "WPA2-Personal", Cipher: "CCMP",
+		}
+		got := marshalAndParse(t, network)
+		if got.SSID != test.ssid || got.ProfileName != test.ssid || got.Password != test.password || got.KeyStatus != KeyPresent {
+			t.Errorf("往返后 SSID=%q 名称=%q 密码=%q 状态=%v，期望 SSID=%q 密码=%q",
+				got.SSID, got.ProfileName, got.Password, got.KeyStatus, test.ssid, test.password)
+		}
+		if got.AuthType() != AuthWPA2PSK || got.KeyType != "passPhrase" {
+			t.Errorf("%q: 身份验证=%s 密钥类型=%s", test.ssid, got.AuthType(), got.KeyType)
+		}
+	}
+}
+
+func TestWLANProfileHexOnlySSID(t *testing.T) {
+	ssid := "Lab\x01\x1fNet"
+	profile, err := NewWLANProfile(SavedWiFi{SSID: ssid, Authentication: "Open", KeyStatus: KeyAbsent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profile.SSIDConfig.SSID) != 1 || profile.SSIDConfig.SSID[0].Name != "" {
+		t.Fatalf("包含控制字符的SSID只应以hex表示: %+v", profile.SSIDConfig.SSID)
+	}
+	if profile.SSIDConfig.SSID[0].Hex != "4C6162011F4E6574" || profile.Name != "4C6162011F4E6574" {
+		t.Errorf("hex = %s，名称 = %s", profile.SSIDConfig.SSID[0].Hex, profile.Name)
+	}
+
+	got := marshalAndParse(t, SavedWiFi{SSID: ssid, Authentication: "Open", KeyStatus: KeyAbsent})
+	if got.SSID != ssid || got.KeyStatus != KeyAbsent {
+		t.Errorf("往返后 SSID=%q 状态=%v，期望 %q", got.SSID, got.KeyStatus, ssid)
+	}
+
+	// SSID元素中的名称包含控制字符时应拒绝，否则encoding/xml会静默替换
+	profile.SSIDConfig.SSID[0].Name = ssid
+	if err := profile.Validate(); err == nil {
+		t.Error("SSID名称包含控制字符时应校验失败")
+	}
+}
+
+func TestWLANProfileValidateKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyType  string
+		material string
+		valid    bool
+	}{
+		{"8个字符", "passPhrase", "12345678", true},
+		{"63个字符", "passPhrase", strings.Repeat("a", 63), true},
+		{"7个字符", "passPhrase", "1234567", false},
+		{"64个字符的密码", "passPhrase", strings.Repeat("a", 64), false},
+		{"空密码", "passPhrase", "", false},
+		{"64位十六进制", "networkKey", rawNetworkKey, true},
+		{"包含非十六进制字符", "networkKey", strings.Repeat("g", 64), false},
+		{"63位十六进制", "networkKey", rawNetworkKey[:63], false},
+		{"65位十六进制", "networkKey", rawNetworkKey + "0", false},
+		{"未知密钥类型", "psk", "12345678", false},
+	}
+	for _, test := range tests {
+		profile, err := NewWLANProfile(SavedWiFi{SSID: "HomeNet", Authentication: "WPA2-Personal", Cipher: "CCMP"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		profile.MSM.Security.SharedKey = &SharedKey{KeyType: test.keyType, KeyMaterial: test.material}
+		if err := profile.Validate(); (err == nil) != test.valid {
+			t.Errorf("%s: Validate() = %v，期望有效=%v", test.name, err, test.valid)
+		}
+	}
+
+	// NewWLANProfile按内容推断密钥类型，过长的密码同样被拒绝
+	profile, err := NewWLANProfile(SavedWiFi{SSID: "HomeNet", Password: strings.Repeat("x", 64), KeyStatus: KeyPresent, Authentication: "WPA2-Personal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := profile.Validate(); err == nil {
+		t.Error("64个非十六进制字符的密码应校验失败")
+	}
+}
@@ -0,0 +1,178 @@
+package wifi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wpaCliBackend Linux上扫描使用的系统命令
+const wpaCliBackend = "wpa_cli"
+
+// wpaScanWait 触发扫描后等待结果的时间，wpa_supplicant扫描所有信道通常需要数秒
+const wpaScanWait = 4 * time.Second
+
+// scanWpaCli 通过wpa_cli扫描附近的网络
+// scan_results只给出频率和标志，逐个读取bss获取Beacon中的信息元素，以得到信道宽度和中心信道
+func scanWpaCli() ([]WiFiNetwork, error) {
+	if output, err := exec.Command("wpa_cli", "scan").CombinedOutput(); err != nil {
+		logf("触发扫描失败: %v，使用已缓存的扫描结果: %s\n", err, strings.TrimSpace(string(output)))
+	} else {
+		time.Sleep(wpaScanWait)
+	}
+
+	output, err := exec.Command("wpa_cli", "scan_results").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("扫描WiFi网络失败: %v, 输出: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	var networks []WiFiNetwork
+	for _, bssid := range parseWpaScanResults(string(output)) {
+		bss, err := exec.Command("wpa_cli", "bss", bssid).CombinedOutput()
+		if err != nil {
+			warnf("读取 %s 的扫描信息失败: %v", bssid, err)
+			continue
+		}
+		if network, ok := parseWpaBSS(string(bss)); ok {
+			networks = append(networks, network)
+		}
+	}
+	if len(networks) == 0 {
+		warnf("未解析到任何网络信息")
+	}
+	return networks, nil
+}
+
+// wpaCliInterface 返回wpa_cli默认使用的网卡名称，获取失败时返回空字符串
+func wpaCliInterface() string {
+	output, err := exec.Command("wpa_cli", "ifname").CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	name := strings.TrimSpace(lines[len(lines)-1])
+	if strings.HasPrefix(name, "Selected interface") || strings.Contains(name, " ") {
+		return ""
+	}
+	return name
+}
+
+// parseWpaScanResults 解析wpa_cli scan_results的输出，返回各BSS的BSSID
+// 每行依次为BSSID、频率、信号强度、标志和SSID，以制表符分隔
+func parseWpaScanResults(output string) []string {
+	var bssids []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 4 || strings.Count(fields[0], ":") != 5 {
+			continue
+		}
+		bssids = append(bssids, fields[0])
+	}
+	return bssids
+}
+
+// parseWpaBSS 解析wpa_cli bss的输出
+// SSID优先取自信息元素中的原始字节，ssid行对不可打印字符做了转义
+func parseWpaBSS(output string) (WiFiNetwork, bool) {
+	var network WiFiNetwork
+	var ies []byte
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "bssid":
+			network.BSSID = value
+		case "freq":
+			network.SetFrequency(ParseFrequency(value))
+		case "level":
+			if level, err := strconv.Atoi(value); err == nil {
+				network.Signal = fmt.Sprintf("%d%%", dBmToQuality(level))
+			}
+		case "flags":
+			network.Security = wpaFlagsSecurity(value)
+		case "ssid":
+			network.SSID = value
+		case "ie":
+			ies, _ = hex.DecodeString(value)
+		}
+	}
+	if network.BSSID == "" {
+		return WiFiNetwork{}, false
+	}
+	if ssid, ok := ssidFromIEs(ies); ok {
+		network.SSID = ssid
+	}
+	if len(ies) > 0 {
+		network.ApplyBeaconIEs(ies)
+	}
+	return network, true
+}
+
+// ssidFromIEs 返回信息元素中的SSID，隐藏网络的SSID为空
+func ssidFromIEs(ies []byte) (string, bool) {
+	for len(ies) >= 2 {
+		id, length := ies[0], int(ies[1])
+		if len(ies) < 2+length {
+			break
+		}
+		if id == 0 {
+			return string(ies[2 : 2+length]), true
+		}
+		ies = ies[2+length:]
+	}
+	return "", false
+}
+
+// dBmToQuality 将信号强度（dBm）换算为与netsh一致的百分比，-100dBm为0%，-50dBm及以上为100%
+func dBmToQuality(level int) int {
+	return min(max(2*(level+100), 0), 100)
+}
+
+// wpaFlagsSecurity 将wpa_cli的标志（如 [WPA2-PSK-CCMP][ESS]）转换为安全类型，多种方式用"/"分隔
+// [OWE-TRANS]等标志只说明过渡模式的配对关系，不影响本BSS的安全类型
+func wpaFlagsSecurity(flags string) string {
+	var names []string
+	add := func(name string) {
+		for _, existing := range names {
+			if existing == name {
+				return
+			}
+		}
+		names = append(names, name)
+	}
+	for _, flag := range strings.Split(strings.ReplaceAll(flags, "]", ""), "[") {
+		parts := strings.Split(flag, "-")
+		switch proto := parts[0]; {
+		case proto == "WEP":
+			add("WEP")
+		case (proto == "WPA" || proto == "WPA2" || proto == "RSN") && len(parts) >= 2:
+			generation := "WPA2"
+			if proto == "WPA" {
+				generation = "WPA"
+			}
+			for _, mgmt := range strings.Split(parts[1], "+") {
+				switch {
+				case mgmt == "OWE":
+					add("OWE")
+				case strings.Contains(mgmt, "SAE"):
+					add("WPA3-Personal")
+				case strings.Contains(mgmt, "PSK"):
+					add(generation + "-Personal")
+				case mgmt == "EAP" && len(parts) >= 3 && parts[2] == "SUITE":
+					add("WPA3-Enterprise")
+				case strings.Contains(mgmt, "EAP"):
+					add(generation + "-Enterprise")
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		return "Open"
+	}
+	return strings.Join(names, "/")
+}
@@ -0,0 +1,108 @@
+package wifi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// wpaScanResultsSample wpa_cli scan_results的示例输出
+const wpaScanResultsSample = `Selected interface 'wlan0'
+bssid / frequency / signal level / flags / ssid
+aa:bb:cc:dd:ee:01	5180	-48	[WPA2-PSK-CCMP][ESS]	HomeNet
+aa:bb:cc:dd:ee:02	2437	-70	[WPA-PSK-TKIP][WPA2-PSK-CCMP][ESS]	Cafe
+`
+
+// wpaBSSSample wpa_cli bss的示例输出，信息元素包含SSID、HT Operation（次信道在上方）和VHT Operation（80MHz，中心信道42）
+const wpaBSSSample = `Selected interface 'wlan0'
+id=0
+bssid=aa:bb:cc:dd:ee:01
+freq=5180
+beacon_int=100
+capabilities=0x1511
+qual=0
+noise=-92
+level=-48
+tsf=0000012345678901
+age=3
+ie=0007486f6d654e65743d1624050000000000000000000000000000000000000000c005012a000000
+flags=[WPA2-PSK-CCMP][ESS]
+ssid=HomeNet
+snr=44
+`
+
+// wpaBSSEscapedSample SSID含不可打印字符时ssid行被转义，信息元素中保留原始字节
+const wpaBSSEscapedSample = `bssid=aa:bb:cc:dd:ee:02
+freq=2437
+level=-70
+ie=000443610165
+flags=[WPA-PSK-TKIP][WPA2-PSK-CCMP][ESS]
+ssid=Ca\x01e
+`
+
+func TestParseWpaScanResults(t *testing.T) {
+	want := []string{"aa:bb:cc:dd:ee:01", "aa:bb:cc:dd:ee:02"}
+	if got := parseWpaScanResults(wpaScanResultsSample); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWpaScanResults = %v，期望 %v", got, want)
+	}
+}
+
+func TestParseWpaBSS(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   WiFiNetwork
+	}{
+		{"5GHz 80MHz", wpaBSSSample, WiFiNetwork{
+			SSID: "HomeNet", BSSID: "aa:bb:cc:dd:ee:01", Signal: "100%", Channel: "36",
+			Security: "WPA2-Personal", Band: Band5G, ChannelWidth: 80, CenterChannel: 42,
+		}},
+		{"转义SSID", wpaBSSEscapedSample, WiFiNetwork{
+			SSID: "Ca\x01e", BSSID: "aa:bb:cc:dd:ee:02", Signal: "60%", Channel: "6",
+			Security: "WPA-Personal/WPA2-Personal", Band: Band2G, ChannelWidth: 20, CenterChannel: 6,
+		}},
+	}
+	for _, test := range tests {
+		got, ok := parseWpaBSS(test.output)
+		if !ok {
+			t.Errorf("%s: parseWpaBSS 解析失败", test.name)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: parseWpaBSS = %+v，期望 %+v", test.name, got, test.want)
+		}
+	}
+
+	if _, ok := parseWpaBSS("FAIL\n"); ok {
+		t.Errorf("parseWpaBSS 对无效输出应返回false")
+	}
+}
+
+func TestWpaFlagsSecurity(t *testing.T) {
+	tests := []struct {
+		flags string
+		want  string
+	}{
+		{"[ESS]", "Open"},
+		{"[WEP][ESS]", "WEP"},
+		{"[WPA2-PSK-CCMP][ESS]", "WPA2-Personal"},
+		{"[WPA2-PSK+SAE-CCMP][ESS]", "WPA2-Personal/WPA3-Personal"},
+		{"[RSN-SAE-CCMP][ESS]", "WPA3-Personal"},
+		{"[WPA2-EAP-CCMP][ESS]", "WPA2-Enterprise"},
+		{"[WPA2-EAP-SUITE-B-192-GCMP-256][ESS]", "WPA3-Enterprise"},
+		{"[WPA2-OWE-CCMP][ESS]", "OWE"},
+		{"[ESS][OWE-TRANS]", "Open"},
+	}
+	for _, test := range tests {
+		if got := wpaFlagsSecurity(test.flags); got != test.want {
+			t.Errorf("wpaFlagsSecurity(%q) = %q，期望 %q", test.flags, got, test.want)
+		}
+	}
+}
+
+func TestDBmToQuality(t *testing.T) {
+	for level, want := range map[int]int{-100: 0, -110: 0, -70: 60, -50: 100, -30: 100} {
+		if got := dBmToQuality(level); got != want {
+			t.Errorf("dBmToQuality(%d) = %d，期望 %d", level, got, want)
+		}
+	}
+}